@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/SqiSch/lpic-cli/internal/anki"
+	"github.com/SqiSch/lpic-cli/internal/database"
+	"github.com/SqiSch/lpic-cli/internal/repository"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// runExportAnki implements "client export-anki": it writes the questions of a
+// certification or testset as an Anki deck package, optionally only the ones
+// answered wrong or marked as important.
+func runExportAnki(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export-anki", flag.ExitOnError)
+	dbFile := fs.String("dbfile", "test.json", "Path or http(s) URL of the JSON database file containing certification sets")
+	keyFile := fs.String("keyFile", "", "File containing the AES key of an encrypted .enc database file. If empty $AES_KEY is used")
+	certID := fs.String("certId", "lpic1-101-500", "Id of the certification set to export")
+	testsetID := fs.String("testsetId", "", "Only export this test set. If empty all questions of the certification are exported")
+	onlyWrong := fs.Bool("onlyWrong", false, "Only export questions whose last answer was wrong")
+	onlyImportant := fs.Bool("onlyImportant", false, "Only export important questions. Together with -onlyWrong questions that are wrong or important are exported")
+	deckName := fs.String("deck", "", "Name of the Anki deck, subdecks are separated by ::. If empty it is derived from the certification and the filters")
+	output := fs.String("o", "", "File to write the package to. If empty <certId>.apkg is used")
+	profile := fs.String("profile", repository.DefaultProfile, "Profile whose progress is used for -onlyWrong, -onlyImportant and the tags")
+	state := addStateFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: client export-anki [options]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	certSet, err := database.LoadDatabaseFromFile(*dbFile, *certID, *keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certification set: %w", err)
+	}
+
+	rep, err := repository.Open(ctx, state.config(repository.Namespace{CertificationID: *certID, Profile: *profile}))
+	if err != nil {
+		return fmt.Errorf("failed to open state backend: %w", err)
+	}
+	questionStates, err := rep.GetAnsweredQuestions()
+	rep.Close()
+	if err != nil {
+		return fmt.Errorf("failed to load progress: %w", err)
+	}
+
+	questions, err := certSet.GetQuestionsForTestset(*testsetID, false, questionStates)
+	if err != nil {
+		return fmt.Errorf("failed to fetch questions: %w", err)
+	}
+	if *testsetID == "" {
+		sort.Slice(questions, func(i, j int) bool { return questions[i].ID < questions[j].ID })
+	}
+	if *onlyWrong || *onlyImportant {
+		questions = filterAnkiQuestions(questions, *onlyWrong, *onlyImportant)
+	}
+	if len(questions) == 0 {
+		return fmt.Errorf("no questions match the filters")
+	}
+
+	deck := anki.Deck{Name: *deckName}
+	if deck.Name == "" {
+		deck.Name = ankiDeckName(certSet, *testsetID, *onlyWrong, *onlyImportant)
+	}
+	for _, question := range questions {
+		deck.Notes = append(deck.Notes, anki.QuestionNote(certSet.CertificationID, question))
+	}
+
+	if *output == "" {
+		*output = certSet.CertificationID + ".apkg"
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := anki.WritePackage(f, deck); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write Anki package: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d questions to deck %q in %s\n", len(deck.Notes), deck.Name, *output)
+	return nil
+}
+
+func filterAnkiQuestions(questions []*types.Question, wrong, important bool) []*types.Question {
+	var filtered []*types.Question
+	for _, question := range questions {
+		if wrong && question.AnsweredState == types.AnsweredFalse || important && question.GetIsImportant() {
+			filtered = append(filtered, question)
+		}
+	}
+	return filtered
+}
+
+func ankiDeckName(certSet *types.CertificationSet, testsetID string, wrong, important bool) string {
+	name := certSet.CertificationName
+	if name == "" {
+		name = certSet.CertificationID
+	}
+	if testsetID != "" {
+		name += "::" + certSet.Testsets[testsetID].TestsetName
+	}
+	switch {
+	case wrong && important:
+		name += "::Wrong or important"
+	case wrong:
+		name += "::Wrong answers"
+	case important:
+		name += "::Important"
+	}
+	return name
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/authoring"
+	"github.com/SqiSch/lpic-cli/internal/database"
+	"github.com/SqiSch/lpic-cli/internal/dataset"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// runCompile implements "client compile": it compiles hand-written question
+// files into a dataset the client loads with -dbfile.
+func runCompile(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("compile", flag.ExitOnError)
+	output := fs.String("o", "-", "File to write the dataset to, - writes to stdout. Files ending in .enc are encrypted")
+	keyFile := fs.String("keyFile", "", "File containing the AES key used for .enc output. If empty $AES_KEY is used")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: client compile [options] <questions.yaml>...")
+		fmt.Fprintln(fs.Output(), "Every file holds one certification")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("expected at least one question file")
+	}
+
+	var certSets []*types.CertificationSet
+	var errs authoring.ErrorList
+	definedIn := make(map[string]string)
+	questions := 0
+	for _, file := range fs.Args() {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		certSet, err := authoring.Compile(file, data)
+		var list authoring.ErrorList
+		if errors.As(err, &list) {
+			errs = append(errs, list...)
+			continue
+		} else if err != nil {
+			return err
+		}
+		if other, ok := definedIn[certSet.CertificationID]; ok {
+			errs = append(errs, &authoring.Error{File: file, Message: fmt.Sprintf("certification %s is already defined in %s", certSet.CertificationID, other)})
+			continue
+		}
+		definedIn[certSet.CertificationID] = file
+		certSets = append(certSets, certSet)
+		questions += len(certSet.Questions)
+	}
+	if len(errs) > 0 {
+		fmt.Fprintln(os.Stderr, errs.Error())
+		return fmt.Errorf("%d error(s)", len(errs))
+	}
+
+	data, err := json.MarshalIndent(certSets, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := checkCompiled(data); err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(*output, database.EncryptedSuffix) {
+		key, err := database.LoadKey(*keyFile)
+		if err != nil {
+			return err
+		}
+		if data, err = database.EncryptDataset(data, key); err != nil {
+			return err
+		}
+	}
+	if *output == "-" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(*output, data, 0644)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Compiled %d questions of %d certification(s)\n", questions, len(certSets))
+	return nil
+}
+
+// checkCompiled validates a compiled dataset and reports its first error.
+// The compiler checks the same rules, so this only catches bugs in it.
+func checkCompiled(data []byte) error {
+	report, err := dataset.Validate(data)
+	if err != nil {
+		return err
+	}
+	for _, issue := range report.Issues {
+		if issue.Severity == dataset.SeverityError {
+			return fmt.Errorf("compiled dataset is invalid: %s: %s", issue.Path, issue.Message)
+		}
+	}
+	return nil
+}
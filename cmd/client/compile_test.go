@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/database"
+)
+
+func TestCompile(t *testing.T) {
+	questions := filepath.Join("..", "..", "internal", "authoring", "testdata", "valid.yaml")
+	output := filepath.Join(t.TempDir(), "team.json")
+
+	stderr := os.Stderr
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	os.Stderr = devNull
+	defer func() { os.Stderr = stderr }()
+
+	if err := runCompile(context.Background(), []string{"-o", output, questions}); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	certSets, err := database.LoadFullData(output, "")
+	if err != nil {
+		t.Fatalf("loading the compiled dataset: %v", err)
+	}
+	if len(certSets) != 1 || certSets[0].CertificationID != "lpic1-101-500" || len(certSets[0].Questions) != 4 {
+		t.Errorf("compiled dataset = %+v, want the 4 questions of lpic1-101-500", certSets)
+	}
+
+	if err := runCompile(context.Background(), []string{"-o", output, questions, questions}); err == nil {
+		t.Error("compiled the same certification twice")
+	}
+}
+
+func TestCheckCompiled(t *testing.T) {
+	// the testset ID mismatch of the first certification is a warning
+	// reported before the error of the second one
+	data := []byte(`[{
+		"CertificationID": "lpic1-101-500",
+		"CertificationName": "LPIC-1 Exam 101",
+		"Questions": {"1": {"ID": "1", "Text": "Which command lists the loaded kernel modules?",
+			"Answers": [{"Text": "lsmod", "IsCorrect": true, "AnswerID": "a"}, {"Text": "modprobe -l", "IsCorrect": false, "AnswerID": "b"}]}},
+		"Testsets": {"basics": {"TestsetID": "basic", "QuestionsIds": [1]}}
+	}, {
+		"CertificationID": "lpic1-102-500",
+		"CertificationName": "LPIC-1 Exam 102",
+		"Questions": {"1": {"ID": "1", "Text": "Which file configures the time zone?",
+			"Answers": [{"Text": "/etc/localtime", "IsCorrect": false, "AnswerID": "a"}, {"Text": "/etc/hosts", "IsCorrect": false, "AnswerID": "b"}]}},
+		"Testsets": {}
+	}]`)
+	err := checkCompiled(data)
+	if err == nil || !strings.Contains(err.Error(), "/1/Questions/1/Answers") {
+		t.Errorf("checkCompiled() = %v, want the error of the second certification", err)
+	}
+}
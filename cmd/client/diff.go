@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/database"
+	"github.com/SqiSch/lpic-cli/internal/dataset"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// Exit codes of "client diff", like diff(1).
+const (
+	diffExitDifferent = 1
+	diffExitFailure   = 2
+)
+
+type diffResult struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+	*dataset.Diff
+}
+
+// runDiff implements "client diff": it compares two datasets and reports the
+// added, removed and modified questions and the changed testsets. It exits
+// with 0 if they have the same content, 1 if not and 2 on failure.
+func runDiff(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := fs.String("format", "text", "Report format: text or json")
+	certID := fs.String("certId", "", "Only compare this certification. If empty all certifications are compared")
+	keyFile := fs.String("keyFile", "", "File containing the AES key of encrypted .enc datasets. If empty $AES_KEY is used")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: client diff [options] <old dataset> <new dataset>")
+		fmt.Fprintln(fs.Output(), "Exit codes: 0 same content, 1 different, 2 a dataset could not be loaded")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *format != "json" && *format != "text" {
+		return &exitError{code: diffExitFailure, err: fmt.Errorf("unknown format %q", *format)}
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return &exitError{code: diffExitFailure, err: fmt.Errorf("expected exactly two datasets")}
+	}
+
+	var sets [2][]*types.CertificationSet
+	for i, location := range fs.Args() {
+		certSets, err := database.LoadFullData(location, *keyFile)
+		if err != nil {
+			return &exitError{code: diffExitFailure, err: fmt.Errorf("%s: %w", location, err)}
+		}
+		if *certID != "" {
+			certSets = filterCertifications(certSets, *certID)
+		}
+		sets[i] = certSets
+	}
+
+	result := diffResult{Old: fs.Arg(0), New: fs.Arg(1), Diff: dataset.Compare(sets[0], sets[1])}
+	var err error
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(result)
+	} else {
+		err = printDiffText(os.Stdout, result)
+	}
+	if err != nil {
+		return &exitError{code: diffExitFailure, err: err}
+	}
+
+	if !result.Empty() {
+		return &exitError{code: diffExitDifferent, err: fmt.Errorf("%d certification(s) differ", len(result.Certifications))}
+	}
+	return nil
+}
+
+func filterCertifications(certSets []*types.CertificationSet, certID string) []*types.CertificationSet {
+	var filtered []*types.CertificationSet
+	for _, certSet := range certSets {
+		if certSet.CertificationID == certID {
+			filtered = append(filtered, certSet)
+		}
+	}
+	return filtered
+}
+
+func printDiffText(w io.Writer, result diffResult) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", result.Old, result.New)
+	if result.Empty() {
+		b.WriteString("No differences.\n")
+	}
+	for _, cert := range result.Certifications {
+		fmt.Fprintf(&b, "certification %s (%s): %d added, %d removed, %d modified questions, %d changed testsets\n",
+			cert.CertificationID, cert.Kind, cert.Count(dataset.ChangeAdded), cert.Count(dataset.ChangeRemoved),
+			cert.Count(dataset.ChangeModified), len(cert.Testsets))
+		for _, question := range cert.Questions {
+			writeQuestionChange(&b, question)
+		}
+		for _, testset := range cert.Testsets {
+			writeTestsetChange(&b, testset)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeQuestionChange(b *strings.Builder, change dataset.QuestionChange) {
+	switch change.Kind {
+	case dataset.ChangeAdded:
+		fmt.Fprintf(b, "  + question %d: %s\n", change.QuestionID, shorten(change.NewText))
+		return
+	case dataset.ChangeRemoved:
+		fmt.Fprintf(b, "  - question %d: %s\n", change.QuestionID, shorten(change.OldText))
+		return
+	}
+
+	fmt.Fprintf(b, "  ~ question %d\n", change.QuestionID)
+	if change.TextChanged() {
+		fmt.Fprintf(b, "      text: %s\n         -> %s\n", shorten(change.OldText), shorten(change.NewText))
+	}
+	if change.CorrectChanged {
+		fmt.Fprintf(b, "      correct answers: %s -> %s\n", formatAnswerIDs(change.OldCorrect), formatAnswerIDs(change.NewCorrect))
+	}
+	for _, answer := range change.Answers {
+		switch answer.Kind {
+		case dataset.ChangeAdded:
+			fmt.Fprintf(b, "      + answer %s: %s\n", answer.AnswerID, shorten(answer.NewText))
+		case dataset.ChangeRemoved:
+			fmt.Fprintf(b, "      - answer %s: %s\n", answer.AnswerID, shorten(answer.OldText))
+		default:
+			fmt.Fprintf(b, "      ~ answer %s: %s\n                -> %s\n", answer.AnswerID, shorten(answer.OldText), shorten(answer.NewText))
+		}
+	}
+	if change.ExplanationChanged() {
+		b.WriteString("      explanation changed\n")
+	}
+}
+
+func writeTestsetChange(b *strings.Builder, change dataset.TestsetChange) {
+	switch change.Kind {
+	case dataset.ChangeAdded:
+		fmt.Fprintf(b, "  + testset %s (%s): %d questions\n", change.TestsetID, change.NewName, len(change.AddedQuestions))
+		return
+	case dataset.ChangeRemoved:
+		fmt.Fprintf(b, "  - testset %s (%s): %d questions\n", change.TestsetID, change.OldName, len(change.RemovedQuestions))
+		return
+	}
+
+	fmt.Fprintf(b, "  ~ testset %s", change.TestsetID)
+	if change.OldName != change.NewName {
+		fmt.Fprintf(b, " renamed %q -> %q", change.OldName, change.NewName)
+	}
+	if len(change.AddedQuestions) > 0 {
+		fmt.Fprintf(b, " +%v", change.AddedQuestions)
+	}
+	if len(change.RemovedQuestions) > 0 {
+		fmt.Fprintf(b, " -%v", change.RemovedQuestions)
+	}
+	b.WriteString("\n")
+}
+
+func formatAnswerIDs(ids []string) string {
+	if len(ids) == 0 {
+		return "none"
+	}
+	return strings.Join(ids, ",")
+}
+
+// shorten returns the first line of text, cut to 70 characters.
+func shorten(text string) string {
+	text, _, _ = strings.Cut(strings.TrimSpace(text), "\n")
+	if runes := []rune(text); len(runes) > 70 {
+		return string(runes[:69]) + "…"
+	}
+	return text
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiffExitCodes(t *testing.T) {
+	fixture := func(name string) string {
+		return filepath.Join("..", "..", "internal", "dataset", "testdata", name)
+	}
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"same content", []string{fixture("valid.json"), fixture("valid.json")}, 0},
+		{"different", []string{fixture("valid.json"), fixture("no-correct-answer.json")}, diffExitDifferent},
+		{"json report", []string{"-format", "json", fixture("valid.json"), fixture("no-correct-answer.json")}, diffExitDifferent},
+		{"other certification", []string{"-certId", "lpic1-102-500", fixture("valid.json"), fixture("no-correct-answer.json")}, 0},
+		{"unreadable", []string{fixture("valid.json"), fixture("missing.json")}, diffExitFailure},
+		{"one dataset", []string{fixture("valid.json")}, diffExitFailure},
+		{"unknown format", []string{"-format", "yaml", fixture("valid.json"), fixture("valid.json")}, diffExitFailure},
+	}
+
+	stdout, stderr := os.Stdout, os.Stderr
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	os.Stdout, os.Stderr = devNull, devNull
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := 0
+			if err := runDiff(context.Background(), tt.args); err != nil {
+				code = exitCode(err)
+			}
+			if code != tt.code {
+				t.Errorf("exit code %d, want %d", code, tt.code)
+			}
+		})
+	}
+}
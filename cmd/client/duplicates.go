@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/database"
+	"github.com/SqiSch/lpic-cli/internal/duplicates"
+	"github.com/SqiSch/lpic-cli/internal/search"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+type duplicatesReport struct {
+	CertificationID string               `json:"certificationId"`
+	Questions       int                  `json:"questions"`
+	Clusters        []duplicates.Cluster `json:"clusters"`
+}
+
+// runDuplicates implements "client duplicates": it reports the clusters of
+// near-duplicate questions of every certification of a dataset.
+func runDuplicates(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("duplicates", flag.ExitOnError)
+	dbFile := fs.String("dbfile", "test.json", "Path or http(s) URL of the JSON database file containing certification sets")
+	keyFile := fs.String("keyFile", "", "File containing the AES key of an encrypted .enc database file. If empty $AES_KEY is used")
+	certID := fs.String("certId", "", "Only check this certification. If empty all certifications are checked")
+	threshold := fs.Float64("threshold", duplicates.DefaultThreshold, "Similarity (0-1) of the text and answers from which questions are near-duplicates")
+	format := fs.String("format", "text", "Report format: text or json")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: client duplicates [options]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *format != "json" && *format != "text" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if *threshold <= 0 || *threshold > 1 {
+		return fmt.Errorf("threshold must be in (0, 1], got %v", *threshold)
+	}
+
+	certSets, err := database.LoadFullData(*dbFile, *keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certification sets: %w", err)
+	}
+	if *certID != "" {
+		certSets = filterCertifications(certSets, *certID)
+		if len(certSets) == 0 {
+			return fmt.Errorf("certification %q not found", *certID)
+		}
+	}
+
+	reports := make([]duplicatesReport, 0, len(certSets))
+	for _, certSet := range certSets {
+		clusters := duplicates.Find(certSet, *threshold)
+		if clusters == nil {
+			clusters = make([]duplicates.Cluster, 0)
+		}
+		reports = append(reports, duplicatesReport{
+			CertificationID: certSet.CertificationID,
+			Questions:       len(certSet.Questions),
+			Clusters:        clusters,
+		})
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+	}
+	for i, report := range reports {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := printDuplicatesText(os.Stdout, report, certSets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printDuplicatesText(w io.Writer, report duplicatesReport, certSet *types.CertificationSet) error {
+	duplicated := 0
+	for _, cluster := range report.Clusters {
+		duplicated += len(cluster.IDs) - 1
+	}
+	fmt.Fprintf(w, "%s: %d questions, %d clusters of near-duplicates, %d redundant questions\n",
+		report.CertificationID, report.Questions, len(report.Clusters), duplicated)
+	for _, cluster := range report.Clusters {
+		fmt.Fprintf(w, "\n  similarity %.2f  testsets %s\n", cluster.Similarity, strings.Join(cluster.Testsets, ", "))
+		for _, id := range cluster.IDs {
+			fmt.Fprintf(w, "    %d  %s\n", id, search.Excerpt(certSet.Questions[id].Text, nil, 100))
+		}
+	}
+	return nil
+}
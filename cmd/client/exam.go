@@ -0,0 +1,359 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/SqiSch/lpic-cli/internal/exam"
+	"github.com/SqiSch/lpic-cli/internal/repository"
+	"github.com/SqiSch/lpic-cli/internal/types"
+	"github.com/SqiSch/lpic-cli/internal/views"
+)
+
+type examOptions struct {
+	questionCount int
+	timeLimit     time.Duration
+	seed          int64
+}
+
+// examRepository stores the graded attempt and its answers.
+type examRepository interface {
+	repository.ExamRepository
+	repository.HistoryRepository
+}
+
+type examMode int
+
+const (
+	examRunning examMode = iota
+	examConfirming
+	examFinished
+	examReviewing
+)
+
+// runExam runs a timed exam simulation. Correctness stays hidden until the
+// exam is submitted or the time runs out; the graded attempt is then stored
+// through the repository and shown as a report, from which wrong and skipped
+// questions can be reviewed. Every answered question is also recorded in the
+// answer history with the time spent on it.
+func runExam(ctx context.Context, rep examRepository, certSet *types.CertificationSet, opts examOptions) error {
+	seed := opts.seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	questions, err := exam.Draw(certSet, opts.questionCount, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		return fmt.Errorf("failed to draw exam questions: %w", err)
+	}
+	log.Printf("Exam drawn with seed %d: %d questions", seed, len(questions))
+
+	app := tview.NewApplication()
+	current := 0
+	mode := examRunning
+	finished := false
+	startedAt := time.Now()
+	deadline := startedAt.Add(opts.timeLimit)
+	done := make(chan struct{})
+	timeSpent := make(map[int]time.Duration, len(questions))
+	shownAt := startedAt
+
+	questionTextView := tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	explanationView := tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	questionView := views.NewQuestionsView(questions[current].Answers, questionTextView, explanationView)
+	questionView.SetBorder(true).SetTitle("Answers")
+	answerArea := views.NewAnswerArea(questionView, views.NewFillInView(explanationView), views.NewArrangeView(explanationView))
+	answerArea.SetHideCorrectness(true)
+	answerArea.SetQuestion(questions[current])
+
+	statusView := tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	statusView.SetBorder(true).SetTitle("Exam")
+
+	updateStatus := func() {
+		answered := 0
+		for _, question := range questions {
+			if question.HasAnswer() {
+				answered++
+			}
+		}
+		remaining := time.Until(deadline).Round(time.Second)
+		if remaining < 0 {
+			remaining = 0
+		}
+		timeColor := "yellow"
+		if remaining < 5*time.Minute {
+			timeColor = "red"
+		}
+		statusView.SetText(fmt.Sprintf("[%s::b]Time left: %s[-:-:-]\n\nQuestion %d / %d\nAnswered: %d\nUnanswered: %d\n\nx: submit exam\nq: abort exam",
+			timeColor, formatDuration(remaining), current+1, len(questions), answered, len(questions)-answered))
+	}
+	updateStatus()
+
+	flex := tview.NewFlex().
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(questionTextView, 0, 1, false).
+			AddItem(answerArea, 0, 2, false), 0, 2, true).
+		AddItem(statusView, 30, 1, false)
+
+	showQuestion := func(index int) {
+		if !finished {
+			timeSpent[questions[current].ID] += time.Since(shownAt)
+			shownAt = time.Now()
+		}
+		current = (index + len(questions)) % len(questions)
+		answerArea.SetQuestion(questions[current])
+		if mode == examFinished {
+			answerArea.ShowExplanation()
+			statusView.SetText(fmt.Sprintf("Question %d / %d\n\nn/p: navigate\nr: review list\no: report\nq: quit", current+1, len(questions)))
+			return
+		}
+		updateStatus()
+	}
+
+	answerArea.SetDoneFunc(func(answered bool) {
+		app.SetFocus(flex)
+		updateStatus()
+	})
+
+	var reportView *tview.TextView
+	reviewFlex := tview.NewFlex().
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(questionTextView, 0, 1, false).
+			AddItem(answerArea, 0, 2, false).
+			AddItem(explanationView, 0, 1, false), 0, 2, true).
+		AddItem(statusView, 30, 1, false)
+
+	showReview := func() {
+		mode = examReviewing
+		review := views.NewReviewView(questions)
+		review.SetJumpFunc(func(index int) {
+			mode = examFinished
+			showQuestion(index)
+			app.SetRoot(reviewFlex, true)
+		})
+		review.SetCloseFunc(func() {
+			mode = examFinished
+			app.SetRoot(reportView, true)
+		})
+		app.SetRoot(review, true)
+	}
+
+	submit := func(timedOut bool) {
+		timeSpent[questions[current].ID] += time.Since(shownAt)
+		mode = examFinished
+		finished = true
+		close(done)
+
+		result := exam.Score(certSet, questions, startedAt, time.Now())
+		result.TimeLimit = opts.timeLimit
+		result.TimedOut = timedOut
+		for _, question := range questions {
+			switch {
+			case question.IsAnsweredCorrectly():
+				question.SetAnsweredState(types.AnsweredTrue)
+			case question.HasAnswer():
+				question.SetAnsweredState(types.AnsweredFalse)
+			}
+		}
+		answerArea.SetHideCorrectness(false)
+
+		report := formatExamReport(result)
+		if err := rep.SaveExamResult(ctx, result); err != nil {
+			log.Printf("failed to save exam result: %v", err)
+			report += fmt.Sprintf("\n[red]Failed to save exam result: %v[-]\n", err)
+		}
+		for _, question := range questions {
+			if question.AnsweredState == types.AnsweredUnknown {
+				continue
+			}
+			event := types.NewAnswerEvent(question, certSet.CertificationID, result.ID, timeSpent[question.ID])
+			if err := rep.RecordAnswer(ctx, event); err != nil {
+				log.Printf("failed to record exam answer: %v", err)
+			}
+		}
+		report += "\nr: review wrong and skipped questions\nq: quit"
+
+		reportView = tview.NewTextView().SetDynamicColors(true).SetWrap(true).SetText(report)
+		reportView.SetBorder(true).SetTitle("Exam Report")
+		app.SetRoot(reportView, true)
+	}
+
+	confirm := func(text, action string, onConfirm func()) {
+		mode = examConfirming
+		modal := tview.NewModal().
+			SetText(text).
+			AddButtons([]string{"Cancel", action}).
+			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+				if buttonLabel == action {
+					onConfirm()
+					return
+				}
+				mode = examRunning
+				app.SetRoot(flex, true)
+			})
+		app.SetRoot(modal, false)
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				app.QueueUpdateDraw(func() {
+					if finished {
+						return
+					}
+					if time.Now().After(deadline) {
+						submit(true)
+						return
+					}
+					updateStatus()
+				})
+			}
+		}
+	}()
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch mode {
+		case examConfirming, examReviewing:
+			return event
+		case examFinished:
+			switch event.Key() {
+			case tcell.KeyUp:
+				questionView.DecreaseMarkerPosition()
+			case tcell.KeyDown:
+				questionView.IncreaseMarkerPosition()
+			case tcell.KeyLeft:
+				showQuestion(current - 1)
+			case tcell.KeyRight:
+				showQuestion(current + 1)
+			case tcell.KeyRune:
+				switch event.Rune() {
+				case 'q':
+					app.Stop()
+					return nil
+				case 'r':
+					showReview()
+					return nil
+				case 'o':
+					app.SetRoot(reportView, true)
+				case 'n':
+					showQuestion(current + 1)
+				case 'p':
+					showQuestion(current - 1)
+				}
+			}
+			return event
+		}
+
+		if answerArea.Editing() {
+			return event
+		}
+		if editor := answerArea.Editor(); editor != nil && (event.Key() == tcell.KeyEnter || event.Key() == tcell.KeyRune && event.Rune() == ' ') {
+			app.SetFocus(editor)
+			return nil
+		}
+		switch event.Key() {
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'q':
+				confirm("Abort the exam? The attempt will not be saved.", "Abort", func() {
+					close(done)
+					app.Stop()
+				})
+			case 'x':
+				unanswered := 0
+				for _, question := range questions {
+					if !question.HasAnswer() {
+						unanswered++
+					}
+				}
+				confirm(fmt.Sprintf("Submit the exam?\n%d question(s) unanswered.", unanswered), "Submit", func() {
+					submit(false)
+				})
+			case ' ':
+				questionView.ToggleCurrentMarkedOption()
+				updateStatus()
+			case 'n':
+				showQuestion(current + 1)
+			case 'p':
+				showQuestion(current - 1)
+			}
+		case tcell.KeyEnter:
+			questionView.ToggleCurrentMarkedOption()
+			updateStatus()
+		case tcell.KeyUp:
+			questionView.DecreaseMarkerPosition()
+		case tcell.KeyDown:
+			questionView.IncreaseMarkerPosition()
+		case tcell.KeyLeft:
+			showQuestion(current - 1)
+		case tcell.KeyRight:
+			showQuestion(current + 1)
+		}
+		return event
+	})
+
+	return app.SetRoot(flex, true).Run()
+}
+
+// formatExamReport renders a graded attempt with tview color tags.
+func formatExamReport(result *types.ExamResult) string {
+	var b strings.Builder
+
+	verdict := "[red::b]FAILED[-:-:-]"
+	if result.Passed {
+		verdict = "[green::b]PASSED[-:-:-]"
+	}
+	fmt.Fprintf(&b, "Certification: %s\n", result.CertificationID)
+	fmt.Fprintf(&b, "Score: [::b]%d[-:-:-] / %d (pass at %d)  %s\n", result.ScaledScore, exam.MaxScore, exam.PassScore, verdict)
+	fmt.Fprintf(&b, "Correct: %d / %d", result.Correct, result.Total)
+	if result.Points > float64(result.Correct) {
+		fmt.Fprintf(&b, " (%.1f points with partial credit)", result.Points)
+	}
+	b.WriteString("\n")
+	used := result.FinishedAt.Sub(result.StartedAt).Round(time.Second)
+	fmt.Fprintf(&b, "Time used: %s of %s", formatDuration(used), formatDuration(result.TimeLimit))
+	if result.TimedOut {
+		b.WriteString(" [red](time ran out)[-]")
+	}
+	b.WriteString("\n\nPer testset:\n")
+	for _, testset := range result.Testsets {
+		pct := float64(testset.Correct) / float64(testset.Total) * 100
+		fmt.Fprintf(&b, "  %-20s %3d / %-3d %5.1f%%\n", testset.TestsetID, testset.Correct, testset.Total, pct)
+	}
+	return b.String()
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// printExamResults lists all stored exam attempts of the repository's namespace.
+func printExamResults(ctx context.Context, rep repository.ExamRepository) error {
+	results, err := rep.GetExamResults(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Exam attempts:")
+	for _, result := range results {
+		verdict := "failed"
+		if result.Passed {
+			verdict = "passed"
+		}
+		fmt.Printf("%s  score %d (%s)  correct %d/%d  time %s\n",
+			result.StartedAt.Format("2006-01-02 15:04"), result.ScaledScore, verdict, result.Correct, result.Total,
+			formatDuration(result.FinishedAt.Sub(result.StartedAt)))
+	}
+	return nil
+}
@@ -2,17 +2,26 @@ package main
 
 import (
     "context"
+    "errors"
     "flag"
     "fmt"
     "io/ioutil"
     "log"
     "os"
+    "slices"
+    "strconv"
+    "strings"
+    "time"
 
     "github.com/gdamore/tcell/v2"
     "github.com/rivo/tview"
 
     "github.com/SqiSch/lpic-cli/internal/database"
+    "github.com/SqiSch/lpic-cli/internal/duplicates"
+    "github.com/SqiSch/lpic-cli/internal/exam"
+    "github.com/SqiSch/lpic-cli/internal/objectives"
     "github.com/SqiSch/lpic-cli/internal/repository"
+    "github.com/SqiSch/lpic-cli/internal/search"
     "github.com/SqiSch/lpic-cli/internal/types"
     "github.com/SqiSch/lpic-cli/internal/views"
 )
@@ -32,29 +41,79 @@ func fetchQuestionByIndex(questions []*types.Question, index int) (*types.Questi
 	return questions[questionID], nil
 }
 
+// subcommands are run as "client <name> [options]" instead of the trainer.
+var subcommands = map[string]func(ctx context.Context, args []string) error{
+	"export-progress": runExportProgress,
+	"import-progress": runImportProgress,
+	"validate":        runValidate,
+	"diff":            runDiff,
+	"compile":         runCompile,
+	"export-anki":     runExportAnki,
+	"export-exam":     runExportExam,
+	"search":          runSearch,
+	"duplicates":      runDuplicates,
+}
+
 func main() {
 	ctx := context.Background()
 
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(ctx, os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+				os.Exit(exitCode(err))
+			}
+			return
+		}
+	}
+
 	// Add a flag for the database filename
-	dbFile := flag.String("dbfile", "test.json", "Path to the JSON database file containing certification sets")
+	dbFile := flag.String("dbfile", "test.json", "Path or http(s) URL of the JSON database file containing certification sets. Files ending in .enc are decrypted in memory")
+	keyFile := flag.String("keyFile", "", "File containing the AES key of an encrypted .enc database file. If empty $AES_KEY is used")
 	certID := flag.String("certId", "lpic1-101-500", "Id of the certification set to load from the json file")
 	testSetId := flag.String("testsetId", "admin_1", "Id of the test set to load from the json file")
 	listCerts := flag.Bool("listCerts", false, "List all available certifications in the json file")
 	listTestSets := flag.Bool("listTestSets", false, "List all available test sets in the json file")
 	filterCorrect := flag.Bool("filterCorrect", false, "Filter correct answers")
 	onlyImportant := flag.Bool("onlyImportant", false, "Only show important questions")
+	groupDuplicates := flag.Bool("groupDuplicates", false, "Count near-duplicate questions of different test sets as one question in the statistics")
+	tagFilter := flag.String("tag", "", "Only show questions with one of these comma separated personal tags")
 	withLogfile := flag.Bool("withLogfile", false, "Enable logging to a file in /tmp/lpic-learner.log")
 	help := flag.Bool("help", false, "Show help")
 	h := flag.Bool("h", false, "Show help")
 	randomQuestions := flag.Bool("randomQuestions", false, "Fetch random questions from the certification set instead of a specific test set")
-	stateDir := flag.String("stateDir", "", "Directory to store persistent state (.nutsdb). If empty defaults to $HOME/.nutsdb")
+	objective := flag.String("objective", "", "Study the questions of one exam objective (e.g. 103.7) or topic (e.g. 103) across all test sets")
+	listObjectives := flag.Bool("listObjectives", false, "List the exam objectives of the certification with their number of questions")
+	state := addStateFlags(flag.CommandLine)
+	showProgress := flag.Bool("showProgress", false, "Print a progress summary and the answers per day of the certification")
+	examMode := flag.Bool("exam", false, "Run a timed exam simulation with questions drawn from the whole certification set")
+	examQuestions := flag.Int("examQuestions", exam.DefaultQuestionCount, "Number of questions drawn for an exam")
+	examDuration := flag.Duration("examDuration", exam.DefaultTimeLimit, "Time limit of an exam")
+	seed := flag.Int64("seed", 0, "Seed for drawing exam questions. 0 uses a random seed")
+	listExams := flag.Bool("listExams", false, "List all stored exam attempts of the certification")
+	reviewMode := flag.Bool("review", false, "Only serve questions that are due for spaced-repetition review, most overdue first")
+	reviewLimit := flag.Int("reviewLimit", 0, "Maximum number of due questions served with -review. 0 serves all")
+	resume := flag.Bool("resume", false, "Continue the most recent session of the certification where it was left")
+	profile := flag.String("profile", repository.DefaultProfile, "Profile name used to keep the progress of several users apart")
 	flag.Parse()
 
 	if *help || *h {
 		fmt.Println("Usage: lpic-learner [options]")
+		fmt.Println("       lpic-learner export-progress [options]")
+		fmt.Println("       lpic-learner import-progress [options] <file>")
+		fmt.Println("       lpic-learner validate [options] <dataset>...")
+		fmt.Println("       lpic-learner diff [options] <old dataset> <new dataset>")
+		fmt.Println("       lpic-learner compile [options] <questions.yaml>...")
+		fmt.Println("       lpic-learner export-anki [options]")
+		fmt.Println("       lpic-learner export-exam [options]")
+		fmt.Println("       lpic-learner search [options] <term>...")
+		fmt.Println("       lpic-learner duplicates [options]")
 		fmt.Println("Options:")
 		fmt.Println("  -dbfile string")
-		fmt.Println("        Path to the JSON database file containing certification sets (default \"test.json\")")
+		fmt.Println("        Path or http(s) URL of the JSON database file containing certification sets (default \"test.json\")")
+		fmt.Println("        Files ending in .enc are decrypted in memory, the plaintext is never written to disk")
+		fmt.Println("  -keyFile string")
+		fmt.Println("        File containing the AES key of an encrypted .enc database file. If empty $AES_KEY is used")
 		fmt.Println("  -certId string")
 		fmt.Println("        Id of the certification set to load from the json file (default \"lpic1-101-500\")")
 		fmt.Println("  -testsetId string")
@@ -66,10 +125,60 @@ func main() {
 		fmt.Println("  -randomQuestions")
 		fmt.Println("        Fetch random questions from the certification set instead of a specific test set")
 		fmt.Println("        If this option is set, the -testsetId option is ignored")
+		fmt.Println("  -objective string")
+		fmt.Println("        Study the questions of one exam objective (e.g. 103.7) or topic (e.g. 103) across all test sets")
+		fmt.Println("        Questions without objectives in the json file are classified by the commands they mention")
+		fmt.Println("  -listObjectives")
+		fmt.Println("        List the exam objectives of the certification with their number of questions")
 		fmt.Println("  -filterCorrect")
 		fmt.Println("        Filter correct answers")
 		fmt.Println("  -onlyImportant")
 		fmt.Println("        Only show important questions")
+		fmt.Println("  -groupDuplicates")
+		fmt.Println("        Count near-duplicate questions of different test sets as one question in the statistics")
+		fmt.Println("        A group is answered wrong if one of its questions was answered wrong, see the duplicates command")
+		fmt.Println("  -tag string")
+		fmt.Println("        Only show questions with one of these comma separated personal tags")
+		fmt.Println("        Tags and a note are added to the current question with o, or O to edit the note in $EDITOR")
+		fmt.Println("  -review")
+		fmt.Println("        Only serve questions that are due for spaced-repetition review, most overdue first")
+		fmt.Println("        Correct answers push the next review out, wrong answers make it due again tomorrow")
+		fmt.Println("  -reviewLimit int")
+		fmt.Println("        Maximum number of due questions served with -review. 0 serves all")
+		fmt.Println("  -resume")
+		fmt.Println("        Continue the most recent session of the certification and profile where it was left")
+		fmt.Println("        The testset, filters and question order of that session are used instead of the options")
+		fmt.Println("  -exam")
+		fmt.Println("        Run a timed exam simulation with questions drawn from the whole certification set")
+		fmt.Println("        Correctness is hidden until the exam is submitted (x) or the time runs out")
+		fmt.Println("  -examQuestions int")
+		fmt.Println("        Number of questions drawn for an exam (default 60)")
+		fmt.Println("  -examDuration duration")
+		fmt.Println("        Time limit of an exam (default 1h30m0s)")
+		fmt.Println("  -seed int")
+		fmt.Println("        Seed for drawing exam questions. 0 uses a random seed")
+		fmt.Println("  -listExams")
+		fmt.Println("        List all stored exam attempts of the certification")
+		fmt.Println("  -stateDir string")
+		fmt.Println("        Directory to store persistent state. If empty defaults to $HOME")
+		fmt.Println("  -stateBackend string")
+		fmt.Println("        Backend used to store progress: nuts, sqlite, mongo or memory (default \"nuts\")")
+		fmt.Println("        sqlite stores everything in <stateDir>/.lpic-cli.sqlite, which can be inspected with any SQLite tool")
+		fmt.Println("        mongo stores the progress of every -profile in a shared MongoDB")
+		fmt.Println("        memory keeps the progress only until the client exits")
+		fmt.Println("  -mongoURI string")
+		fmt.Println("        MongoDB URI of the mongo state backend. If empty $MONGO_URI or mongodb://localhost:27017 is used")
+		fmt.Println("        $MONGO_USER and $MONGO_PASSWORD are added to the localhost URI when set")
+		fmt.Println("  -mongoDatabase string")
+		fmt.Println("        MongoDB database of the mongo state backend (default \"lpicProgress\")")
+		fmt.Println("  -mongoCollection string")
+		fmt.Println("        MongoDB collection of the mongo state backend (default \"progress\")")
+		fmt.Println("        Exam results, the answer history and sessions use the suffixes _exams, _events and _sessions")
+		fmt.Println("  -showProgress")
+		fmt.Println("        Print a progress summary and the answers per day of the certification")
+		fmt.Println("  -profile string")
+		fmt.Println("        Profile name used to keep the progress of several users apart (default \"default\")")
+		fmt.Println("        Progress is always stored per certification and profile")
 		fmt.Println("  -withLogfile")
 		fmt.Println("        Enable logging to a file in /tmp/lpic-learner.log")
 		fmt.Println("        If this option is set, the log file is created in /tmp/lpic-learner.log")
@@ -78,7 +187,21 @@ func main() {
 		fmt.Println("Examples:")
 		fmt.Println("  lpic-learner -dbfile test.json -certId lpic1-101-500 -testsetId admin_1")
 		fmt.Println("  lpic-learner -listCerts")
+		fmt.Println("  AES_KEY=... lpic-learner -dbfile https://example.com/output.json.enc -certId lpic1-101-500")
 		fmt.Println("  lpic-learner -listTestSets -certId lpic1-101-500")
+		fmt.Println("  lpic-learner -dbfile test.json -certId lpic1-101-500 -objective 103.7 -filterCorrect")
+		fmt.Println("  lpic-learner -dbfile test.json -certId lpic1-101-500 -randomQuestions -tag regex,exam-trap")
+		fmt.Println("  lpic-learner -dbfile test.json -certId lpic1-101-500 -exam -examQuestions 60 -examDuration 90m")
+		fmt.Println("  lpic-learner -dbfile test.json -certId lpic1-101-500 -resume")
+		fmt.Println("  lpic-learner export-progress -o progress.json")
+		fmt.Println("  lpic-learner import-progress -dryRun progress.json")
+		fmt.Println("  lpic-learner validate -format text test.json")
+		fmt.Println("  lpic-learner diff old.json.enc new.json.enc")
+		fmt.Println("  lpic-learner compile -o team.json questions/*.yaml")
+		fmt.Println("  lpic-learner export-anki -dbfile test.json -certId lpic1-101-500 -onlyWrong -o wrong.apkg")
+		fmt.Println("  lpic-learner export-exam -dbfile test.json -certId lpic1-101-500 -randomQuestions -questions 60 -seed 42 -o exam.html")
+		fmt.Println("  lpic-learner duplicates -dbfile test.json -certId lpic1-101-500 -threshold 0.6")
+		fmt.Println("  lpic-learner search -dbfile test.json -certId lpic1-101-500 -format json umask")
 		fmt.Println("  lpic-learner--dbfile=test.json --certId=lpic1-101-500 --testsetId=admin_1 --filterCorrect")
 		return
 	}
@@ -98,19 +221,35 @@ func main() {
 		log.SetFlags(0)
 	}
 
-	// Initialize repository only after we know the stateDir flag
-	rep := repository.NewNutsQuestionRepositoryWithDir(*stateDir)
+	// Initialize repository only after we know the stateDir flag. Only the
+	// trainer moves the progress of older versions into the certification.
+	cfg := state.config(repository.Namespace{CertificationID: *certID, Profile: *profile})
+	cfg.MigrateLegacy = !(*listCerts || *listTestSets || *listObjectives || *showProgress || *listExams || *examMode)
+	rep, err := repository.Open(ctx, cfg)
+	if err != nil {
+		log.Fatalf("failed to open state backend: %v", err)
+	}
+	defer rep.Close()
 
-	certSet, err := database.LoadDatabaseFromFile(*dbFile, *certID)
+	certSet, err := database.LoadDatabaseFromFile(*dbFile, *certID, *keyFile)
 	if err != nil {
 		log.Fatalf("failed to load certification set: %v", err)
 	}
 
 	log.Printf("Loaded certification set: %s (%s) %d questions \n", certSet.CertificationName, certSet.CertificationID, len(certSet.Questions))
+	classified := objectives.Assign(certSet)
+	log.Printf("Classified %d questions without objectives\n", classified)
+	index := search.NewIndex(certSet)
+	log.Printf("Indexed %d words for the search\n", index.Len())
+	if *groupDuplicates {
+		clusters := duplicates.Find(certSet, duplicates.DefaultThreshold)
+		marked := duplicates.Assign(certSet, clusters)
+		log.Printf("Found %d clusters of near-duplicates, counting %d questions as duplicates\n", len(clusters), marked)
+	}
 
 	if *listCerts {
 		fmt.Println("Available certifications:")
-		certs, err := database.LoadFullData(*dbFile)
+		certs, err := database.LoadFullData(*dbFile, *keyFile)
 		if err != nil {
 			log.Fatalf("failed to load certification sets: %v", err)
 		}
@@ -125,7 +264,7 @@ func main() {
 		if certID == nil {
 			log.Fatalf("certID is required to list test sets")
 		}
-		certSet, err := database.LoadDatabaseFromFile(*dbFile, *certID)
+		certSet, err := database.LoadDatabaseFromFile(*dbFile, *certID, *keyFile)
 		if err != nil {
 			log.Fatalf("failed to load certification set: %v", err)
 		}
@@ -135,6 +274,33 @@ func main() {
 		return
 	}
 
+	if *listObjectives {
+		printObjectives(certSet)
+		return
+	}
+
+	if *showProgress {
+		if err := printProgress(ctx, rep, certSet, *groupDuplicates); err != nil {
+			log.Fatalf("failed to load progress: %v", err)
+		}
+		return
+	}
+
+	if *listExams {
+		if err := printExamResults(ctx, rep); err != nil {
+			log.Fatalf("failed to load exam results: %v", err)
+		}
+		return
+	}
+
+	if *examMode {
+		opts := examOptions{questionCount: *examQuestions, timeLimit: *examDuration, seed: *seed}
+		if err := runExam(ctx, rep, certSet, opts); err != nil {
+			log.Fatalf("exam failed: %v", err)
+		}
+		return
+	}
+
 	// Get the state of answered questions
 	formerQuestionStates, err := rep.GetAnsweredQuestions()
 	if err != nil {
@@ -143,8 +309,70 @@ func main() {
 
 	app := tview.NewApplication()
 
+	var resumed *types.SessionState
+	if *resume {
+		resumed, err = rep.GetLatestSession(ctx)
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Println("No session to resume, starting a new one")
+			resumed = nil
+		} else if err != nil {
+			log.Fatalf("failed to load the last session: %v", err)
+		}
+	}
+
 	var questions []*types.Question
-	if *randomQuestions {
+	if resumed != nil {
+		questions = restoreSession(certSet, resumed, formerQuestionStates)
+		if len(questions) == 0 {
+			fmt.Println("The last session has no questions left.")
+			return
+		}
+		testSet := sessionTestset(certSet, resumed, questions)
+		certSet.Testsets[testSet.TestsetID] = testSet
+		testSetId = &testSet.TestsetID
+	} else if *reviewMode {
+		questions = certSet.GetDueQuestions(formerQuestionStates, time.Now())
+		if len(questions) == 0 {
+			fmt.Println("No questions are due for review.")
+			return
+		}
+		if *reviewLimit > 0 && len(questions) > *reviewLimit {
+			questions = questions[:*reviewLimit]
+		}
+
+		// create a fake testset for the due questions
+		testSet := types.Testset{
+			TestsetID:    "review",
+			TestsetName:  "Due for Review",
+			QuestionsIds: make([]int, 0),
+		}
+		for _, question := range questions {
+			testSet.QuestionsIds = append(testSet.QuestionsIds, question.ID)
+		}
+		certSet.Testsets["review"] = testSet
+		testSetId = &testSet.TestsetID
+	} else if *objective != "" {
+		questions, err = objectiveQuestions(certSet, *objective, *filterCorrect, formerQuestionStates)
+		if err != nil {
+			log.Fatalf("failed to fetch question: %v", err)
+		}
+		if len(questions) == 0 {
+			fmt.Printf("No questions for objective %s, see -listObjectives.\n", *objective)
+			return
+		}
+
+		// create a fake testset for the questions of the objective
+		testSet := types.Testset{
+			TestsetID:    "objective",
+			TestsetName:  objectives.Title(*objective),
+			QuestionsIds: make([]int, 0),
+		}
+		for _, question := range questions {
+			testSet.QuestionsIds = append(testSet.QuestionsIds, question.ID)
+		}
+		certSet.Testsets["objective"] = testSet
+		testSetId = &testSet.TestsetID
+	} else if *randomQuestions {
 		questions, err = certSet.GetQuestionsForTestset("", *filterCorrect, formerQuestionStates)
 		if err != nil {
 			log.Fatalf("failed to fetch question: %v", err)
@@ -168,7 +396,7 @@ func main() {
 		}
 	}
 
-	if *onlyImportant {
+	if *onlyImportant && resumed == nil {
 		// Filter only important questions
 		filteredQuestions := make([]*types.Question, 0)
 		for _, question := range questions {
@@ -183,21 +411,72 @@ func main() {
 		questions = filteredQuestions
 	}
 
+	tags := types.ParseTags(*tagFilter)
+	if len(tags) > 0 && resumed == nil {
+		questions = filterTagged(questions, tags)
+		if len(questions) == 0 {
+			fmt.Printf("No questions are tagged with %s.\n", strings.Join(tags, " or "))
+			return
+		}
+	}
+
 	testset := certSet.Testsets[*testSetId]
 	session := types.NewCertificationSession(&testset)
+	sessionID := strconv.FormatInt(time.Now().UnixNano(), 36)
+
+	// the session is stored on every move, so -resume continues where it was left
+	sessionState := types.SessionState{
+		ID:              sessionID,
+		CertificationID: certSet.CertificationID,
+		Mode:            types.SessionModeTestset,
+		TestsetID:       *testSetId,
+		FilterCorrect:   *filterCorrect,
+		OnlyImportant:   *onlyImportant,
+		Tags:            tags,
+		StartedAt:       time.Now(),
+	}
+	if *reviewMode {
+		sessionState.Mode = types.SessionModeReview
+	} else if *objective != "" {
+		sessionState.Mode = types.SessionModeObjective
+		sessionState.Objective = *objective
+	} else if *randomQuestions {
+		sessionState.Mode = types.SessionModeRandom
+	}
+	startIndex := session.GetFirst()
+	if resumed != nil {
+		sessionState = *resumed
+		startIndex = session.SetCurrentQuestionIndex(resumed.CurrentQuestionIndex)
+	}
+	sessionState.QuestionIDs = make([]int, 0, len(questions))
+	for _, question := range questions {
+		sessionState.QuestionIDs = append(sessionState.QuestionIDs, question.ID)
+	}
+	saveSession := func() {
+		sessionState.CurrentQuestionIndex = session.GetCurrentQuestionIndex()
+		sessionState.UpdatedAt = time.Now()
+		if err := rep.SaveSession(ctx, sessionState); err != nil {
+			log.Printf("failed to save session: %v", err)
+		}
+	}
+	saveSession()
 
-	question, err := fetchQuestionByIndex(questions, session.GetFirst())
+	question, err := fetchQuestionByIndex(questions, startIndex)
 	if err != nil {
 		log.Fatalf("failed to fetch question: %v", err)
 	}
 
 	questionTextView := tview.NewTextView().SetText(question.Text).SetDynamicColors(true)
 	explainationView := tview.NewTextView().SetText("").SetDynamicColors(true).SetWrap(true)
+	notesView := views.NewNotesView()
+	notesView.SetQuestion(question)
 
 	// Main question/answers/explanation area
 	questionView := views.NewQuestionsView(question.Answers, questionTextView, explainationView)
 	questionView.SetBorder(true).SetTitle("Answers")
-	questionView.SetQuestion(question)
+	answerArea := views.NewAnswerArea(questionView, views.NewFillInView(explainationView), views.NewArrangeView(explainationView))
+	answerArea.SetQuestion(question)
+	shownAt := time.Now()
 
 	frame2 := tview.NewFlex().SetDirection(tview.FlexRow)
 	frame2.AddItem(tview.NewButton("Explain"), 1, 0, false)
@@ -219,56 +498,157 @@ func main() {
 	flex.AddItem(tview.NewBox().SetBorder(true).SetTitle("XXYYZZ"), 1, 1, false).
 		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
 			AddItem(questionTextView, 0, 1, false).
-			AddItem(questionView, 0, 2, true).
-			AddItem(explainationView, 0, 1, false), 0, 2, false).
+			AddItem(answerArea, 0, 2, true).
+			AddItem(explainationView, 0, 1, false).
+			AddItem(notesView, 6, 0, false), 0, 2, false).
 		AddItem(statsAndBar, 24, 1, false)
 
 	modal := tview.NewModal()
 
+	// Marking options of a choice question only changes the question; the
+	// answer is recorded once when the question is left, so toggling towards
+	// the right answer does not count as a wrong attempt.
+	answerPending := false
+	var answerTime time.Duration
+	recordAnswer := func(timeSpent time.Duration) {
+		answerPending = false
+		event := types.NewAnswerEvent(question, certSet.CertificationID, sessionID, timeSpent)
+		if err := rep.RecordAnswer(ctx, event); err != nil {
+			log.Printf("failed to record answer: %v", err)
+		}
+	}
+	commitAnswer := func() {
+		if answerPending {
+			recordAnswer(answerTime)
+		}
+	}
+
 	prevQuestion := func() {
+		commitAnswer()
 		question, err = fetchQuestionByIndex(questions, session.GetAndDecIndex())
 		if err != nil {
 			log.Fatalf("failed to fetch question: %v", err)
 		}
-		questionView.SetQuestion(question)
+		answerArea.SetQuestion(question)
+		notesView.SetQuestion(question)
+		shownAt = time.Now()
+		saveSession()
 		views.QuestionStateOverview(questions, textcieTest, session.GetCurrentQuestionIndex())
 		progressBar.SetQuestions(questions)
 	}
 
 	nextQuestion := func() {
+		commitAnswer()
 		question, err = fetchQuestionByIndex(questions, session.GetAndIncIndex())
 		if err != nil {
 			log.Fatalf("failed to fetch question: %v", err)
 		}
-		questionView.SetQuestion(question)
+		answerArea.SetQuestion(question)
+		notesView.SetQuestion(question)
+		shownAt = time.Now()
+		saveSession()
 		views.QuestionStateOverview(questions, textcieTest, session.GetCurrentQuestionIndex())
 		progressBar.SetQuestions(questions)
 	}
 
-	showStatistics := func() {
-		testSetQuestionLenght := len(certSet.Questions)
-		questionsLenght := len(questions)
-		AlreadyAnswered := len(formerQuestionStates)
-
-		CorrectAnswered := 0
-		IncorrectAnswered := 0
-		for _, question := range certSet.Questions {
-			if question.AnsweredState == types.AnsweredTrue {
-				CorrectAnswered++
-			} else if question.AnsweredState == types.AnsweredFalse {
-				IncorrectAnswered++
+	reviewOpen := false
+	annotationsOpen := false
+	saveAnnotations := func(tags []string, note string) {
+		question.SetTags(tags)
+		question.SetNote(note)
+		if err := rep.UpsertQuestion(ctx, question); err != nil {
+			log.Printf("failed to save tags and note: %v", err)
+		}
+		notesView.SetQuestion(question)
+	}
+	editAnnotations := func() {
+		closeForm := func() {
+			annotationsOpen = false
+			app.SetRoot(flex, true)
+		}
+		form := views.NewAnnotationForm(question, func(tags []string, note string) {
+			saveAnnotations(tags, note)
+			closeForm()
+		}, closeForm)
+		annotationsOpen = true
+		app.SetRoot(form, true)
+	}
+	editNote := func() {
+		app.Suspend(func() {
+			note, err := editInEditor(question.GetNote())
+			if err != nil {
+				log.Printf("failed to edit note: %v", err)
+				return
 			}
+			saveAnnotations(question.GetTags(), note)
+		})
+	}
+	jumpToIndex := func(index int) {
+		commitAnswer()
+		question, err = fetchQuestionByIndex(questions, session.SetCurrentQuestionIndex(index))
+		if err != nil {
+			log.Fatalf("failed to fetch question: %v", err)
 		}
+		answerArea.SetQuestion(question)
+		notesView.SetQuestion(question)
+		shownAt = time.Now()
+		saveSession()
+		views.QuestionStateOverview(questions, textcieTest, session.GetCurrentQuestionIndex())
+		progressBar.SetQuestions(questions)
+		app.SetRoot(flex, true)
+	}
+	showReview := func() {
+		review := views.NewReviewView(questions)
+		review.SetJumpFunc(func(index int) {
+			reviewOpen = false
+			jumpToIndex(index)
+		})
+		review.SetCloseFunc(func() {
+			reviewOpen = false
+			app.SetRoot(flex, true)
+		})
+		reviewOpen = true
+		app.SetRoot(review, true)
+	}
 
-		CorrectAnsweredTotal := 0
-		IncorrectAnsweredTotal := 0
-		for _, question := range certSet.Questions {
-			if question.AnsweredState == types.AnsweredTrue {
-				CorrectAnsweredTotal++
-			} else if question.AnsweredState == types.AnsweredFalse {
-				IncorrectAnsweredTotal++
+	searchOpen := false
+	showSearch := func() {
+		searchView := views.NewSearchView(index)
+		searchView.SetJumpFunc(func(found *types.Question) {
+			searchOpen = false
+			position := slices.Index(questions, found)
+			if position < 0 {
+				// questions of other testsets are added to the end of the session
+				// with their stored state
+				states, err := rep.GetAnsweredQuestions()
+				if err != nil {
+					log.Printf("failed to load the state of question %d: %v", found.ID, err)
+				}
+				certSet.GetQuestionsByIDs([]int{found.ID}, states)
+				questions = append(questions, found)
+				testset.QuestionsIds = append(testset.QuestionsIds, found.ID)
+				sessionState.QuestionIDs = append(sessionState.QuestionIDs, found.ID)
+				position = len(questions) - 1
 			}
-		}
+			jumpToIndex(position)
+		})
+		searchView.SetCloseFunc(func() {
+			searchOpen = false
+			app.SetRoot(flex, true)
+		})
+		searchOpen = true
+		app.SetRoot(searchView, true)
+	}
+
+	showStatistics := func() {
+		// near-duplicates are counted once with -groupDuplicates
+		allQuestions := certSet.CertificationSetMapToSlice()
+		testSetQuestionLenght := len(types.ItemStates(allQuestions))
+		questionsLenght := len(types.ItemStates(questions))
+		AlreadyAnswered := len(formerQuestionStates)
+
+		CorrectAnswered, IncorrectAnswered, _ := types.CountItemStates(questions)
+		CorrectAnsweredTotal, IncorrectAnsweredTotal, _ := types.CountItemStates(allQuestions)
 
 		precentageCorrect := float64(CorrectAnswered) / float64(questionsLenght) * 100
 		precentageIncorrect := float64(IncorrectAnswered) / float64(questionsLenght) * 100
@@ -295,11 +675,23 @@ func main() {
 	markAnswer := func() {
 		state := questionView.ToggleCurrentMarkedOption()
 		question.SetAnsweredState(state)
+		answerPending = true
+		answerTime = time.Since(shownAt)
 		views.QuestionStateOverview(questions, textcieTest, session.GetCurrentQuestionIndex())
 		progressBar.SetQuestions(questions)
-		rep.UpsertQuestion(ctx, question)
 	}
 
+	// fill-in-the-blank, ordering and matching questions are answered in the
+	// editor of answerArea, which grades the answer itself
+	answerArea.SetDoneFunc(func(answered bool) {
+		app.SetFocus(flex)
+		if answered {
+			recordAnswer(time.Since(shownAt))
+			views.QuestionStateOverview(questions, textcieTest, session.GetCurrentQuestionIndex())
+			progressBar.SetQuestions(questions)
+		}
+	})
+
 	setFirstView := func() {
 		if err := app.SetRoot(flex, false).Run(); err != nil {
 			panic(err)
@@ -309,7 +701,7 @@ func main() {
 	showHelp := func() {
 		helpText := "Help:\n" +
 			"q: Quit\n" +
-			"Enter: Mark answer\n" +
+			"Enter: Mark answer, type the answer of a fill-in question or arrange the items of an ordering or matching question\n" +
 			"Space: Mark answer\n" +
 			"n/right: Next question\n" +
 			"p/left: Previous question\n" +
@@ -319,7 +711,11 @@ func main() {
 			"e: Show explanation\n" +
 			"v: Mark question as important\n" +
 			"b: Unmark question as important\n" +
+			"o: Edit tags and note of the question\n" +
+			"O: Edit the note in $EDITOR\n" +
 			"u: reset all questions in a testset\n" +
+			"r: Review wrong and skipped questions\n" +
+			"/: Search all questions of the certification\n" +
 			"h: Show help\n" +
 			"s: Toggle Solve/Unsolve question\n"
 		modal = tview.NewModal().
@@ -336,10 +732,18 @@ func main() {
     toogleSolve := false
 
     app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+        if reviewOpen || annotationsOpen || searchOpen || answerArea.Editing() {
+            return event
+        }
+        if editor := answerArea.Editor(); editor != nil && (event.Key() == tcell.KeyEnter || event.Key() == tcell.KeyRune && event.Rune() == ' ') {
+            app.SetFocus(editor)
+            return nil
+        }
         switch event.Key() {
         case tcell.KeyRune:
             switch event.Rune() {
             case 'q':
+                commitAnswer()
                 app.Stop()
             case ' ':
                 markAnswer()
@@ -356,8 +760,18 @@ func main() {
                 question.SetIsImportant(false)
                 rep.UpsertQuestion(ctx, question)
                 progressBar.SetQuestions(questions)
+            case 'o':
+                editAnnotations()
+                return nil
+            case 'O':
+                editNote()
             case 'h':
                 showHelp()
+            case 'r':
+                showReview()
+            case '/':
+                showSearch()
+                return nil
             case 'u':
                 modal := tview.NewModal().
                     SetText("Should i really reset the testset?").
@@ -365,9 +779,12 @@ func main() {
                     SetDoneFunc(func(buttonIndex int, buttonLabel string) {
                         if buttonLabel == "Reset" {
                             log.Println("Resetting testset")
+                            answerPending = false
                             for _, question := range questions {
                                 question.ResetAnsweredState()
-                                rep.UpsertQuestion(ctx, question)
+                                if err := rep.RecordAnswer(ctx, types.NewResetEvent(question, certSet.CertificationID, sessionID)); err != nil {
+                                    log.Printf("failed to record reset: %v", err)
+                                }
                             }
                             views.QuestionStateOverview(questions, textcieTest, session.GetCurrentQuestionIndex())
                             progressBar.SetQuestions(questions)
@@ -380,12 +797,13 @@ func main() {
             case 'p':
                 prevQuestion(); toogleSolve = false
             case 's':
+                commitAnswer()
                 toogleSolve = !toogleSolve
                 for _, v := range questionView.GetCurrentQuestion().Answers {
 					v.SetIsMarked(toogleSolve)
 					questionView.GetCurrentQuestion().SetAnsweredState(types.AnsweredFalse)
 				}
-				questionView.ShowExplanation()
+				answerArea.ShowExplanation()
 				views.QuestionStateOverview(questions, textcieTest, session.GetCurrentQuestionIndex())
 
 			case 't':
@@ -412,4 +830,5 @@ func main() {
 	if err := app.SetRoot(flex, true).Run(); err != nil {
 		panic(err)
 	}
+	commitAnswer()
 }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// editInEditor lets the user edit text in $VISUAL or $EDITOR (vi if neither
+// is set) and returns the edited text. The terminal has to be released by
+// the caller, e.g. with tview's Application.Suspend.
+func editInEditor(text string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.CreateTemp("", "lpic-note-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	// $EDITOR may carry arguments, e.g. "code --wait"
+	args := append(strings.Fields(editor), f.Name())
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %w", args[0], err)
+	}
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(edited), nil
+}
+
+// filterTagged returns the questions tagged with one of tags.
+func filterTagged(questions []*types.Question, tags []string) []*types.Question {
+	var tagged []*types.Question
+	for _, question := range questions {
+		if question.HasTag(tags...) {
+			tagged = append(tagged, question)
+		}
+	}
+	return tagged
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+func TestEditInEditor(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	// $EDITOR may carry arguments
+	t.Setenv("EDITOR", "sed -i s/old/new/")
+	edited, err := editInEditor("the old note\n")
+	if err != nil {
+		t.Fatalf("editInEditor: %v", err)
+	}
+	if edited != "the new note\n" {
+		t.Errorf("editInEditor() = %q, want the edited text", edited)
+	}
+
+	t.Setenv("VISUAL", "false")
+	if _, err := editInEditor("the old note\n"); err == nil {
+		t.Error("editInEditor ignored a failing $VISUAL")
+	}
+}
+
+func TestFilterTagged(t *testing.T) {
+	questions := []*types.Question{
+		(&types.Question{ID: 1}).SetTags([]string{"grep"}),
+		(&types.Question{ID: 2}).SetTags([]string{"exam-trap"}),
+		{ID: 3},
+	}
+	var ids []int
+	for _, question := range filterTagged(questions, []string{"Exam Trap", "grep"}) {
+		ids = append(ids, question.ID)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Errorf("filterTagged() = %v, want questions 1 and 2", ids)
+	}
+	if tagged := filterTagged(questions, []string{"sed"}); len(tagged) != 0 {
+		t.Errorf("filterTagged() = %d questions, want none", len(tagged))
+	}
+}
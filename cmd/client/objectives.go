@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/SqiSch/lpic-cli/internal/objectives"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// printObjectives prints the objectives of the certification with their
+// weight and number of questions. Objectives named by questions but not
+// known for the certification's exam are listed after them.
+func printObjectives(certSet *types.CertificationSet) {
+	counts := make(map[string]int)
+	unclassified := 0
+	for _, question := range certSet.Questions {
+		if len(question.Objectives) == 0 {
+			unclassified++
+		}
+		for _, id := range question.Objectives {
+			counts[id]++
+		}
+	}
+
+	fmt.Println("Objective  Weight  Questions  Title")
+	for _, objective := range objectives.ForCertification(certSet.CertificationID) {
+		fmt.Printf("%-9s  %6d  %9d  %s\n", objective.ID, objective.Weight, counts[objective.ID], objective.Title)
+		delete(counts, objective.ID)
+	}
+	others := make([]string, 0, len(counts))
+	for id := range counts {
+		others = append(others, id)
+	}
+	sort.Strings(others)
+	for _, id := range others {
+		fmt.Printf("%-9s  %6s  %9d  %s\n", id, "-", counts[id], objectives.Title(id))
+	}
+	if unclassified > 0 {
+		fmt.Printf("Questions without objective: %d\n", unclassified)
+	}
+}
+
+// objectiveQuestions returns the questions of all testsets whose objectives
+// match filter, ordered by ID.
+func objectiveQuestions(certSet *types.CertificationSet, filter string, filterCorrect bool, stateDB []types.QuestionStateDB) ([]*types.Question, error) {
+	questions, err := certSet.GetQuestionsForTestset("", filterCorrect, stateDB)
+	if err != nil {
+		return nil, err
+	}
+	var matching []*types.Question
+	for _, question := range questions {
+		if objectives.HasObjective(question, filter) {
+			matching = append(matching, question)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool { return matching[i].ID < matching[j].ID })
+	return matching, nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SqiSch/lpic-cli/internal/database"
+	"github.com/SqiSch/lpic-cli/internal/printable"
+	"github.com/SqiSch/lpic-cli/internal/repository"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// runExportExam implements "client export-exam": it renders a testset or a
+// random selection of questions as a printable mock exam with an answer key.
+// The questions are selected like in the client; random selections only
+// depend on the seed.
+func runExportExam(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export-exam", flag.ExitOnError)
+	dbFile := fs.String("dbfile", "test.json", "Path or http(s) URL of the JSON database file containing certification sets")
+	keyFile := fs.String("keyFile", "", "File containing the AES key of an encrypted .enc database file. If empty $AES_KEY is used")
+	certID := fs.String("certId", "lpic1-101-500", "Id of the certification set to export")
+	testsetID := fs.String("testsetId", "", "Test set to export. If empty all questions of the certification are exported")
+	randomQuestions := fs.Bool("randomQuestions", false, "Draw random questions from the whole certification set instead of a test set")
+	filterCorrect := fs.Bool("filterCorrect", false, "Leave out questions that were answered correctly")
+	onlyImportant := fs.Bool("onlyImportant", false, "Only export important questions")
+	count := fs.Int("questions", 0, "Maximum number of questions. 0 exports all selected questions")
+	seed := fs.Int64("seed", 0, "Seed for -randomQuestions. 0 uses a random seed, which is printed")
+	title := fs.String("title", "", "Title of the exam. If empty it is derived from the certification")
+	format := fs.String("format", "", "Output format: html or markdown. If empty it is derived from -o, html for stdout")
+	output := fs.String("o", "-", "File to write the exam to, - writes to stdout")
+	keyOutput := fs.String("answerKey", "", "File to write the answer key to. If empty the key follows the exam on a new page")
+	profile := fs.String("profile", repository.DefaultProfile, "Profile whose progress is used for -filterCorrect and -onlyImportant")
+	state := addStateFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: client export-exam [options]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	sheetFormat, err := exportFormat(*format, *output)
+	if err != nil {
+		return err
+	}
+
+	certSet, err := database.LoadDatabaseFromFile(*dbFile, *certID, *keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certification set: %w", err)
+	}
+
+	var questionStates []types.QuestionStateDB
+	if *filterCorrect || *onlyImportant {
+		rep, err := repository.Open(ctx, state.config(repository.Namespace{CertificationID: *certID, Profile: *profile}))
+		if err != nil {
+			return fmt.Errorf("failed to open state backend: %w", err)
+		}
+		questionStates, err = rep.GetAnsweredQuestions()
+		rep.Close()
+		if err != nil {
+			return fmt.Errorf("failed to load progress: %w", err)
+		}
+	}
+
+	selection := *testsetID
+	if *randomQuestions {
+		selection = ""
+	}
+	questions, err := certSet.GetQuestionsForTestset(selection, *filterCorrect, questionStates)
+	if err != nil {
+		return fmt.Errorf("failed to fetch questions: %w", err)
+	}
+	if selection == "" {
+		// The certification set is a map, so sort before shuffling to only
+		// depend on the seed.
+		sort.Slice(questions, func(i, j int) bool { return questions[i].ID < questions[j].ID })
+	}
+	if *onlyImportant {
+		important := make([]*types.Question, 0)
+		for _, question := range questions {
+			if question.GetIsImportant() {
+				important = append(important, question)
+			}
+		}
+		if len(important) == 0 {
+			fmt.Fprintln(os.Stderr, "No important questions found, using all questions")
+			important = questions
+		}
+		questions = important
+	}
+	if *randomQuestions {
+		if *seed == 0 {
+			*seed = time.Now().UnixNano()
+		}
+		rng := rand.New(rand.NewSource(*seed))
+		rng.Shuffle(len(questions), func(i, j int) { questions[i], questions[j] = questions[j], questions[i] })
+	}
+	if *count > 0 && len(questions) > *count {
+		questions = questions[:*count]
+	}
+	if len(questions) == 0 {
+		return fmt.Errorf("no questions match the filters")
+	}
+
+	sheet := printable.Sheet{Title: *title, Questions: questions}
+	if sheet.Title == "" {
+		sheet.Title = certSet.CertificationName
+		if sheet.Title == "" {
+			sheet.Title = certSet.CertificationID
+		}
+		sheet.Title += " – Mock exam"
+	}
+	sheet.Subtitle = examSubtitle(certSet, selection, len(questions), *randomQuestions, *seed)
+
+	err = writeOutput(*output, func(w io.Writer) error {
+		return printable.WriteExam(w, sheetFormat, sheet, *keyOutput == "")
+	})
+	if err != nil {
+		return err
+	}
+	if *keyOutput != "" {
+		keyFormat, err := exportFormat(*format, *keyOutput)
+		if err != nil {
+			return err
+		}
+		err = writeOutput(*keyOutput, func(w io.Writer) error {
+			return printable.WriteKey(w, keyFormat, sheet)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if *randomQuestions {
+		fmt.Fprintf(os.Stderr, "Exported %d questions with seed %d\n", len(questions), *seed)
+	} else {
+		fmt.Fprintf(os.Stderr, "Exported %d questions\n", len(questions))
+	}
+	return nil
+}
+
+// exportFormat returns format, or the format given by the extension of output.
+func exportFormat(format, output string) (printable.Format, error) {
+	if format != "" {
+		return printable.ParseFormat(format)
+	}
+	if output == "-" || filepath.Ext(output) == "" {
+		return printable.FormatHTML, nil
+	}
+	return printable.ParseFormat(filepath.Ext(output))
+}
+
+func examSubtitle(certSet *types.CertificationSet, testsetID string, count int, random bool, seed int64) string {
+	parts := []string{}
+	switch {
+	case random:
+		parts = append(parts, "Random questions")
+	case testsetID != "":
+		parts = append(parts, certSet.Testsets[testsetID].TestsetName)
+	default:
+		parts = append(parts, "All questions")
+	}
+	if count == 1 {
+		parts = append(parts, "1 question")
+	} else {
+		parts = append(parts, fmt.Sprintf("%d questions", count))
+	}
+	if random {
+		parts = append(parts, fmt.Sprintf("seed %d", seed))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// writeOutput calls write with the file output, or stdout for -.
+func writeOutput(output string, write func(w io.Writer) error) error {
+	if output == "-" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
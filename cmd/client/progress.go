@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SqiSch/lpic-cli/internal/repository"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// printProgress prints the progress summary and the answers per day. With
+// groupDuplicates the near-duplicates marked in certSet are also summarized
+// as one question.
+func printProgress(ctx context.Context, rep repository.Store, certSet *types.CertificationSet, groupDuplicates bool) error {
+	summary, err := rep.GetProgressSummary(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Questions with progress: %d\n", summary.Questions)
+	fmt.Printf("Correct: %d\nIncorrect: %d\nImportant: %d\nAttempts: %d\n", summary.Correct, summary.Incorrect, summary.Important, summary.Attempts)
+	if groupDuplicates {
+		states, err := rep.GetAnsweredQuestions()
+		if err != nil {
+			return err
+		}
+		questions, err := certSet.GetQuestionsForTestset("", false, states)
+		if err != nil {
+			return err
+		}
+		var answered []*types.Question
+		for _, question := range questions {
+			if question.AnsweredState != types.AnsweredUnknown {
+				answered = append(answered, question)
+			}
+		}
+		correct, incorrect, _ := types.CountItemStates(answered)
+		fmt.Printf("\nNear-duplicates counted once: %d answered of %d questions\n", correct+incorrect, len(types.ItemStates(questions)))
+		fmt.Printf("Correct: %d\nIncorrect: %d\n", correct, incorrect)
+	}
+
+	days, err := rep.GetDailyProgress(ctx)
+	if err != nil {
+		return err
+	}
+	if len(days) == 0 {
+		return nil
+	}
+	fmt.Println("\nDay         Attempts  Correct  Incorrect  Time")
+	for _, day := range days {
+		fmt.Printf("%s  %8d  %7d  %9d  %s\n", day.Day, day.Attempts, day.Correct, day.Incorrect, formatDuration(day.TimeSpent))
+	}
+	return nil
+}
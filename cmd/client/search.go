@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/database"
+	"github.com/SqiSch/lpic-cli/internal/search"
+)
+
+// Exit codes of "client search", like grep(1).
+const (
+	searchExitNoMatch = 1
+	searchExitFailure = 2
+)
+
+// searchExcerptWidth is the number of characters shown around a match.
+const searchExcerptWidth = 100
+
+type searchMatch struct {
+	Field   search.Field `json:"field"`
+	Excerpt string       `json:"excerpt"`
+}
+
+type searchHit struct {
+	ID       int           `json:"id"`
+	Score    float64       `json:"score"`
+	Testsets []string      `json:"testsets"`
+	Text     string        `json:"text"`
+	Matches  []searchMatch `json:"matches"`
+}
+
+type searchReport struct {
+	Query   string      `json:"query"`
+	Total   int         `json:"total"`
+	Results []searchHit `json:"results"`
+}
+
+// runSearch implements "client search": it prints the questions of a
+// certification whose text, answers or explanation contain every search term,
+// best matches first. It exits with 0 if questions were found, 1 if not and 2
+// on failure.
+func runSearch(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	dbFile := fs.String("dbfile", "test.json", "Path or http(s) URL of the JSON database file containing certification sets")
+	keyFile := fs.String("keyFile", "", "File containing the AES key of an encrypted .enc database file. If empty $AES_KEY is used")
+	certID := fs.String("certId", "lpic1-101-500", "Id of the certification set to search")
+	format := fs.String("format", "text", "Output format: text or json")
+	limit := fs.Int("limit", 20, "Maximum number of questions printed. 0 prints all")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: client search [options] <term>...")
+		fmt.Fprintln(fs.Output(), "Exit codes: 0 questions found, 1 nothing found, 2 the dataset could not be searched")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *format != "json" && *format != "text" {
+		return &exitError{code: searchExitFailure, err: fmt.Errorf("unknown format %q", *format)}
+	}
+	query := strings.Join(fs.Args(), " ")
+	terms := search.Terms(query)
+	if len(terms) == 0 {
+		fs.Usage()
+		return &exitError{code: searchExitFailure, err: fmt.Errorf("expected a search term")}
+	}
+
+	certSet, err := database.LoadDatabaseFromFile(*dbFile, *certID, *keyFile)
+	if err != nil {
+		return &exitError{code: searchExitFailure, err: fmt.Errorf("failed to load certification set: %w", err)}
+	}
+	results := search.NewIndex(certSet).Search(query)
+
+	report := searchReport{Query: query, Total: len(results), Results: make([]searchHit, 0)}
+	if *limit > 0 && len(results) > *limit {
+		results = results[:*limit]
+	}
+	for _, result := range results {
+		report.Results = append(report.Results, searchHit{
+			ID:       result.Question.ID,
+			Score:    result.Score,
+			Testsets: result.Testsets,
+			Text:     result.Question.Text,
+			Matches:  searchMatches(result, terms),
+		})
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = printSearchText(os.Stdout, report, terms, isTerminal(os.Stdout))
+	}
+	if err != nil {
+		return &exitError{code: searchExitFailure, err: err}
+	}
+
+	if report.Total == 0 {
+		return &exitError{code: searchExitNoMatch, err: fmt.Errorf("no questions match %q", query)}
+	}
+	return nil
+}
+
+// searchMatches returns an excerpt of every answer and explanation of the
+// result containing one of terms. The question text is always printed.
+func searchMatches(result search.Result, terms []string) []searchMatch {
+	var matches []searchMatch
+	for _, field := range result.Fields {
+		switch field {
+		case search.FieldAnswer:
+			for _, text := range result.Question.SolutionTexts() {
+				if search.Contains(text, terms) {
+					matches = append(matches, searchMatch{Field: field, Excerpt: search.Excerpt(text, terms, searchExcerptWidth)})
+				}
+			}
+		case search.FieldExplanation:
+			matches = append(matches, searchMatch{Field: field, Excerpt: search.Excerpt(result.Question.Explanation, terms, searchExcerptWidth)})
+		}
+	}
+	return matches
+}
+
+func printSearchText(w io.Writer, report searchReport, terms []string, color bool) error {
+	mark := func(s string) string { return "*" + s + "*" }
+	if color {
+		mark = func(s string) string { return "\x1b[1;33m" + s + "\x1b[0m" }
+	}
+
+	for _, hit := range report.Results {
+		fmt.Fprintf(w, "%d  score %.1f  testsets %s\n", hit.ID, hit.Score, strings.Join(hit.Testsets, ", "))
+		fmt.Fprintf(w, "    %s\n", search.Highlight(search.Excerpt(hit.Text, terms, 2*searchExcerptWidth), terms, mark, nil))
+		for _, match := range hit.Matches {
+			fmt.Fprintf(w, "    %s: %s\n", match.Field, search.Highlight(match.Excerpt, terms, mark, nil))
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d of %d questions match %q\n", len(report.Results), report.Total, report.Query)
+	return err
+}
+
+// isTerminal reports whether f is a terminal, so matches are only colored
+// when they are not piped into another command.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"log"
+
+	"github.com/SqiSch/lpic-cli/internal/objectives"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// restoreSession rebuilds the question list of a stored session in its
+// original order. Questions of a review session that were not answered since
+// the session started are shown unmarked again, like when it was started.
+func restoreSession(certSet *types.CertificationSet, session *types.SessionState, stateDB []types.QuestionStateDB) []*types.Question {
+	questions, missing := certSet.GetQuestionsByIDs(session.QuestionIDs, stateDB)
+	if len(missing) > 0 {
+		log.Printf("Skipping %d questions of session %s that are no longer in the certification set: %v", len(missing), session.ID, missing)
+	}
+
+	if session.Mode == types.SessionModeReview {
+		answeredAt := make(map[int]bool, len(stateDB))
+		for _, questionState := range stateDB {
+			if !questionState.LastAnsweredAt.Before(session.StartedAt) {
+				answeredAt[questionState.QuestionID] = true
+			}
+		}
+		for _, question := range questions {
+			if !answeredAt[question.ID] {
+				question.ResetAnsweredState()
+			}
+		}
+	}
+	return questions
+}
+
+// sessionTestset returns the testset the questions of a session are served
+// from. Filtered, random, review and objective sessions get a testset of
+// their own.
+func sessionTestset(certSet *types.CertificationSet, session *types.SessionState, questions []*types.Question) types.Testset {
+	testset := types.Testset{TestsetID: session.TestsetID, QuestionsIds: make([]int, 0, len(questions))}
+	switch session.Mode {
+	case types.SessionModeRandom:
+		testset.TestsetName = "Random Questions"
+	case types.SessionModeReview:
+		testset.TestsetName = "Due for Review"
+	case types.SessionModeObjective:
+		testset.TestsetName = objectives.Title(session.Objective)
+	default:
+		testset.TestsetName = certSet.Testsets[session.TestsetID].TestsetName
+	}
+	for _, question := range questions {
+		testset.QuestionsIds = append(testset.QuestionsIds, question.ID)
+	}
+	return testset
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+func TestRestoreSession(t *testing.T) {
+	startedAt := time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)
+	newCertSet := func() *types.CertificationSet {
+		certSet := &types.CertificationSet{
+			Questions: map[int]*types.Question{},
+			Testsets:  map[string]types.Testset{"basics": {TestsetName: "Basics"}},
+		}
+		for _, id := range []int{1, 2, 3} {
+			certSet.Questions[id] = &types.Question{ID: id, Answers: []*types.Answer{{AnswerID: "a", IsCorrect: true}, {AnswerID: "b"}}}
+		}
+		return certSet
+	}
+	stateDB := []types.QuestionStateDB{
+		// answered during the session
+		{QuestionID: 1, MarkedAnswers: []string{"a"}, AnsweredState: types.AnsweredTrue, LastAnsweredAt: startedAt.Add(time.Minute)},
+		// answered before the session
+		{QuestionID: 3, MarkedAnswers: []string{"b"}, AnsweredState: types.AnsweredFalse, LastAnsweredAt: startedAt.Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		mode     string
+		answered []types.AnsweredState
+		name     string
+	}{
+		{types.SessionModeTestset, []types.AnsweredState{types.AnsweredFalse, types.AnsweredUnknown, types.AnsweredTrue}, "Basics"},
+		{types.SessionModeReview, []types.AnsweredState{types.AnsweredUnknown, types.AnsweredUnknown, types.AnsweredTrue}, "Due for Review"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			certSet := newCertSet()
+			session := &types.SessionState{ID: "s1", Mode: tt.mode, TestsetID: "basics", QuestionIDs: []int{3, 2, 7, 1}, StartedAt: startedAt}
+			questions := restoreSession(certSet, session, stateDB)
+			if len(questions) != 3 {
+				t.Fatalf("restored %d questions, want the 3 still in the certification set", len(questions))
+			}
+			for i, question := range questions {
+				if question.ID != []int{3, 2, 1}[i] || question.AnsweredState != tt.answered[i] {
+					t.Errorf("question %d: ID %d answered %v, want ID %d answered %v", i, question.ID, question.AnsweredState, []int{3, 2, 1}[i], tt.answered[i])
+				}
+			}
+
+			testset := sessionTestset(certSet, session, questions)
+			if testset.TestsetName != tt.name || len(testset.QuestionsIds) != 3 || testset.QuestionsIds[0] != 3 {
+				t.Errorf("testset = %+v, want %q with the restored questions", testset, tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/SqiSch/lpic-cli/internal/repository"
+)
+
+// stateFlags are the flags selecting the progress backend. They are shared
+// by the client and its subcommands.
+type stateFlags struct {
+	dir             *string
+	backend         *string
+	mongoURI        *string
+	mongoDatabase   *string
+	mongoCollection *string
+}
+
+func addStateFlags(fs *flag.FlagSet) *stateFlags {
+	return &stateFlags{
+		dir:             fs.String("stateDir", "", "Directory to store persistent state (.nutsdb). If empty defaults to $HOME/.nutsdb"),
+		backend:         fs.String("stateBackend", repository.BackendNuts, "Backend used to store progress: nuts, sqlite, mongo or memory"),
+		mongoURI:        fs.String("mongoURI", "", "MongoDB URI of the mongo state backend. If empty $MONGO_URI or mongodb://localhost:27017 is used"),
+		mongoDatabase:   fs.String("mongoDatabase", repository.DefaultMongoDatabase, "MongoDB database of the mongo state backend"),
+		mongoCollection: fs.String("mongoCollection", repository.DefaultMongoCollection, "MongoDB collection of the mongo state backend"),
+	}
+}
+
+func (f *stateFlags) config(ns repository.Namespace) repository.Config {
+	return repository.Config{
+		Backend: *f.backend,
+		Dir:     *f.dir,
+		Mongo: repository.MongoConfig{
+			URI:        *f.mongoURI,
+			Database:   *f.mongoDatabase,
+			Collection: *f.mongoCollection,
+		},
+		Namespace: ns,
+	}
+}
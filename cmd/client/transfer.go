@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SqiSch/lpic-cli/internal/repository"
+	"github.com/SqiSch/lpic-cli/internal/transfer"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// runExportProgress implements "client export-progress": it writes the stored
+// progress of all (or the selected) namespaces to a versioned JSON file.
+func runExportProgress(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export-progress", flag.ExitOnError)
+	state := addStateFlags(fs)
+	certID := fs.String("certId", "", "Only export this certification. If empty all certifications are exported")
+	profile := fs.String("profile", "", "Only export this profile. If empty all profiles are exported")
+	output := fs.String("o", "-", "File to write the progress to, - writes to stdout")
+	legacyCertID := fs.String("legacyCertId", "", "Export the progress of versions without certifications as the progress of this certification and -profile")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: client export-progress [options]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	filter := transfer.Filter{CertificationID: *certID, Profile: *profile}
+	var legacy repository.Namespace
+	if *legacyCertID != "" {
+		legacy = repository.Namespace{CertificationID: *legacyCertID, Profile: *profile}
+	}
+	cfg := state.config(repository.Namespace{})
+	file, err := transfer.Export(ctx, cfg.Opener(ctx), filter, legacy)
+	if errors.Is(err, transfer.ErrLegacyProgress) {
+		return fmt.Errorf("%w; pass -legacyCertId to export it as the progress of that certification", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := transfer.Write(w, file); err != nil {
+		return fmt.Errorf("failed to write progress file: %w", err)
+	}
+
+	questions := 0
+	for _, ns := range file.Namespaces {
+		questions += len(ns.Questions)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d question states of %d namespace(s)\n", questions, len(file.Namespaces))
+	return nil
+}
+
+// runImportProgress implements "client import-progress": it merges a file
+// written by export-progress into the state backend and reports the changes.
+func runImportProgress(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import-progress", flag.ExitOnError)
+	state := addStateFlags(fs)
+	certID := fs.String("certId", "", "Only import this certification. If empty all certifications are imported")
+	profile := fs.String("profile", "", "Only import this profile. If empty all profiles are imported")
+	dryRun := fs.Bool("dryRun", false, "Only report what would change, do not write anything")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: client import-progress [options] <file>")
+		fmt.Fprintln(fs.Output(), "Reads the progress from stdin if <file> is -")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one progress file")
+	}
+
+	var r io.Reader = os.Stdin
+	if fs.Arg(0) != "-" {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	file, err := transfer.Read(r)
+	if err != nil {
+		return err
+	}
+
+	filter := transfer.Filter{CertificationID: *certID, Profile: *profile}
+	cfg := state.config(repository.Namespace{})
+	report, err := transfer.Import(ctx, cfg.Opener(ctx), file, filter, *dryRun)
+	if err != nil {
+		return err
+	}
+	printImportReport(report, file.ExportedAt)
+	return nil
+}
+
+func printImportReport(report *transfer.Report, exportedAt time.Time) {
+	if report.DryRun {
+		fmt.Println("Dry run, nothing was written.")
+	}
+	fmt.Printf("Progress exported at %s:\n", exportedAt.Local().Format("2006-01-02 15:04"))
+	if len(report.Namespaces) == 0 {
+		fmt.Println("No matching namespaces.")
+	}
+	for _, ns := range report.Namespaces {
+		fmt.Printf("%s: %d added, %d updated, %d kept, %d unchanged, %d new answer events, %d new exam results\n",
+			ns.Namespace, ns.Count(transfer.ChangeAdded), ns.Count(transfer.ChangeUpdated), ns.Count(transfer.ChangeKept),
+			ns.Unchanged, ns.NewEvents, ns.NewExamResults)
+		for _, change := range ns.Changes {
+			id := change.Imported.QuestionID
+			switch change.Kind {
+			case transfer.ChangeAdded:
+				fmt.Printf("  + question %d: %s\n", id, describeState(change.Merged))
+			case transfer.ChangeUpdated:
+				fmt.Printf("  ~ question %d: %s -> %s\n", id, describeState(*change.Local), describeState(change.Merged))
+			case transfer.ChangeKept:
+				fmt.Printf("  = question %d: kept local %s over imported %s\n", id, describeState(*change.Local), describeState(change.Imported))
+			}
+		}
+	}
+}
+
+func describeState(state types.QuestionStateDB) string {
+	description := "unanswered"
+	switch state.AnsweredState {
+	case types.AnsweredTrue:
+		description = "correct"
+	case types.AnsweredFalse:
+		description = "wrong"
+	}
+	if state.Important {
+		description += ", important"
+	}
+	if len(state.Tags) > 0 {
+		description += ", tags " + strings.Join(state.Tags, ",")
+	}
+	if state.Note != "" {
+		description += ", note"
+	}
+	switch {
+	case state.ResetAt.After(state.LastAnsweredAt):
+		description += " (reset " + state.ResetAt.Local().Format("2006-01-02 15:04") + ")"
+	case !state.LastAnsweredAt.IsZero():
+		description += " (answered " + state.LastAnsweredAt.Local().Format("2006-01-02 15:04") + ")"
+	}
+	return description
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SqiSch/lpic-cli/internal/database"
+	"github.com/SqiSch/lpic-cli/internal/dataset"
+)
+
+// Exit codes of "client validate".
+const (
+	validateExitInvalid = 1
+	validateExitFailure = 2
+)
+
+// exitError makes a subcommand exit with code instead of 1.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// exitCode returns the exit code of a subcommand that failed with err.
+func exitCode(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return 1
+}
+
+type validateResult struct {
+	Valid    bool              `json:"valid"`
+	Errors   int               `json:"errors"`
+	Warnings int               `json:"warnings"`
+	Files    []*dataset.Report `json:"files"`
+}
+
+// runValidate implements "client validate": it checks datasets against the
+// published JSON Schema and the content rules and prints a report. It exits
+// with 0 if all datasets are valid, 1 if one is not and 2 if one could not be
+// read, so it can be used as a pre-commit hook.
+func runValidate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	format := fs.String("format", "json", "Report format: json or text")
+	strict := fs.Bool("strict", false, "Treat warnings as errors")
+	keyFile := fs.String("keyFile", "", "File containing the AES key of encrypted .enc datasets. If empty $AES_KEY is used")
+	printSchema := fs.Bool("schema", false, "Print the JSON Schema of datasets and exit")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: client validate [options] <dataset>...")
+		fmt.Fprintln(fs.Output(), "Exit codes: 0 valid, 1 invalid, 2 a dataset could not be read")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *printSchema {
+		_, err := os.Stdout.Write(dataset.Schema)
+		return err
+	}
+	if *format != "json" && *format != "text" {
+		return &exitError{code: validateExitFailure, err: fmt.Errorf("unknown format %q", *format)}
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return &exitError{code: validateExitFailure, err: fmt.Errorf("expected at least one dataset")}
+	}
+
+	result := validateResult{Valid: true, Files: make([]*dataset.Report, 0, fs.NArg())}
+	for _, location := range fs.Args() {
+		data, err := database.ReadDataset(location, *keyFile)
+		if err != nil {
+			return &exitError{code: validateExitFailure, err: err}
+		}
+		report, err := dataset.Validate(data)
+		if err != nil {
+			return &exitError{code: validateExitFailure, err: err}
+		}
+		report.File = location
+		if *strict && report.Warnings > 0 {
+			report.Valid = false
+		}
+		result.Valid = result.Valid && report.Valid
+		result.Errors += report.Errors
+		result.Warnings += report.Warnings
+		result.Files = append(result.Files, report)
+	}
+
+	var err error
+	if *format == "text" {
+		err = printValidateText(os.Stdout, result)
+	} else {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(result)
+	}
+	if err != nil {
+		return &exitError{code: validateExitFailure, err: err}
+	}
+
+	if !result.Valid {
+		return &exitError{code: validateExitInvalid, err: fmt.Errorf("%d errors, %d warnings", result.Errors, result.Warnings)}
+	}
+	return nil
+}
+
+func printValidateText(w io.Writer, result validateResult) error {
+	for _, report := range result.Files {
+		for _, issue := range report.Issues {
+			if _, err := fmt.Fprintf(w, "%s:%s: %s [%s] %s\n", report.File, issue.Path, issue.Severity, issue.Rule, issue.Message); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d file(s), %d errors, %d warnings\n", len(result.Files), result.Errors, result.Warnings)
+	return err
+}
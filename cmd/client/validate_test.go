@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateExitCodes(t *testing.T) {
+	fixture := func(name string) string {
+		return filepath.Join("..", "..", "internal", "dataset", "testdata", name)
+	}
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"valid", []string{fixture("valid.json")}, 0},
+		{"warnings only", []string{fixture("warnings.json")}, 0},
+		{"strict warnings", []string{"-strict", fixture("warnings.json")}, validateExitInvalid},
+		{"invalid", []string{fixture("valid.json"), fixture("no-correct-answer.json")}, validateExitInvalid},
+		{"unreadable", []string{fixture("valid.json"), fixture("missing.json")}, validateExitFailure},
+		{"unknown format", []string{"-format", "yaml", fixture("valid.json")}, validateExitFailure},
+	}
+
+	stdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	os.Stdout = devNull
+	defer func() { os.Stdout = stdout }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := 0
+			if err := runValidate(context.Background(), tt.args); err != nil {
+				code = exitCode(err)
+			}
+			if code != tt.code {
+				t.Errorf("exit code %d, want %d", code, tt.code)
+			}
+		})
+	}
+}
@@ -1,46 +1,49 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
 	"context"
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/rand"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
-	"os"
+	"strings"
 
 	"github.com/SqiSch/lpic-cli/internal/database"
 	"github.com/SqiSch/lpic-cli/internal/types"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func main() {
 	// Command-line arguments
 	operation := flag.String("operation", "encrypt", "Operation to perform: encrypt or decrypt")
 	var inputFile string
-	flag.StringVar(&inputFile, "input", "", "Input file path for decryption")
+	flag.StringVar(&inputFile, "input", "", "Input file path. For encryption a JSON dataset, e.g. written by the scraper's json sink; if empty the dataset is read from MongoDB")
 	var inputURL string
 	flag.StringVar(&inputURL, "url", "", "Input URL for decryption")
 	outputFile := flag.String("output", "output.json", "Output file path")
+	keyFile := flag.String("keyFile", "", "File containing the AES key. If empty $AES_KEY is used")
+	certIDs := flag.String("certIds", "", "Comma separated certification IDs to read from MongoDB, all if empty")
+	mongoURI := flag.String("mongoURI", database.MongoURIFromEnv(), "MongoDB URI to read the dataset from")
+	mongoDatabase := flag.String("mongoDatabase", "certificationDB", "MongoDB database to read the dataset from")
+	mongoCollection := flag.String("mongoCollection", "questions", "MongoDB collection to read the dataset from")
 	flag.Parse()
 
-	// Get AES key from environment variable
-	aesKey := os.Getenv("AES_KEY")
-	if aesKey == "" {
-		log.Fatal("AES_KEY environment variable is not set")
+	key, err := database.LoadKey(*keyFile)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	key := []byte(aesKey)
-
 	if *operation == "encrypt" {
-		loadDataAndEncrypt(key, *outputFile)
+		var data []byte
+		if inputFile != "" {
+			data = loadFile(inputFile)
+		} else {
+			data = loadMongo(*mongoURI, *mongoDatabase, *mongoCollection, *certIDs)
+		}
+		encryptData(key, data, *outputFile)
 	} else if *operation == "decrypt" {
 		if inputFile == "" && inputURL == "" {
 			log.Fatal("Either input file path or input URL must be provided for decryption")
@@ -55,79 +58,48 @@ func main() {
 	}
 }
 
-// It prepends the nonce to the ciphertext.
-func encrypt(plaintext []byte, key []byte) ([]byte, error) {
-	block, err := aes.NewCipher(key)
+// loadFile returns the JSON dataset in inputFile after checking that it
+// holds certification sets.
+func loadFile(inputFile string) []byte {
+	data, err := ioutil.ReadFile(inputFile)
 	if err != nil {
-		log.Printf("failed to create AES cipher block: %w", err)
-		return nil, err
+		log.Fatalf("Failed to read dataset: %v", err)
 	}
-
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		log.Printf("failed to create GCM cipher: %w", err)
-		return nil, err
-	}
-
-	// GCM requires a unique nonce. We generate a random one for each encryption.
-	// The nonce size is determined by the GCM instance.
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
-		log.Printf("failed to generate nonce: %w", err)
-		return nil, err
+	var certificationSets []types.CertificationSet
+	if err := json.Unmarshal(data, &certificationSets); err != nil {
+		log.Fatalf("Failed to decode certification sets of %s: %v", inputFile, err)
 	}
-
-	// Seal encrypts and authenticates the plaintext.
-	// It appends the authentication tag to the ciphertext.
-	// The nonce is prepended to the result for easy storage/retrieval.
-	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil) // The first 'nonce' here is the destination buffer - we want the nonce prepended
-	return ciphertext, nil
+	fmt.Println("Read", len(certificationSets), "certification sets from", inputFile)
+	return data
 }
 
-// decrypt decrypts the given byte slice (nonce + ciphertext) using AES-GCM.
-func decrypt(ciphertextWithNonce []byte, key []byte) ([]byte, error) {
-	block, err := aes.NewCipher(key)
+// loadMongo returns the certification sets with the comma separated certIDs,
+// or all of the collection, as JSON dataset.
+func loadMongo(uri, databaseName, collectionName, certIDs string) []byte {
+	mongoClient, err := database.MongoConnectURI(context.Background(), uri)
 	if err != nil {
-		log.Printf("failed to create AES cipher block: %w", err)
-		return nil, err
+		log.Fatal(err)
 	}
-
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		log.Printf("failed to create GCM cipher: %w", err)
-		return nil, err
-	}
-
-	// Extract the nonce from the beginning of the ciphertextWithNonce
-	nonceSize := gcm.NonceSize()
-	if len(ciphertextWithNonce) < nonceSize {
-		log.Printf("ciphertext too short to contain nonce")
-		return nil, err
-	}
-	nonce := ciphertextWithNonce[:nonceSize]
-	ciphertext := ciphertextWithNonce[nonceSize:]
-
-	// Open decrypts and authenticates the data.
-	// It verifies the authentication tag appended by Seal().
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil) // The first 'nil' means create a new buffer for plaintext
-	if err != nil {
-		log.Printf("failed to decrypt and authenticate: %w", err) // This error likely indicates tampering
-		return nil, err
-	}
-
-	return plaintext, nil
-}
-
-func loadDataAndEncrypt(key []byte, outputFile string) {
-	certIds := []string{"lpic1-101-500", "lpic1-102-500", "lpic2-202-450", "lpic2-201-450", "cka"}
-
-	mongoClient := database.MongoConnect()
+	defer mongoClient.Disconnect(context.Background())
 
 	// Fetch the CertificationSet from MongoDB
-	collection := mongoClient.Database("certificationDB").Collection("questions")
+	collection := mongoClient.Database(databaseName).Collection(collectionName)
 	var certificationSets []types.CertificationSet
 
-	for _, certID := range certIds {
+	if certIDs == "" {
+		cursor, err := collection.Find(context.TODO(), bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+		if err != nil {
+			log.Fatalf("Failed to fetch CertificationSets: %v", err)
+		}
+		if err := cursor.All(context.TODO(), &certificationSets); err != nil {
+			log.Fatalf("Failed to fetch CertificationSets: %v", err)
+		}
+		fmt.Println("Fetched", len(certificationSets), "CertificationSets")
+	}
+	for _, certID := range strings.Split(certIDs, ",") {
+		if certID == "" {
+			continue
+		}
 		var certificationSet types.CertificationSet
 
 		fmt.Println("Fetching CertificationSet with ID: ", certID)
@@ -144,18 +116,12 @@ func loadDataAndEncrypt(key []byte, outputFile string) {
 	if err != nil {
 		log.Fatalf("Failed to marshal data: %v", err)
 	}
+	return data
+}
 
-	// Compress the data
-	var compressedData bytes.Buffer
-	gzipWriter := gzip.NewWriter(&compressedData)
-	_, err = gzipWriter.Write(data)
-	if err != nil {
-		log.Fatalf("Failed to compress data: %v", err)
-	}
-	gzipWriter.Close()
-
-	// Encrypt the compressed data
-	encryptedData, err := encrypt(compressedData.Bytes(), key)
+func encryptData(key []byte, data []byte, outputFile string) {
+	// Compress and encrypt the data
+	encryptedData, err := database.EncryptDataset(data, key)
 	if err != nil {
 		log.Fatalf("Failed to encrypt data: %v", err)
 	}
@@ -177,28 +143,15 @@ func decryptData(key []byte, inputFile string, outputFile string) {
 		log.Fatalf("Failed to read encrypted data from file: %v", err)
 	}
 
-	// Decrypt the data
-	decryptedData, err := decrypt(encryptedData, key)
+	// Decrypt and decompress the data
+	decompressedData, err := database.DecryptDataset(encryptedData, key)
 	if err != nil {
 		log.Fatalf("Failed to decrypt data: %v", err)
 	}
 
-	// Decompress the decrypted data
-	var decompressedData bytes.Buffer
-	gzipReader, err := gzip.NewReader(bytes.NewReader(decryptedData))
-	if err != nil {
-		log.Fatalf("Failed to decompress data: %v", err)
-	}
-
-	_, err = io.Copy(&decompressedData, gzipReader)
-	gzipReader.Close()
-	if err != nil {
-		log.Fatalf("Failed to read decompressed data: %v", err)
-	}
-
 	// Unmarshal the decompressedData data
 	var certificationSets []types.CertificationSet
-	err = json.Unmarshal(decompressedData.Bytes(), &certificationSets)
+	err = json.Unmarshal(decompressedData, &certificationSets)
 	if err != nil {
 		log.Fatalf("Failed to unmarshal decrypted data: %v", err)
 	}
@@ -234,26 +187,14 @@ func decryptDataFromURL(key []byte, inputURL string, outputFile string) {
 		log.Fatalf("Failed to read encrypted data from response: %v", err)
 	}
 
-	// Decrypt the encrypted data
-	decryptedData, err := decrypt(encryptedData, key)
+	// Decrypt and decompress the data
+	decompressedData, err := database.DecryptDataset(encryptedData, key)
 	if err != nil {
 		log.Fatalf("Failed to decrypt data: %v", err)
 	}
 
-	// Decompress the decrypted data
-	var decompressedData bytes.Buffer
-	gzipReader, err := gzip.NewReader(bytes.NewReader(decryptedData))
-	if err != nil {
-		log.Fatalf("Failed to decompress data: %v", err)
-	}
-	_, err = io.Copy(&decompressedData, gzipReader)
-	gzipReader.Close()
-	if err != nil {
-		log.Fatalf("Failed to read decompressed data: %v", err)
-	}
-
 	// Write decrypted data to output file
-	err = ioutil.WriteFile(outputFile, decompressedData.Bytes(), 0644)
+	err = ioutil.WriteFile(outputFile, decompressedData, 0644)
 	if err != nil {
 		log.Fatalf("Failed to write decrypted data to file: %v", err)
 	}
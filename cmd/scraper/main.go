@@ -6,20 +6,14 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"os"
-	"regexp"
-	"strconv"
-	"strings"
 
 	"github.com/PuerkitoBio/goquery"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"io/ioutil"
 
+	"github.com/SqiSch/lpic-cli/internal/database"
 	"github.com/SqiSch/lpic-cli/internal/types"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 type CertificationSetUrls struct {
@@ -33,6 +27,8 @@ type CertificationSetConfig struct {
 	CertificationName        string `yaml:"certification_name"`
 	CertificationDescription string `yaml:"certification_description"`
 	Urls                     []CertificationSetUrls
+	// Profile says how to parse the pages, defaultProfile if missing.
+	Profile *Profile `yaml:"profile"`
 }
 
 func readCertificationSetConfig(filePath string) (*CertificationSetConfig, error) {
@@ -45,6 +41,12 @@ func readCertificationSetConfig(filePath string) (*CertificationSetConfig, error
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, err
 	}
+	if config.Profile == nil {
+		config.Profile = defaultProfile()
+	}
+	if err := config.Profile.compile(); err != nil {
+		return nil, err
+	}
 
 	return &config, nil
 }
@@ -53,8 +55,19 @@ func main() {
 	// Command-line arguments
 	cookies := flag.String("cookies", "", "Cookies to include in the request")
 	configFile := flag.String("config", "lpic11.yaml", "Path to the YAML configuration file")
+	sinkName := flag.String("sink", "mongo", "Where to write the scraped questions: json, mongo or stdout")
+	output := flag.String("o", "dataset.json", "Dataset file of the json sink, the certification is replaced or added. Files ending in .enc are encrypted")
+	keyFile := flag.String("keyFile", "", "File containing the AES key used for .enc datasets. If empty $AES_KEY is used")
+	mongoURI := flag.String("mongoURI", database.MongoURIFromEnv(), "MongoDB URI of the mongo sink")
+	mongoDatabase := flag.String("mongoDatabase", "certificationDB", "MongoDB database of the mongo sink")
+	mongoCollection := flag.String("mongoCollection", "questions", "MongoDB collection of the mongo sink")
 	flag.Parse()
 
+	sink, err := newSink(*sinkName, *output, *keyFile, *mongoURI, *mongoDatabase, *mongoCollection)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Read the YAML configuration
 	configFilePath := configFile
 	certificationSetConfig, err := readCertificationSetConfig(*configFilePath)
@@ -76,26 +89,7 @@ func main() {
 	certificationSet.Questions = make(map[int]*types.Question)
 	certificationSet.Testsets = make(map[string]types.Testset)
 
-	// Get MongoDB credentials from environment variables
-	mongoUser := os.Getenv("MONGO_USER")
-	mongoPassword := os.Getenv("MONGO_PASSWORD")
-	mongoURI := "mongodb://localhost:27017"
-	if mongoUser != "" && mongoPassword != "" {
-		mongoURI = "mongodb://" + mongoUser + ":" + mongoPassword + "@localhost:27017"
-	}
-
-	// Connect to MongoDB
-	clientOptions := options.Client().ApplyURI(mongoURI)
-	client, err := mongo.Connect(context.TODO(), clientOptions)
-	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB: %v", err)
-	}
-	defer client.Disconnect(context.TODO())
-
-	collection := client.Database("certificationDB").Collection("questions")
-
-	// Regex to remove question prefix like "Q. 17  "
-	questionPrefixRegex := regexp.MustCompile(`^Q\.\s*\d+\s+`)
+	profile := certificationSetConfig.Profile
 
 	// Split URLs and scrape each
 	for _, certsetConfig := range certificationSetConfig.Urls {
@@ -131,23 +125,14 @@ func main() {
 				continue
 			}
 
-			// if url paramter contains type=fulllength then extract the testset param fromt the url
 			urlObj, err := url.Parse(urlstring)
 			if err != nil {
 				log.Printf("Failed to parse URL %s: %v", urlstring, err)
 				continue
 			}
 
-			queryParams := urlObj.Query()
-			testset := queryParams.Get("testset")
-			if testset == "" {
-				log.Printf("No testset parameter found in URL %s", urlstring)
-				continue
-			}
-
-			testType := queryParams.Get("type")
-			if testType == "" {
-				log.Printf("No type parameter found in URL %s", urlstring)
+			if param := profile.missingParam(urlObj); param != "" {
+				log.Printf("No %s parameter found in URL %s", param, urlstring)
 				continue
 			}
 
@@ -159,49 +144,21 @@ func main() {
 			}
 
 			// Extract questions and answers
-			doc.Find(".card-group").Each(func(i int, s *goquery.Selection) {
-				questionText := strings.TrimSpace(s.Find(".card-header h6").Text())
-				questionText = questionPrefixRegex.ReplaceAllString(questionText, "")
-				explanation := strings.TrimSpace(s.Find(".explanation").Text())
-				explanation = strings.ReplaceAll(explanation, "Explanation:-  ", "")
-				questionID, _ := s.Find("input[name^='question']").Attr("value")
-				questionIDInt, _ := strconv.Atoi(questionID)
-
-				var answers []*types.Answer
-				s.Find(".card-content .radio").Each(func(j int, a *goquery.Selection) {
-					answerText := strings.TrimSpace(a.Find("span").Text())
-					isCorrect := a.Find("input").AttrOr("val", "0") == "1"
-					answerID, _ := a.Find("input").Attr("value")
-
-					answers = append(answers, &types.Answer{
-						Text:      answerText,
-						IsCorrect: isCorrect,
-						AnswerID:  answerID,
-					})
-				})
-
-				question := types.Question{
-					ID:          questionIDInt,
-					Text:        questionText,
-					Answers:     answers,
-					Explanation: explanation,
-				}
-
-				certificationSet.Questions[question.ID] = &question
-				testsetObj.QuestionsIds = append(testsetObj.QuestionsIds, questionIDInt)
-			})
+			questions := profile.parse(doc)
+			if len(questions) == 0 {
+				log.Printf("No questions found in URL %s, check the profile selectors", urlstring)
+			}
+			for _, question := range questions {
+				certificationSet.Questions[question.ID] = question
+				testsetObj.QuestionsIds = append(testsetObj.QuestionsIds, question.ID)
+			}
 
 			certificationSet.Testsets[setId] = testsetObj
 		}
 	}
 
-	// Save to MongoDB
-	filter := bson.M{"_id": certificationSetConfig.CertificationID}
-	update := bson.M{"$set": certificationSet}
-	_, err = collection.UpdateOne(context.TODO(), filter, update, options.Update().SetUpsert(true))
-	if err != nil {
-		log.Printf("Failed to update certification set: %v", err)
-	} else {
-		log.Printf("Updated certification set: %s", certificationSetConfig.CertificationID)
+	if err := sink.Write(context.TODO(), &certificationSet); err != nil {
+		log.Fatalf("Failed to write certification set to %s: %v", sink, err)
 	}
+	log.Printf("Wrote %d questions of certification set %s to %s", len(certificationSet.Questions), certificationSetConfig.CertificationID, sink)
 }
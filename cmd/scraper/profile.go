@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+
+	"github.com/SqiSch/lpic-cli/internal/authoring"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// Profile describes how the questions are found in the pages of a practice
+// site. Selectors are CSS selectors; all but Question are relative to the
+// element of a question, the answer ones to the element of an answer.
+type Profile struct {
+	// Question selects one element per question.
+	Question string `yaml:"question"`
+	// Text selects the question text.
+	Text string `yaml:"text"`
+	// Explanation selects the explanation, optional.
+	Explanation string `yaml:"explanation"`
+	// ID selects the element whose IDAttr holds the numeric question ID.
+	// Questions without one get an ID derived from their text.
+	ID     string `yaml:"id"`
+	IDAttr string `yaml:"id_attr"`
+	// Answer selects one element per answer option.
+	Answer string `yaml:"answer"`
+	// AnswerText selects the text of an answer, the whole answer element if
+	// empty.
+	AnswerText string `yaml:"answer_text"`
+	// AnswerID selects the element whose AnswerIDAttr holds the answer ID.
+	// Answers without one get an ID derived from their text.
+	AnswerID     string `yaml:"answer_id"`
+	AnswerIDAttr string `yaml:"answer_id_attr"`
+	// Correct selects the element marking a correct answer, the answer
+	// element itself or one in it. With CorrectAttr the answer is correct if
+	// the attribute is CorrectValue, otherwise if the element exists, e.g.
+	// "input[checked]" or ".correct".
+	Correct      string `yaml:"correct"`
+	CorrectAttr  string `yaml:"correct_attr"`
+	CorrectValue string `yaml:"correct_value"`
+	// Cleanup lists regular expressions whose matches are removed from the
+	// text, the explanation and the answers, e.g. a "Q. 17" prefix.
+	Cleanup struct {
+		Text        []string `yaml:"text"`
+		Explanation []string `yaml:"explanation"`
+		Answer      []string `yaml:"answer"`
+	} `yaml:"cleanup"`
+	// RequiredParams are query parameters a URL must have to be scraped.
+	RequiredParams []string `yaml:"required_params"`
+
+	textCleanup, explanationCleanup, answerCleanup []*regexp.Regexp
+}
+
+// defaultProfile is used by configs without a profile. It parses the site
+// the configs in cmd/scraper/config were written for.
+func defaultProfile() *Profile {
+	p := &Profile{
+		Question:       ".card-group",
+		Text:           ".card-header h6",
+		Explanation:    ".explanation",
+		ID:             "input[name^='question']",
+		IDAttr:         "value",
+		Answer:         ".card-content .radio",
+		AnswerText:     "span",
+		AnswerID:       "input",
+		AnswerIDAttr:   "value",
+		Correct:        "input",
+		CorrectAttr:    "val",
+		CorrectValue:   "1",
+		RequiredParams: []string{"testset", "type"},
+	}
+	p.Cleanup.Text = []string{`^Q\.\s*\d+\s+`}
+	p.Cleanup.Explanation = []string{`Explanation:-  `}
+	return p
+}
+
+// compile checks the selectors and compiles the cleanup expressions.
+func (p *Profile) compile() error {
+	for _, field := range []struct {
+		name, selector string
+		required       bool
+	}{
+		{"question", p.Question, true},
+		{"text", p.Text, true},
+		{"explanation", p.Explanation, false},
+		{"id", p.ID, false},
+		{"answer", p.Answer, true},
+		{"answer_text", p.AnswerText, false},
+		{"answer_id", p.AnswerID, false},
+		{"correct", p.Correct, true},
+	} {
+		if field.selector == "" {
+			if field.required {
+				return fmt.Errorf("profile: %s selector is missing", field.name)
+			}
+			continue
+		}
+		if _, err := cascadia.Compile(field.selector); err != nil {
+			return fmt.Errorf("profile: %s selector %q: %v", field.name, field.selector, err)
+		}
+	}
+	if p.ID != "" && p.IDAttr == "" {
+		return fmt.Errorf("profile: id_attr is missing")
+	}
+	if p.AnswerID != "" && p.AnswerIDAttr == "" {
+		return fmt.Errorf("profile: answer_id_attr is missing")
+	}
+	var err error
+	if p.textCleanup, err = compileCleanup("text", p.Cleanup.Text); err != nil {
+		return err
+	}
+	if p.explanationCleanup, err = compileCleanup("explanation", p.Cleanup.Explanation); err != nil {
+		return err
+	}
+	p.answerCleanup, err = compileCleanup("answer", p.Cleanup.Answer)
+	return err
+}
+
+func compileCleanup(field string, patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("profile: cleanup %s: %v", field, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// missingParam returns the first required query parameter u lacks.
+func (p *Profile) missingParam(u *url.URL) string {
+	query := u.Query()
+	for _, param := range p.RequiredParams {
+		if query.Get(param) == "" {
+			return param
+		}
+	}
+	return ""
+}
+
+// parse returns the questions of doc in page order.
+func (p *Profile) parse(doc *goquery.Document) []*types.Question {
+	var questions []*types.Question
+	doc.Find(p.Question).Each(func(i int, s *goquery.Selection) {
+		questionText := clean(s.Find(p.Text).Text(), p.textCleanup)
+		var explanation string
+		if p.Explanation != "" {
+			explanation = clean(s.Find(p.Explanation).Text(), p.explanationCleanup)
+		}
+		questionID := 0
+		if p.ID != "" {
+			questionID, _ = strconv.Atoi(strings.TrimSpace(s.Find(p.ID).AttrOr(p.IDAttr, "")))
+		}
+		if questionID == 0 {
+			questionID = authoring.QuestionID(questionText)
+		}
+
+		var answers []*types.Answer
+		s.Find(p.Answer).Each(func(j int, a *goquery.Selection) {
+			text := a
+			if p.AnswerText != "" {
+				text = a.Find(p.AnswerText)
+			}
+			answerText := clean(text.Text(), p.answerCleanup)
+			var answerID string
+			if p.AnswerID != "" {
+				answerID = strings.TrimSpace(a.Find(p.AnswerID).AttrOr(p.AnswerIDAttr, ""))
+			}
+			if answerID == "" {
+				answerID = authoring.AnswerID(answerText)
+			}
+			answers = append(answers, &types.Answer{
+				Text:      answerText,
+				IsCorrect: p.isCorrect(a),
+				AnswerID:  answerID,
+			})
+		})
+
+		questions = append(questions, &types.Question{
+			ID:          questionID,
+			Text:        questionText,
+			Answers:     answers,
+			Explanation: explanation,
+		})
+	})
+	return questions
+}
+
+func (p *Profile) isCorrect(answer *goquery.Selection) bool {
+	marker := answer.Filter(p.Correct)
+	if marker.Length() == 0 {
+		marker = answer.Find(p.Correct)
+	}
+	if p.CorrectAttr == "" {
+		return marker.Length() > 0
+	}
+	value, ok := marker.Attr(p.CorrectAttr)
+	return ok && value == p.CorrectValue
+}
+
+// clean removes the matches of cleanup from text and trims it.
+func clean(text string, cleanup []*regexp.Regexp) string {
+	text = strings.TrimSpace(text)
+	for _, re := range cleanup {
+		text = re.ReplaceAllString(text, "")
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/SqiSch/lpic-cli/internal/authoring"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+func parsePage(t *testing.T, profile *Profile, page string) []*types.Question {
+	t.Helper()
+	if err := profile.compile(); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return profile.parse(doc)
+}
+
+func TestDefaultProfileParse(t *testing.T) {
+	page := `<html><body>
+<div class="card-group">
+  <div class="card-header"><h6>Q. 17 Which command lists the loaded kernel modules?</h6></div>
+  <input type="hidden" name="question[17]" value="4711">
+  <div class="card-content">
+    <div class="radio"><input value="a1" val="1"><span> lsmod </span></div>
+    <div class="radio"><input value="a2" val="0"><span>modprobe -l</span></div>
+  </div>
+  <div class="explanation">Explanation:-  lsmod reads /proc/modules.</div>
+</div>
+<div class="card-group">
+  <div class="card-header"><h6>Q. 18 Which file lists the mounted filesystems?</h6></div>
+  <div class="card-content">
+    <div class="radio"><input val="0"><span>/etc/fstab</span></div>
+    <div class="radio"><input val="1"><span>/etc/mtab</span></div>
+  </div>
+</div>
+</body></html>`
+	questions := parsePage(t, defaultProfile(), page)
+	want := []*types.Question{
+		{
+			ID:   4711,
+			Text: "Which command lists the loaded kernel modules?",
+			Answers: []*types.Answer{
+				{Text: "lsmod", IsCorrect: true, AnswerID: "a1"},
+				{Text: "modprobe -l", AnswerID: "a2"},
+			},
+			Explanation: "lsmod reads /proc/modules.",
+		},
+		{
+			// no ID on the page
+			ID:   authoring.QuestionID("Which file lists the mounted filesystems?"),
+			Text: "Which file lists the mounted filesystems?",
+			Answers: []*types.Answer{
+				{Text: "/etc/fstab", AnswerID: authoring.AnswerID("/etc/fstab")},
+				{Text: "/etc/mtab", IsCorrect: true, AnswerID: authoring.AnswerID("/etc/mtab")},
+			},
+		},
+	}
+	if !reflect.DeepEqual(questions, want) {
+		t.Errorf("parse() = %s, want %s", dump(questions), dump(want))
+	}
+}
+
+func TestCustomProfileParse(t *testing.T) {
+	profile := &Profile{
+		Question: "article",
+		Text:     "p.q",
+		Answer:   "li",
+		Correct:  ".correct",
+	}
+	profile.Cleanup.Answer = []string{`^[A-D]\)\s*`}
+	page := `<article><p class="q">Which signal is sent by kill by default?</p><ul>
+<li>A) SIGKILL</li><li class="correct">B) SIGTERM</li><li>C) <b class="correct-not">SIGHUP</b></li>
+</ul></article>`
+	questions := parsePage(t, profile, page)
+	if len(questions) != 1 {
+		t.Fatalf("parse() = %d questions, want 1", len(questions))
+	}
+	var texts []string
+	var correct []string
+	for _, answer := range questions[0].Answers {
+		texts = append(texts, answer.Text)
+		if answer.IsCorrect {
+			correct = append(correct, answer.Text)
+		}
+	}
+	if !reflect.DeepEqual(texts, []string{"SIGKILL", "SIGTERM", "SIGHUP"}) || !reflect.DeepEqual(correct, []string{"SIGTERM"}) {
+		t.Errorf("answers = %q with %q correct, want SIGTERM correct", texts, correct)
+	}
+}
+
+func TestProfileCompileErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(p *Profile)
+		want   string
+	}{
+		{"missing selector", func(p *Profile) { p.Answer = "" }, "profile: answer selector is missing"},
+		{"invalid selector", func(p *Profile) { p.Text = "h6[" }, `profile: text selector "h6["`},
+		{"id without attribute", func(p *Profile) { p.IDAttr = "" }, "profile: id_attr is missing"},
+		{"answer id without attribute", func(p *Profile) { p.AnswerIDAttr = "" }, "profile: answer_id_attr is missing"},
+		{"invalid cleanup", func(p *Profile) { p.Cleanup.Answer = []string{"("} }, "profile: cleanup answer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := defaultProfile()
+			tt.change(profile)
+			if err := profile.compile(); err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("compile() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	if err := defaultProfile().compile(); err != nil {
+		t.Errorf("default profile: %v", err)
+	}
+}
+
+func TestMissingParam(t *testing.T) {
+	profile := defaultProfile()
+	for rawURL, want := range map[string]string{
+		"https://example.com/exam?testset=3&type=full": "",
+		"https://example.com/exam?type=full":           "testset",
+		"https://example.com/exam?testset=3&type=":     "type",
+	} {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := profile.missingParam(u); got != want {
+			t.Errorf("missingParam(%s) = %q, want %q", rawURL, got, want)
+		}
+	}
+}
+
+func dump(questions []*types.Question) string {
+	var b strings.Builder
+	for _, question := range questions {
+		b.WriteString("\n")
+		b.WriteString(strings.TrimSpace(question.Text))
+		for _, answer := range question.Answers {
+			b.WriteString("\n  " + answer.AnswerID + " " + answer.Text)
+			if answer.IsCorrect {
+				b.WriteString(" (correct)")
+			}
+		}
+	}
+	return b.String()
+}
+
+func TestBundledConfigs(t *testing.T) {
+	files, err := filepath.Glob("config/*.yaml")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no bundled configs: %v", err)
+	}
+	for _, file := range files {
+		config, err := readCertificationSetConfig(file)
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+		if config.CertificationID == "" || len(config.Urls) == 0 || len(config.Urls[0].Urls) == 0 {
+			t.Errorf("%s: got %+v, want a certification with URLs", file, config)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/SqiSch/lpic-cli/internal/database"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// Sink stores a scraped certification set, replacing an earlier scrape of
+// the same certification.
+type Sink interface {
+	Write(ctx context.Context, certSet *types.CertificationSet) error
+	// String describes where the set is written to, for the log.
+	String() string
+}
+
+// newSink returns the sink named by the -sink flag.
+func newSink(name, output, keyFile, mongoURI, mongoDatabase, mongoCollection string) (Sink, error) {
+	switch name {
+	case "json":
+		return &jsonSink{path: output, keyFile: keyFile}, nil
+	case "mongo":
+		return &mongoSink{uri: mongoURI, database: mongoDatabase, collection: mongoCollection}, nil
+	case "stdout":
+		return stdoutSink{}, nil
+	}
+	return nil, fmt.Errorf("unknown sink %q, expected json, mongo or stdout", name)
+}
+
+// jsonSink merges the set into a dataset file the client loads with
+// -dbfile. Files ending in .enc are decrypted and encrypted again.
+type jsonSink struct {
+	path    string
+	keyFile string
+}
+
+func (s *jsonSink) Write(ctx context.Context, certSet *types.CertificationSet) error {
+	var certSets []*types.CertificationSet
+	if _, err := os.Stat(s.path); err == nil {
+		if certSets, err = database.LoadFullData(s.path, s.keyFile); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	replaced := false
+	for i, other := range certSets {
+		if other.CertificationID == certSet.CertificationID {
+			certSets[i] = certSet
+			replaced = true
+		}
+	}
+	if !replaced {
+		certSets = append(certSets, certSet)
+	}
+
+	data, err := json.MarshalIndent(certSets, "", "  ")
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(s.path, database.EncryptedSuffix) {
+		key, err := database.LoadKey(s.keyFile)
+		if err != nil {
+			return err
+		}
+		if data, err = database.EncryptDataset(data, key); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
+
+func (s *jsonSink) String() string {
+	return s.path
+}
+
+// mongoSink upserts the set into a collection keyed by certification ID,
+// which cmd/crypt encrypts to a dataset file.
+type mongoSink struct {
+	uri        string
+	database   string
+	collection string
+}
+
+func (s *mongoSink) Write(ctx context.Context, certSet *types.CertificationSet) error {
+	client, err := database.MongoConnectURI(ctx, s.uri)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(context.Background())
+
+	collection := client.Database(s.database).Collection(s.collection)
+	filter := bson.M{"_id": certSet.CertificationID}
+	update := bson.M{"$set": certSet}
+	_, err = collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (s *mongoSink) String() string {
+	return fmt.Sprintf("MongoDB collection %s.%s", s.database, s.collection)
+}
+
+// stdoutSink prints the set as a dataset with one certification.
+type stdoutSink struct{}
+
+func (stdoutSink) Write(ctx context.Context, certSet *types.CertificationSet) error {
+	data, err := json.MarshalIndent([]*types.CertificationSet{certSet}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(data))
+	return err
+}
+
+func (stdoutSink) String() string {
+	return "stdout"
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/database"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+func TestNewSink(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"json", "dataset.json"},
+		{"mongo", "MongoDB collection certificationDB.questions"},
+		{"stdout", "stdout"},
+	}
+	for _, tt := range tests {
+		sink, err := newSink(tt.name, "dataset.json", "", "mongodb://localhost", "certificationDB", "questions")
+		if err != nil {
+			t.Fatalf("newSink(%q): %v", tt.name, err)
+		}
+		if sink.String() != tt.want {
+			t.Errorf("newSink(%q) writes to %s, want %s", tt.name, sink, tt.want)
+		}
+	}
+	if _, err := newSink("csv", "dataset.json", "", "", "", ""); err == nil {
+		t.Error("newSink accepted an unknown sink")
+	}
+}
+
+func scraped(certificationID string, questionIDs ...int) *types.CertificationSet {
+	certSet := &types.CertificationSet{CertificationID: certificationID, Questions: make(map[int]*types.Question)}
+	for _, id := range questionIDs {
+		certSet.Questions[id] = &types.Question{ID: id, Text: "Question", Answers: []*types.Answer{{AnswerID: "a", Text: "ls", IsCorrect: true}}}
+	}
+	return certSet
+}
+
+func TestJSONSink(t *testing.T) {
+	ctx := context.Background()
+	for _, name := range []string{"dataset.json", "dataset.json" + database.EncryptedSuffix} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			keyFile := filepath.Join(dir, "key")
+			if err := os.WriteFile(keyFile, []byte("0123456789abcdef0123456789abcdef\n"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			sink := &jsonSink{path: filepath.Join(dir, name), keyFile: keyFile}
+
+			// the first scrape creates the file, another certification is
+			// added and a new scrape of the first one replaces it
+			for _, certSet := range []*types.CertificationSet{
+				scraped("lpic1-101-500", 1, 2),
+				scraped("lpic1-102-500", 3),
+				scraped("lpic1-101-500", 4),
+			} {
+				if err := sink.Write(ctx, certSet); err != nil {
+					t.Fatalf("Write(%s): %v", certSet.CertificationID, err)
+				}
+			}
+
+			certSets, err := database.LoadFullData(sink.path, keyFile)
+			if err != nil {
+				t.Fatalf("LoadFullData: %v", err)
+			}
+			if len(certSets) != 2 {
+				t.Fatalf("dataset has %d certifications, want 2", len(certSets))
+			}
+			for i, want := range []struct {
+				id        string
+				questions []int
+			}{
+				{"lpic1-101-500", []int{4}},
+				{"lpic1-102-500", []int{3}},
+			} {
+				certSet := certSets[i]
+				if certSet.CertificationID != want.id || len(certSet.Questions) != len(want.questions) || certSet.Questions[want.questions[0]] == nil {
+					t.Errorf("certification %d = %s with %d questions, want %s with questions %v",
+						i, certSet.CertificationID, len(certSet.Questions), want.id, want.questions)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONSinkUnreadableDataset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dataset.json")
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sink := &jsonSink{path: path}
+	if err := sink.Write(context.Background(), scraped("lpic1-101-500", 1)); err == nil {
+		t.Error("Write replaced a dataset it could not read")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "not json" {
+		t.Errorf("the dataset was overwritten with %q", data)
+	}
+}
@@ -0,0 +1,248 @@
+// Package anki writes questions as an Anki deck package (.apkg), which can be
+// imported by the Anki desktop and mobile apps.
+package anki
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Fields are the note fields of the question model, in this order.
+var Fields = []string{"Question", "Options", "Correct", "Explanation"}
+
+// modelID identifies the question note type. It must not change, so that
+// importing a newer export updates the notes of an older one.
+const modelID = 1735689600001
+
+// Note is a single flash card.
+type Note struct {
+	// GUID identifies the note across exports, see NoteGUID.
+	GUID string
+	// Fields holds the HTML of the fields named in Fields.
+	Fields []string
+	Tags   []string
+}
+
+// Deck is the content of a package. Subdecks are separated by "::".
+type Deck struct {
+	Name  string
+	Notes []Note
+}
+
+// DeckID returns the stable ID of the deck called name.
+func DeckID(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	// Anki stores IDs as JavaScript numbers, so they have to fit in 53 bits.
+	return int64(h.Sum64()>>11) | 1
+}
+
+// WritePackage writes deck as an .apkg file to w. The collection is built in a
+// temporary SQLite database, which is removed afterwards.
+func WritePackage(w io.Writer, deck Deck) error {
+	dir, err := os.MkdirTemp("", "lpic-anki-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "collection.anki2")
+	if err := writeCollection(path, deck, time.Now()); err != nil {
+		return err
+	}
+	collection, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{"collection.anki2", collection},
+		// The package ships no images or sounds.
+		{"media", []byte("{}")},
+	} {
+		f, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(file.data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// collectionSchema is the schema of Anki collections of version 11, which
+// all Anki versions can import.
+const collectionSchema = `
+CREATE TABLE col (
+	id integer primary key, crt integer not null, mod integer not null, scm integer not null,
+	ver integer not null, dty integer not null, usn integer not null, ls integer not null,
+	conf text not null, models text not null, decks text not null, dconf text not null, tags text not null
+);
+CREATE TABLE notes (
+	id integer primary key, guid text not null, mid integer not null, mod integer not null,
+	usn integer not null, tags text not null, flds text not null, sfld integer not null,
+	csum integer not null, flags integer not null, data text not null
+);
+CREATE TABLE cards (
+	id integer primary key, nid integer not null, did integer not null, ord integer not null,
+	mod integer not null, usn integer not null, type integer not null, queue integer not null,
+	due integer not null, ivl integer not null, factor integer not null, reps integer not null,
+	lapses integer not null, left integer not null, odue integer not null, odid integer not null,
+	flags integer not null, data text not null
+);
+CREATE TABLE revlog (
+	id integer primary key, cid integer not null, usn integer not null, ease integer not null,
+	ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null,
+	type integer not null
+);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn ON notes (usn);
+CREATE INDEX ix_cards_usn ON cards (usn);
+CREATE INDEX ix_revlog_usn ON revlog (usn);
+CREATE INDEX ix_cards_nid ON cards (nid);
+CREATE INDEX ix_cards_sched ON cards (did, queue, due);
+CREATE INDEX ix_revlog_cid ON revlog (cid);
+CREATE INDEX ix_notes_csum ON notes (csum);
+`
+
+const cardCSS = `.card { font-family: arial; font-size: 18px; text-align: left; color: black; background-color: white; }
+.correct { color: #2e7d32; font-weight: bold; }
+.explanation { font-size: 16px; color: #555; }`
+
+func writeCollection(path string, deck Deck, now time.Time) error {
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(collectionSchema); err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	deckID := DeckID(deck.Name)
+	conf, models, decks, dconf, err := collectionJSON(deck.Name, deckID, now)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		now.Unix(), now.UnixMilli(), now.UnixMilli(), conf, models, decks, dconf); err != nil {
+		return fmt.Errorf("failed to write collection: %w", err)
+	}
+
+	// Note and card IDs are creation times in milliseconds, kept unique by
+	// counting up from now.
+	baseID := now.UnixMilli()
+	for i, note := range deck.Notes {
+		if len(note.Fields) != len(Fields) {
+			return fmt.Errorf("note %s has %d fields, expected %d", note.GUID, len(note.Fields), len(Fields))
+		}
+		id := baseID + int64(i)
+		sortField := stripHTML(note.Fields[0])
+		tags := ""
+		if len(note.Tags) > 0 {
+			tags = " " + strings.Join(note.Tags, " ") + " "
+		}
+		if _, err := tx.Exec(`INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')`,
+			id, note.GUID, modelID, now.Unix(), tags, strings.Join(note.Fields, "\x1f"), sortField, checksum(sortField)); err != nil {
+			return fmt.Errorf("failed to write note %s: %w", note.GUID, err)
+		}
+		// New cards are shown in the order of the deck.
+		if _, err := tx.Exec(`INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+			id, id, deckID, now.Unix(), i+1); err != nil {
+			return fmt.Errorf("failed to write card of note %s: %w", note.GUID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func collectionJSON(deckName string, deckID int64, now time.Time) (conf, models, decks, dconf string, err error) {
+	fields := make([]map[string]any, len(Fields))
+	for i, name := range Fields {
+		fields[i] = map[string]any{"name": name, "ord": i, "sticky": false, "rtl": false, "font": "Arial", "size": 20, "media": []any{}}
+	}
+	model := map[string]any{
+		"id": modelID, "name": "lpic-cli question", "type": 0, "mod": now.Unix(), "usn": -1,
+		"sortf": 0, "did": deckID, "flds": fields, "css": cardCSS, "tags": []any{}, "vers": []any{},
+		"tmpls": []map[string]any{{
+			"name": "Card 1", "ord": 0, "did": nil, "bqfmt": "", "bafmt": "",
+			"qfmt": "{{Question}}<hr>{{Options}}",
+			"afmt": "{{FrontSide}}<hr id=answer><div class=correct>{{Correct}}</div>" +
+				"{{#Explanation}}<hr><div class=explanation>{{Explanation}}</div>{{/Explanation}}",
+		}},
+		"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		// The card needs the question field to be generated.
+		"req": []any{[]any{0, "any", []any{0}}},
+	}
+	deckJSON := func(id int64, name string) map[string]any {
+		return map[string]any{
+			"id": id, "name": name, "desc": "", "mod": now.Unix(), "usn": -1, "collapsed": false,
+			"browserCollapsed": false, "newToday": []int{0, 0}, "revToday": []int{0, 0}, "lrnToday": []int{0, 0},
+			"timeToday": []int{0, 0}, "dyn": 0, "conf": 1, "extendNew": 10, "extendRev": 50,
+		}
+	}
+	values := []any{
+		map[string]any{
+			"activeDecks": []int64{deckID}, "curDeck": deckID, "newSpread": 0, "collapseTime": 1200, "timeLim": 0,
+			"estTimes": true, "dueCounts": true, "curModel": strconv.FormatInt(modelID, 10), "nextPos": 1,
+			"sortType": "noteFld", "sortBackwards": false, "addToCur": true,
+		},
+		map[string]any{strconv.FormatInt(modelID, 10): model},
+		map[string]any{"1": deckJSON(1, "Default"), strconv.FormatInt(deckID, 10): deckJSON(deckID, deckName)},
+		map[string]any{"1": map[string]any{
+			"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true, "timer": 0, "replayq": true,
+			"new":   map[string]any{"perDay": 20, "delays": []int{1, 10}, "separate": true, "ints": []int{1, 4, 7}, "initialFactor": 2500, "bury": true, "order": 1},
+			"rev":   map[string]any{"perDay": 100, "fuzz": 0.05, "ivlFct": 1, "maxIvl": 36500, "ease4": 1.3, "bury": true, "minSpace": 1},
+			"lapse": map[string]any{"leechFails": 8, "minInt": 1, "delays": []int{10}, "leechAction": 0, "mult": 0},
+		}},
+	}
+	encoded := make([]string, len(values))
+	for i, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", "", "", "", err
+		}
+		encoded[i] = string(data)
+	}
+	return encoded[0], encoded[1], encoded[2], encoded[3], nil
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+func stripHTML(s string) string {
+	return strings.TrimSpace(html.UnescapeString(htmlTag.ReplaceAllString(s, " ")))
+}
+
+// checksum is the first 8 hex digits of the SHA-1 of the sort field, which
+// Anki uses to find duplicates.
+func checksum(sortField string) int64 {
+	sum := sha1.Sum([]byte(sortField))
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
@@ -0,0 +1,144 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeckID(t *testing.T) {
+	id := DeckID("LPIC-1::101")
+	if id <= 0 || id >= 1<<53 || id%2 == 0 {
+		t.Errorf("DeckID() = %d, want an odd number below 2^53", id)
+	}
+	if again := DeckID("LPIC-1::101"); again != id {
+		t.Errorf("DeckID() changed from %d to %d", id, again)
+	}
+	if other := DeckID("LPIC-1::102"); other == id {
+		t.Errorf("two decks got the ID %d", id)
+	}
+}
+
+func testDeck() Deck {
+	return Deck{Name: "LPIC-1::101", Notes: []Note{
+		{GUID: "lpic-cli/lpic1-101-500/1", Fields: []string{"<b>ls</b> &amp; more", "<ol></ol>", "A", ""}, Tags: []string{"lpic1-101-500", TagWrong}},
+		{GUID: "lpic-cli/lpic1-101-500/2", Fields: []string{"Second", "<ol></ol>", "B", "why"}},
+	}}
+}
+
+func TestWritePackage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePackage(&buf, testDeck()); err != nil {
+		t.Fatalf("WritePackage: %v", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	for _, file := range archive.File {
+		f, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[file.Name] = data
+	}
+	if len(files) != 2 || string(files["media"]) != "{}" {
+		t.Fatalf("package files = %v, want collection.anki2 and an empty media list", archiveNames(archive))
+	}
+
+	path := filepath.Join(t.TempDir(), "collection.anki2")
+	if err := os.WriteFile(path, files["collection.anki2"], 0644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var models, decks string
+	if err := db.QueryRow(`SELECT models, decks FROM col`).Scan(&models, &decks); err != nil {
+		t.Fatalf("reading the collection: %v", err)
+	}
+	var deckByID map[string]struct{ Name string }
+	if err := json.Unmarshal([]byte(decks), &deckByID); err != nil {
+		t.Fatalf("decks: %v", err)
+	}
+	deckID := DeckID("LPIC-1::101")
+	if deck := deckByID[strconv.FormatInt(deckID, 10)]; deck.Name != "LPIC-1::101" {
+		t.Errorf("decks = %s, want the deck LPIC-1::101", decks)
+	}
+	var modelByID map[string]struct{ Flds []struct{ Name string } }
+	if err := json.Unmarshal([]byte(models), &modelByID); err != nil {
+		t.Fatalf("models: %v", err)
+	}
+	if fields := modelByID[strconv.FormatInt(modelID, 10)].Flds; len(fields) != len(Fields) {
+		t.Errorf("model has %d fields, want %d", len(fields), len(Fields))
+	}
+
+	rows, err := db.Query(`SELECT n.guid, n.tags, n.flds, n.sfld, c.did, c.due FROM notes n JOIN cards c ON c.nid = n.id ORDER BY c.due`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var guid, tags, flds, sortField string
+		var did, due int64
+		if err := rows.Scan(&guid, &tags, &flds, &sortField, &did, &due); err != nil {
+			t.Fatal(err)
+		}
+		if did != deckID {
+			t.Errorf("card of %s is in deck %d, want %d", guid, did, deckID)
+		}
+		got = append(got, strings.Join([]string{guid, tags, strings.ReplaceAll(flds, "\x1f", "|"), sortField, strconv.FormatInt(due, 10)}, " / "))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"lpic-cli/lpic1-101-500/1 /  lpic1-101-500 wrong  / <b>ls</b> &amp; more|<ol></ol>|A| / ls  & more / 1",
+		"lpic-cli/lpic1-101-500/2 /  / Second|<ol></ol>|B|why / Second / 2",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("notes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func archiveNames(archive *zip.Reader) []string {
+	names := make([]string, 0, len(archive.File))
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+func TestWriteCollectionFieldCount(t *testing.T) {
+	deck := testDeck()
+	deck.Notes[1].Fields = deck.Notes[1].Fields[:2]
+	err := writeCollection(filepath.Join(t.TempDir(), "collection.anki2"), deck, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "lpic-cli/lpic1-101-500/2 has 2 fields") {
+		t.Errorf("writeCollection() = %v, want an error about the fields of the second note", err)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	// the first 8 hex digits of the SHA-1 of "Second" are 2a4bcae6
+	if got := checksum("Second"); got != 0x2a4bcae6 {
+		t.Errorf("checksum() = %x, want 2a4bcae6", got)
+	}
+}
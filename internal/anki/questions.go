@@ -0,0 +1,96 @@
+package anki
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// Tags added to the notes of questions by their stored state.
+const (
+	TagWrong     = "wrong"
+	TagImportant = "important"
+)
+
+// NoteGUID returns the GUID of the note of a question. It is stable, so
+// re-importing an export updates the notes instead of duplicating them.
+func NoteGUID(certID string, questionID int) string {
+	return fmt.Sprintf("lpic-cli/%s/%d", certID, questionID)
+}
+
+// QuestionNote returns the note of question. It is tagged with the
+// certification, with TagWrong and TagImportant by the applied state and with
+// the personal tags of the question.
+func QuestionNote(certID string, question *types.Question) Note {
+	var options, correct strings.Builder
+	options.WriteString(`<ol type="A">`)
+	correct.WriteString("<ul>")
+	for i, answer := range question.Answers {
+		text := textHTML(answer.Text)
+		fmt.Fprintf(&options, "<li>%s</li>", text)
+		if answer.IsCorrect {
+			fmt.Fprintf(&correct, "<li>%c. %s</li>", 'A'+rune(i%26), text)
+		}
+	}
+	switch {
+	case question.Kind == types.QuestionKindOrdering && question.IsArranged():
+		for _, item := range question.InitialArrangement() {
+			fmt.Fprintf(&options, "<li>%s</li>", textHTML(question.Ordering[item]))
+		}
+	case question.Kind == types.QuestionKindMatching && question.IsArranged():
+		for _, option := range question.MatchOptions() {
+			fmt.Fprintf(&options, "<li>%s</li>", textHTML(option))
+		}
+	}
+	options.WriteString("</ol>")
+	if question.IsFillIn() || question.IsArranged() {
+		// the solution texts are in order, e.g. of the ordering items
+		for _, text := range question.SolutionTexts() {
+			fmt.Fprintf(&correct, "<li>%s</li>", textHTML(text))
+		}
+	}
+	correct.WriteString("</ul>")
+
+	questionHTML := textHTML(question.Text)
+	switch {
+	case question.IsFillIn():
+		questionHTML += "<br><i>Type the answer.</i>"
+	case question.Kind == types.QuestionKindOrdering && question.IsArranged():
+		questionHTML += "<br><i>Put the items in order.</i>"
+	case question.IsArranged():
+		lefts := make([]string, 0, len(question.Matching))
+		for _, pair := range question.Matching {
+			lefts = append(lefts, "<li>"+textHTML(pair.Left)+"</li>")
+		}
+		questionHTML += "<ol>" + strings.Join(lefts, "") + "</ol><i>Match each item with an option.</i>"
+	case !question.IsSingleAnswer():
+		questionHTML += "<br><i>Choose all correct answers.</i>"
+	}
+
+	tags := []string{tag(certID)}
+	if question.AnsweredState == types.AnsweredFalse {
+		tags = append(tags, TagWrong)
+	}
+	if question.GetIsImportant() {
+		tags = append(tags, TagImportant)
+	}
+	tags = append(tags, question.GetTags()...)
+
+	return Note{
+		GUID:   NoteGUID(certID, question.ID),
+		Fields: []string{questionHTML, options.String(), correct.String(), textHTML(question.Explanation)},
+		Tags:   tags,
+	}
+}
+
+// textHTML escapes text and keeps its line breaks.
+func textHTML(text string) string {
+	return strings.ReplaceAll(html.EscapeString(strings.TrimSpace(text)), "\n", "<br>")
+}
+
+// tag turns s into an Anki tag, which must not contain spaces.
+func tag(s string) string {
+	return strings.Join(strings.Fields(s), "_")
+}
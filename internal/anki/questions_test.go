@@ -0,0 +1,101 @@
+package anki
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+func TestQuestionNote(t *testing.T) {
+	question := &types.Question{
+		ID:   7,
+		Text: "Which commands show <files>?\nPick two.",
+		Answers: []*types.Answer{
+			{Text: "ls", IsCorrect: true},
+			{Text: "cd"},
+			{Text: "dir & more", IsCorrect: true},
+		},
+		Explanation: "dir is ls -C -b.",
+	}
+	question.AnsweredState = types.AnsweredFalse
+	question.SetIsImportant(true).SetTags([]string{"shell"})
+
+	note := QuestionNote("lpic1 101", question)
+	want := Note{
+		GUID: "lpic-cli/lpic1 101/7",
+		Fields: []string{
+			"Which commands show &lt;files&gt;?<br>Pick two.<br><i>Choose all correct answers.</i>",
+			`<ol type="A"><li>ls</li><li>cd</li><li>dir &amp; more</li></ol>`,
+			"<ul><li>A. ls</li><li>C. dir &amp; more</li></ul>",
+			"dir is ls -C -b.",
+		},
+		Tags: []string{"lpic1_101", TagWrong, TagImportant, "shell"},
+	}
+	if !reflect.DeepEqual(note, want) {
+		t.Errorf("QuestionNote() = %+v, want %+v", note, want)
+	}
+	if guid := QuestionNote("lpic1 101", question).GUID; guid != note.GUID {
+		t.Errorf("GUID changed from %q to %q", note.GUID, guid)
+	}
+}
+
+func TestQuestionNoteKinds(t *testing.T) {
+	tests := []struct {
+		name     string
+		question *types.Question
+		text     string
+		options  []string
+		correct  string
+	}{
+		{
+			name:     "single answer",
+			question: &types.Question{Text: "Q", Answers: []*types.Answer{{Text: "ls", IsCorrect: true}, {Text: "cd"}}},
+			options:  []string{"<li>ls</li><li>cd</li>"},
+			correct:  "<ul><li>A. ls</li></ul>",
+		},
+		{
+			name:     "fill-in",
+			question: &types.Question{Text: "Q", Kind: types.QuestionKindFillIn, FillIn: &types.FillIn{Accepted: []string{"uname -r", "uname --kernel-release"}}},
+			text:     "Q<br><i>Type the answer.</i>",
+			correct:  "<ul><li>uname -r</li><li>uname --kernel-release</li></ul>",
+		},
+		{
+			name:     "ordering",
+			question: &types.Question{Text: "Q", Kind: types.QuestionKindOrdering, Ordering: []string{"BIOS", "boot loader", "kernel"}},
+			text:     "Q<br><i>Put the items in order.</i>",
+			options:  []string{"<li>BIOS</li>", "<li>boot loader</li>", "<li>kernel</li>"},
+			correct:  "<ul><li>BIOS</li><li>boot loader</li><li>kernel</li></ul>",
+		},
+		{
+			name: "matching",
+			question: &types.Question{Text: "Q", Kind: types.QuestionKindMatching, Matching: []types.Pair{
+				{Left: "runlevel 3", Right: "multi-user.target"},
+				{Left: "runlevel 5", Right: "graphical.target"},
+			}},
+			text:    "Q<ol><li>runlevel 3</li><li>runlevel 5</li></ol><i>Match each item with an option.</i>",
+			options: []string{"<li>graphical.target</li><li>multi-user.target</li>"},
+			correct: "<ul><li>runlevel 3 → multi-user.target</li><li>runlevel 5 → graphical.target</li></ul>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := QuestionNote("lpic1-101-500", tt.question)
+			if tt.text != "" && note.Fields[0] != tt.text {
+				t.Errorf("Question = %q, want %q", note.Fields[0], tt.text)
+			}
+			for _, option := range tt.options {
+				if !strings.Contains(note.Fields[1], option) {
+					t.Errorf("Options = %q, want %q in it", note.Fields[1], option)
+				}
+			}
+			if note.Fields[2] != tt.correct {
+				t.Errorf("Correct = %q, want %q", note.Fields[2], tt.correct)
+			}
+			if !reflect.DeepEqual(note.Tags, []string{"lpic1-101-500"}) {
+				t.Errorf("Tags = %v, want only the certification", note.Tags)
+			}
+		})
+	}
+}
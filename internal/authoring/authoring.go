@@ -0,0 +1,478 @@
+// Package authoring compiles hand-written question files (YAML) into the
+// dataset format loaded by the client. See Compile for the file format.
+package authoring
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/SqiSch/lpic-cli/internal/objectives"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// AuthoredIDBase is the smallest ID derived from a question key. Scraped
+// questions keep the IDs of their source, which are far below.
+const AuthoredIDBase = 1_000_000_000
+
+// QuestionID returns the stable ID of the question with key. It does not
+// change when questions are reordered, reworded or moved to another file.
+func QuestionID(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return AuthoredIDBase + int(h.Sum32()%AuthoredIDBase)
+}
+
+// AnswerID returns the stable ID of an answer with text. It does not change
+// when answers are reordered, so stored marks keep pointing at the same answer.
+func AnswerID(text string) string {
+	h := fnv.New32a()
+	h.Write([]byte(strings.TrimSpace(text)))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+type authoredTestset struct {
+	node    *yaml.Node
+	testset types.Testset
+}
+
+type authoredQuestion struct {
+	node        *yaml.Node
+	key         string
+	idNode      *yaml.Node
+	question    *types.Question
+	answerNodes []*yaml.Node
+	testsets    []*yaml.Node
+	objectives  []*yaml.Node
+}
+
+type compiler struct {
+	file string
+	errs ErrorList
+}
+
+// Compile compiles the question file data, named file in error messages.
+// A question file holds one certification:
+//
+//	certification_id: team-101
+//	certification_name: Team questions for 101
+//	testsets:
+//	  - id: basics
+//	    name: Basics
+//	questions:
+//	  - key: ls-basics
+//	    text: Which command lists the files of a directory?
+//	    testsets: [basics]
+//	    objectives: [103.3]
+//	    answers:
+//	      - text: ls
+//	        correct: true
+//	      - text: cd
+//	    explanation: cd changes the directory.
+//	  - key: ls-long
+//	    text: Which command lists the files with their permissions?
+//	    testsets: [basics]
+//	    fill_in:
+//	      accepted: [ls -l, ls -la]
+//	      patterns: ['ls( -[a-z]*l[a-z]*)+']
+//
+// A question with fill_in is a fill-in-the-blank question answered by typing
+// the answer instead of marking answers. Besides accepted and patterns,
+// fill_in takes case_sensitive, exact_whitespace and command (see
+// types.FillIn). A question with ordering lists items in the correct order,
+// one with matching pairs:
+//
+//	questions:
+//	  - key: boot-order
+//	    text: Put the boot stages in order.
+//	    ordering: [firmware, boot loader, kernel, init]
+//	  - key: runlevel-targets
+//	    text: Match the runlevels to the systemd targets.
+//	    matching:
+//	      - left: runlevel 3
+//	        right: multi-user.target
+//	      - left: runlevel 5
+//	        right: graphical.target
+//
+// Question IDs are derived from the key (see QuestionID) unless an id is
+// given, answer IDs from the text (see AnswerID) unless an id is given.
+// Questions without objectives are classified by the client. All
+// errors are returned as an ErrorList with the line of the offending value.
+func Compile(file string, data []byte) (*types.CertificationSet, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, syntaxErrors(file, err)
+	}
+	c := &compiler{file: file}
+	certSet := c.compile(&doc)
+	sort.SliceStable(c.errs, func(i, j int) bool {
+		a, b := c.errs[i], c.errs[j]
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	if err := c.errs.err(); err != nil {
+		return nil, err
+	}
+	return certSet, nil
+}
+
+func (c *compiler) errorf(node *yaml.Node, format string, args ...any) {
+	c.errs = append(c.errs, nodeError(c.file, node, format, args...))
+}
+
+func (c *compiler) compile(doc *yaml.Node) *types.CertificationSet {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		c.errs = append(c.errs, &Error{File: c.file, Message: "file is empty"})
+		return nil
+	}
+	root := doc.Content[0]
+
+	certSet := &types.CertificationSet{
+		Questions: map[int]*types.Question{},
+		Testsets:  map[string]types.Testset{},
+	}
+	var testsets []*authoredTestset
+	var questions []*authoredQuestion
+	present := c.fields(root, map[string]func(*yaml.Node){
+		"certification_id":          func(n *yaml.Node) { certSet.CertificationID = c.str(n) },
+		"certification_name":        func(n *yaml.Node) { certSet.CertificationName = c.str(n) },
+		"certification_description": func(n *yaml.Node) { certSet.CertificationDescription = c.str(n) },
+		"testsets": func(n *yaml.Node) {
+			c.each(n, func(item *yaml.Node) { testsets = append(testsets, c.testset(item)) })
+		},
+		"questions": func(n *yaml.Node) {
+			c.each(n, func(item *yaml.Node) { questions = append(questions, c.question(item)) })
+		},
+	})
+	if present == nil {
+		return nil
+	}
+	if certSet.CertificationID == "" {
+		c.errorf(at(present, "certification_id", root), "certification_id is missing")
+	}
+	certSet.ID = certSet.CertificationID
+	if len(questions) == 0 {
+		c.errorf(at(present, "questions", root), "no questions")
+	}
+
+	testsetNodes := c.addTestsets(certSet, testsets)
+	c.addQuestions(certSet, questions, testsetNodes)
+	for _, testset := range testsets {
+		if testset.testset.TestsetID != "" && len(certSet.Testsets[testset.testset.TestsetID].QuestionsIds) == 0 {
+			c.errorf(testset.node, "testset %q has no questions", testset.testset.TestsetID)
+		}
+	}
+	return certSet
+}
+
+func (c *compiler) testset(node *yaml.Node) *authoredTestset {
+	testset := &authoredTestset{node: node}
+	c.fields(node, map[string]func(*yaml.Node){
+		"id":          func(n *yaml.Node) { testset.testset.TestsetID = c.str(n) },
+		"name":        func(n *yaml.Node) { testset.testset.TestsetName = c.str(n) },
+		"description": func(n *yaml.Node) { testset.testset.TestsetDescription = c.str(n) },
+	})
+	return testset
+}
+
+func (c *compiler) addTestsets(certSet *types.CertificationSet, testsets []*authoredTestset) map[string]*yaml.Node {
+	nodes := make(map[string]*yaml.Node, len(testsets))
+	for _, testset := range testsets {
+		id := testset.testset.TestsetID
+		switch first, ok := nodes[id]; {
+		case id == "":
+			c.errorf(testset.node, "testset needs an id")
+		case ok:
+			c.errorf(testset.node, "testset %q is already defined at line %d", id, first.Line)
+		default:
+			nodes[id] = testset.node
+			testset.testset.QuestionsIds = []int{}
+			certSet.Testsets[id] = testset.testset
+		}
+	}
+	return nodes
+}
+
+func (c *compiler) question(node *yaml.Node) *authoredQuestion {
+	q := &authoredQuestion{node: node, question: &types.Question{}}
+	present := c.fields(node, map[string]func(*yaml.Node){
+		"key":         func(n *yaml.Node) { q.key = c.str(n) },
+		"id":          func(n *yaml.Node) { q.idNode = n },
+		"text":        func(n *yaml.Node) { q.question.Text = c.str(n) },
+		"explanation": func(n *yaml.Node) { q.question.Explanation = c.str(n) },
+		"testsets":    func(n *yaml.Node) { c.each(n, func(item *yaml.Node) { q.testsets = append(q.testsets, item) }) },
+		"objectives":  func(n *yaml.Node) { c.each(n, func(item *yaml.Node) { q.objectives = append(q.objectives, item) }) },
+		"answers": func(n *yaml.Node) {
+			c.each(n, func(item *yaml.Node) {
+				q.answerNodes = append(q.answerNodes, item)
+				q.question.Answers = append(q.question.Answers, c.answer(item))
+			})
+		},
+		"fill_in": func(n *yaml.Node) { q.question.FillIn = c.fillIn(n) },
+		"ordering": func(n *yaml.Node) {
+			c.each(n, func(item *yaml.Node) { q.question.Ordering = append(q.question.Ordering, c.str(item)) })
+		},
+		"matching": func(n *yaml.Node) {
+			c.each(n, func(item *yaml.Node) { q.question.Matching = append(q.question.Matching, c.pair(item)) })
+		},
+	})
+	if present == nil {
+		return q
+	}
+
+	if q.question.Text == "" {
+		c.errorf(at(present, "text", node), "question has no text")
+	}
+	kinds := 0
+	for _, field := range []string{"answers", "fill_in", "ordering", "matching"} {
+		if _, ok := present[field]; ok {
+			kinds++
+		}
+	}
+	if kinds > 1 {
+		c.errorf(node, "question can only have one of answers, fill_in, ordering and matching")
+		return q
+	}
+	for _, kind := range []string{types.QuestionKindOrdering, types.QuestionKindMatching} {
+		if kindNode, ok := present[kind]; ok {
+			q.question.Kind = kind
+			if err := q.question.ValidateArrangement(); err != nil {
+				c.errorf(kindNode, "%s question: %v", kind, err)
+			}
+			return q
+		}
+	}
+	if fillInNode, ok := present["fill_in"]; ok {
+		q.question.Kind = types.QuestionKindFillIn
+		if q.question.FillIn != nil {
+			if err := q.question.FillIn.Validate(); err != nil {
+				c.errorf(fillInNode, "fill-in question: %v", err)
+			}
+		}
+		return q
+	}
+	answersNode, ok := present["answers"]
+	if !ok {
+		c.errorf(node, "question has no answers")
+		return q
+	}
+	if len(q.question.Answers) < 2 {
+		c.errorf(answersNode, "question needs at least two answers")
+		return q
+	}
+	correct := 0
+	for _, answer := range q.question.Answers {
+		if answer.IsCorrect {
+			correct++
+		}
+	}
+	switch correct {
+	case 0:
+		c.errorf(answersNode, "question has no correct answer")
+	case len(q.question.Answers):
+		c.errorf(answersNode, "all answers are correct")
+	}
+	return q
+}
+
+func (c *compiler) answer(node *yaml.Node) *types.Answer {
+	answer := &types.Answer{}
+	var idNode *yaml.Node
+	present := c.fields(node, map[string]func(*yaml.Node){
+		"text":    func(n *yaml.Node) { answer.Text = c.str(n) },
+		"correct": func(n *yaml.Node) { answer.IsCorrect = c.boolean(n) },
+		"id": func(n *yaml.Node) {
+			idNode = n
+			answer.AnswerID = c.str(n)
+		},
+	})
+	if present == nil {
+		return answer
+	}
+	if answer.Text == "" {
+		c.errorf(at(present, "text", node), "answer has no text")
+	}
+	if idNode != nil && answer.AnswerID == "" {
+		c.errorf(idNode, "answer id must not be empty")
+	}
+	if idNode == nil {
+		answer.AnswerID = AnswerID(answer.Text)
+	}
+	return answer
+}
+
+func (c *compiler) pair(node *yaml.Node) types.Pair {
+	var pair types.Pair
+	c.fields(node, map[string]func(*yaml.Node){
+		"left":  func(n *yaml.Node) { pair.Left = c.str(n) },
+		"right": func(n *yaml.Node) { pair.Right = c.str(n) },
+	})
+	return pair
+}
+
+func (c *compiler) fillIn(node *yaml.Node) *types.FillIn {
+	fillIn := &types.FillIn{}
+	present := c.fields(node, map[string]func(*yaml.Node){
+		"accepted": func(n *yaml.Node) {
+			c.each(n, func(item *yaml.Node) { fillIn.Accepted = append(fillIn.Accepted, c.str(item)) })
+		},
+		"patterns": func(n *yaml.Node) {
+			c.each(n, func(item *yaml.Node) { fillIn.Patterns = append(fillIn.Patterns, c.str(item)) })
+		},
+		"case_sensitive":   func(n *yaml.Node) { fillIn.CaseSensitive = c.boolean(n) },
+		"exact_whitespace": func(n *yaml.Node) { fillIn.ExactWhitespace = c.boolean(n) },
+		"command":          func(n *yaml.Node) { fillIn.Command = c.boolean(n) },
+	})
+	if present == nil {
+		return nil
+	}
+	return fillIn
+}
+
+func (c *compiler) addQuestions(certSet *types.CertificationSet, questions []*authoredQuestion, testsetNodes map[string]*yaml.Node) {
+	knownObjectives := make(map[string]bool)
+	for _, objective := range objectives.ForCertification(certSet.CertificationID) {
+		knownObjectives[objective.ID] = true
+	}
+	keys := make(map[string]*yaml.Node, len(questions))
+	byID := make(map[int]*authoredQuestion, len(questions))
+	for _, q := range questions {
+		if q.key != "" {
+			if first, ok := keys[q.key]; ok {
+				c.errorf(q.node, "question key %q is already used at line %d", q.key, first.Line)
+				continue
+			}
+			keys[q.key] = q.node
+		}
+
+		switch {
+		case q.idNode != nil:
+			if err := q.idNode.Decode(&q.question.ID); err != nil || q.question.ID <= 0 {
+				c.errorf(q.idNode, "question id must be a positive number")
+				continue
+			}
+		case q.key != "":
+			q.question.ID = QuestionID(q.key)
+		default:
+			c.errorf(q.node, "question needs a key or an id")
+			continue
+		}
+
+		if first, ok := byID[q.question.ID]; ok {
+			if q.idNode == nil && first.idNode == nil {
+				c.errorf(q.node, "question key %q gets the same ID %d as key %q at line %d, set an id for one of them",
+					q.key, q.question.ID, first.key, first.node.Line)
+			} else {
+				c.errorf(q.node, "question ID %d is already used at line %d", q.question.ID, first.node.Line)
+			}
+			continue
+		}
+		byID[q.question.ID] = q
+		certSet.Questions[q.question.ID] = q.question
+
+		answerIDs := make(map[string]int, len(q.question.Answers))
+		for i, answer := range q.question.Answers {
+			if first, ok := answerIDs[answer.AnswerID]; ok && answer.AnswerID != "" {
+				c.errorf(q.answerNodes[i], "answer has the same id %q as the answer at line %d, set an id or change the text",
+					answer.AnswerID, q.answerNodes[first].Line)
+			}
+			answerIDs[answer.AnswerID] = i
+		}
+
+		seen := make(map[string]bool, len(q.testsets))
+		for _, ref := range q.testsets {
+			id := c.str(ref)
+			if _, ok := testsetNodes[id]; !ok {
+				c.errorf(ref, "unknown testset %q", id)
+				continue
+			}
+			if seen[id] {
+				c.errorf(ref, "testset %q is listed twice", id)
+				continue
+			}
+			seen[id] = true
+			testset := certSet.Testsets[id]
+			testset.QuestionsIds = append(testset.QuestionsIds, q.question.ID)
+			certSet.Testsets[id] = testset
+		}
+
+		for _, node := range q.objectives {
+			id := c.str(node)
+			switch {
+			case id == "":
+			case len(knownObjectives) > 0 && !knownObjectives[id]:
+				c.errorf(node, "unknown objective %q of certification %s", id, certSet.CertificationID)
+			case slices.Contains(q.question.Objectives, id):
+				c.errorf(node, "objective %q is listed twice", id)
+			default:
+				q.question.Objectives = append(q.question.Objectives, id)
+			}
+		}
+	}
+}
+
+// fields calls the handler of every key of the mapping node. Unknown and
+// duplicate keys are reported. It returns the key nodes, or nil if node is not
+// a mapping.
+func (c *compiler) fields(node *yaml.Node, handlers map[string]func(*yaml.Node)) map[string]*yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		c.errorf(node, "expected a mapping")
+		return nil
+	}
+	present := make(map[string]*yaml.Node, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		handler, ok := handlers[key.Value]
+		if !ok {
+			c.errorf(key, "unknown field %q", key.Value)
+			continue
+		}
+		if first, ok := present[key.Value]; ok {
+			c.errorf(key, "field %q is already set at line %d", key.Value, first.Line)
+			continue
+		}
+		present[key.Value] = key
+		handler(value)
+	}
+	return present
+}
+
+func (c *compiler) each(node *yaml.Node, f func(*yaml.Node)) {
+	if node.Kind != yaml.SequenceNode {
+		c.errorf(node, "expected a list")
+		return
+	}
+	for _, item := range node.Content {
+		f(item)
+	}
+}
+
+func (c *compiler) str(node *yaml.Node) string {
+	if node.Kind != yaml.ScalarNode {
+		c.errorf(node, "expected a text")
+		return ""
+	}
+	if node.Tag == "!!null" {
+		return ""
+	}
+	return strings.TrimSpace(node.Value)
+}
+
+func (c *compiler) boolean(node *yaml.Node) bool {
+	var value bool
+	if node.Kind != yaml.ScalarNode || node.Decode(&value) != nil {
+		c.errorf(node, "expected true or false")
+	}
+	return value
+}
+
+// at returns the key node of field, or node if the field is missing.
+func at(present map[string]*yaml.Node, field string, node *yaml.Node) *yaml.Node {
+	if key, ok := present[field]; ok {
+		return key
+	}
+	return node
+}
@@ -0,0 +1,215 @@
+package authoring
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+func TestQuestionID(t *testing.T) {
+	id := QuestionID("ls-basics")
+	if id < AuthoredIDBase || id >= 2*AuthoredIDBase {
+		t.Errorf("QuestionID() = %d, want at least %d", id, AuthoredIDBase)
+	}
+	if again := QuestionID("ls-basics"); again != id {
+		t.Errorf("QuestionID() changed from %d to %d", id, again)
+	}
+	if other := QuestionID("ls-long"); other == id {
+		t.Errorf("two keys got the ID %d", id)
+	}
+}
+
+func TestAnswerID(t *testing.T) {
+	id := AnswerID("ls -l")
+	if len(id) != 8 {
+		t.Errorf("AnswerID() = %q, want 8 hex digits", id)
+	}
+	if again := AnswerID("  ls -l\n"); again != id {
+		t.Errorf("AnswerID() with surrounding space = %q, want %q", again, id)
+	}
+	if other := AnswerID("ls -L"); other == id {
+		t.Errorf("two answers got the ID %q", id)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	data, err := os.ReadFile("testdata/valid.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	certSet, err := Compile("valid.yaml", data)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if certSet.CertificationID != "lpic1-101-500" || certSet.ID != certSet.CertificationID || certSet.CertificationName != "Team questions for 101" {
+		t.Errorf("certification = %q (%q), %q", certSet.CertificationID, certSet.ID, certSet.CertificationName)
+	}
+
+	lsBasics, lsLong, targets := QuestionID("ls-basics"), QuestionID("ls-long"), QuestionID("runlevel-targets")
+	wantTestsets := map[string]types.Testset{
+		"basics": {TestsetID: "basics", TestsetName: "Basics", QuestionsIds: []int{lsBasics, lsLong, targets}},
+		"boot":   {TestsetID: "boot", TestsetName: "Boot", QuestionsIds: []int{42, targets}},
+	}
+	if !reflect.DeepEqual(certSet.Testsets, wantTestsets) {
+		t.Errorf("Testsets = %+v, want %+v", certSet.Testsets, wantTestsets)
+	}
+	if len(certSet.Questions) != 4 {
+		t.Fatalf("%d questions, want 4", len(certSet.Questions))
+	}
+
+	choice := certSet.Questions[lsBasics]
+	wantAnswers := []*types.Answer{
+		{AnswerID: AnswerID("ls"), Text: "ls", IsCorrect: true},
+		{AnswerID: AnswerID("cd"), Text: "cd"},
+		{AnswerID: "pwd", Text: "pwd"},
+	}
+	if !reflect.DeepEqual(choice.Answers, wantAnswers) {
+		t.Errorf("answers = %+v, want %+v", choice.Answers, wantAnswers)
+	}
+	if choice.Kind != "" || choice.Explanation != "cd changes the directory." || !reflect.DeepEqual(choice.Objectives, []string{"103.3"}) {
+		t.Errorf("choice question = %+v", choice)
+	}
+
+	fillIn := certSet.Questions[lsLong]
+	wantFillIn := &types.FillIn{Accepted: []string{"ls -l", "ls -la"}, Patterns: []string{"ls( -[a-z]*l[a-z]*)+"}, Command: true}
+	if fillIn.Kind != types.QuestionKindFillIn || !reflect.DeepEqual(fillIn.FillIn, wantFillIn) {
+		t.Errorf("fill-in question = %s %+v, want %+v", fillIn.Kind, fillIn.FillIn, wantFillIn)
+	}
+
+	ordering := certSet.Questions[42]
+	if ordering.Kind != types.QuestionKindOrdering || !reflect.DeepEqual(ordering.Ordering, []string{"firmware", "boot loader", "kernel", "init"}) {
+		t.Errorf("ordering question = %s %v", ordering.Kind, ordering.Ordering)
+	}
+
+	matching := certSet.Questions[targets]
+	wantPairs := []types.Pair{{Left: "runlevel 3", Right: "multi-user.target"}, {Left: "runlevel 5", Right: "graphical.target"}}
+	if matching.Kind != types.QuestionKindMatching || !reflect.DeepEqual(matching.Matching, wantPairs) {
+		t.Errorf("matching question = %s %+v", matching.Kind, matching.Matching)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"empty file", "", "q.yaml: file is empty"},
+		{"syntax error", "certification_id: [a\n", "q.yaml:1: did not find expected ',' or ']'"},
+		{"not a mapping", "- a\n", "q.yaml:1:1: expected a mapping"},
+		{
+			"missing certification and questions",
+			"certification_name: x\n",
+			"q.yaml:1:1: certification_id is missing\nq.yaml:1:1: no questions",
+		},
+		{
+			"unknown and duplicate fields",
+			`certification_id: a
+certification_id: b
+questions:
+  - key: q
+    txt: Q
+    text: Q
+    answers: [{text: x, correct: true}, {text: y}]
+`,
+			"q.yaml:2:1: field \"certification_id\" is already set at line 1\nq.yaml:5:5: unknown field \"txt\"",
+		},
+		{
+			"answers",
+			`certification_id: a
+questions:
+  - key: one
+    text: One answer
+    answers: [{text: x, correct: true}]
+  - key: none-correct
+    text: No correct answer
+    answers: [{text: x}, {text: y}]
+  - key: all-correct
+    text: All correct
+    answers: [{text: x, correct: true}, {text: y, correct: true}]
+  - key: same-text
+    text: Same answer twice
+    answers: [{text: x, correct: true}, {text: x, correct: maybe}]
+  - key: no-answers
+    text: No answers
+`,
+			`q.yaml:5:5: question needs at least two answers
+q.yaml:8:5: question has no correct answer
+q.yaml:11:5: all answers are correct
+q.yaml:14:41: answer has the same id "fd0c5087" as the answer at line 14, set an id or change the text
+q.yaml:14:60: expected true or false
+q.yaml:15:5: question has no answers`,
+		},
+		{
+			"kinds",
+			`certification_id: a
+questions:
+  - key: two-kinds
+    text: Two kinds
+    ordering: [a, b]
+    fill_in: {accepted: [a]}
+  - key: bad-pattern
+    text: Bad pattern
+    fill_in: {patterns: ['ls (']}
+  - key: one-item
+    text: One item
+    ordering: [a]
+`,
+			`q.yaml:3:5: question can only have one of answers, fill_in, ordering and matching
+q.yaml:9:5: fill-in question: invalid pattern "ls (": error parsing regexp: missing closing ): ` + "`ls (`" + `
+q.yaml:12:5: ordering question: at least two items are needed`,
+		},
+		{
+			"ids, testsets and objectives",
+			`certification_id: lpic1-101-500
+testsets:
+  - id: basics
+  - id: basics
+  - name: No id
+  - id: empty
+questions:
+  - key: q
+    text: Q
+    testsets: [basics, basics, other]
+    objectives: [103.3, 103.3, 999.9]
+    ordering: [a, b]
+  - key: q
+    text: Same key
+    ordering: [a, b]
+  - id: -1
+    text: Negative id
+    ordering: [a, b]
+  - text: No key
+    ordering: [a, b]
+`,
+			`q.yaml:4:5: testset "basics" is already defined at line 3
+q.yaml:5:5: testset needs an id
+q.yaml:6:5: testset "empty" has no questions
+q.yaml:10:24: testset "basics" is listed twice
+q.yaml:10:32: unknown testset "other"
+q.yaml:11:25: objective "103.3" is listed twice
+q.yaml:11:32: unknown objective "999.9" of certification lpic1-101-500
+q.yaml:13:5: question key "q" is already used at line 8
+q.yaml:16:9: question id must be a positive number
+q.yaml:19:5: question needs a key or an id`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certSet, err := Compile("q.yaml", []byte(tt.yaml))
+			if certSet != nil {
+				t.Errorf("Compile returned a certification set with errors")
+			}
+			var list ErrorList
+			if !errors.As(err, &list) {
+				t.Fatalf("Compile() = %v, want an ErrorList", err)
+			}
+			if got := err.Error(); got != tt.want {
+				t.Errorf("Compile() errors:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package authoring
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Error is a problem at a position of a question file.
+type Error struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Line == 0:
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	case e.Column == 0:
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+// ErrorList holds all errors found in a question file, in file order.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	lines := make([]string, len(l))
+	for i, err := range l {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (l ErrorList) err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+var yamlLineError = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// syntaxErrors converts an error of the YAML parser, which only reports the
+// line in its message.
+func syntaxErrors(file string, err error) ErrorList {
+	var list ErrorList
+	for _, message := range strings.Split(err.Error(), "\n") {
+		message = strings.TrimSpace(message)
+		if message == "" || message == "yaml: unmarshal errors:" {
+			continue
+		}
+		if m := yamlLineError.FindStringSubmatch(message); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			list = append(list, &Error{File: file, Line: line, Message: m[2]})
+			continue
+		}
+		list = append(list, &Error{File: file, Message: strings.TrimPrefix(message, "yaml: ")})
+	}
+	return list
+}
+
+func nodeError(file string, node *yaml.Node, format string, args ...any) *Error {
+	return &Error{File: file, Line: node.Line, Column: node.Column, Message: fmt.Sprintf(format, args...)}
+}
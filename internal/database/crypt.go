@@ -0,0 +1,123 @@
+package database
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// EncryptedSuffix marks dataset files that are gzip compressed and AES-GCM
+// encrypted, as written by cmd/crypt.
+const EncryptedSuffix = ".enc"
+
+// LoadKey returns the AES key used for encrypted datasets. It is read from
+// keyFile if set, otherwise from the AES_KEY environment variable. Surrounding
+// whitespace, e.g. the trailing newline of a key file, is ignored.
+func LoadKey(keyFile string) ([]byte, error) {
+	if keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		key := strings.TrimSpace(string(data))
+		if key == "" {
+			return nil, fmt.Errorf("key file %s is empty", keyFile)
+		}
+		return []byte(key), nil
+	}
+
+	key := os.Getenv("AES_KEY")
+	if key == "" {
+		return nil, errors.New("AES_KEY environment variable is not set and no key file was given")
+	}
+	return []byte(key), nil
+}
+
+// Encrypt encrypts plaintext with AES-GCM. The random nonce is prepended to
+// the ciphertext.
+func Encrypt(plaintext []byte, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// The nonce is the destination buffer, so the ciphertext is appended to it.
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts data (nonce + ciphertext) written by Encrypt.
+func Decrypt(data []byte, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("ciphertext too short to contain nonce")
+	}
+
+	// Open fails if the data was tampered with or the key is wrong.
+	plaintext, err := gcm.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt and authenticate: %w", err)
+	}
+	return plaintext, nil
+}
+
+// EncryptDataset compresses data with gzip and encrypts the result.
+func EncryptDataset(data []byte, key []byte) ([]byte, error) {
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	if _, err := gzipWriter.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress data: %w", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress data: %w", err)
+	}
+	return Encrypt(compressed.Bytes(), key)
+}
+
+// DecryptDataset reverses EncryptDataset. The plaintext is only kept in memory.
+func DecryptDataset(data []byte, key []byte) ([]byte, error) {
+	decrypted, err := Decrypt(data, key)
+	if err != nil {
+		return nil, err
+	}
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(decrypted))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress data: %w", err)
+	}
+	defer gzipReader.Close()
+
+	plaintext, err := io.ReadAll(gzipReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress data: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES cipher block: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM cipher: %w", err)
+	}
+	return gcm, nil
+}
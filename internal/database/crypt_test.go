@@ -0,0 +1,122 @@
+package database
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestEncryptDataset(t *testing.T) {
+	plaintext := []byte(`[{"CertificationID": "lpic1-101-500"}]`)
+	encrypted, err := EncryptDataset(plaintext, testKey)
+	if err != nil {
+		t.Fatalf("EncryptDataset: %v", err)
+	}
+	if bytes.Contains(encrypted, []byte("lpic1")) {
+		t.Error("the encrypted dataset contains plaintext")
+	}
+	again, err := EncryptDataset(plaintext, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(encrypted, again) {
+		t.Error("encrypting twice gave the same ciphertext, the nonce is not random")
+	}
+
+	decrypted, err := DecryptDataset(encrypted, testKey)
+	if err != nil {
+		t.Fatalf("DecryptDataset: %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("DecryptDataset() = %q, want %q", decrypted, plaintext)
+	}
+
+	tampered := bytes.Clone(encrypted)
+	tampered[len(tampered)-1] ^= 1
+	for name, tt := range map[string]struct {
+		data, key []byte
+	}{
+		"wrong key":     {encrypted, []byte("fedcba9876543210fedcba9876543210")},
+		"tampered data": {tampered, testKey},
+		"too short":     {encrypted[:4], testKey},
+		"invalid key":   {encrypted, []byte("short")},
+		"not gzip":      {mustEncrypt(t, []byte("plain")), testKey},
+	} {
+		if _, err := DecryptDataset(tt.data, tt.key); err == nil {
+			t.Errorf("%s: DecryptDataset succeeded", name)
+		}
+	}
+}
+
+func mustEncrypt(t *testing.T, plaintext []byte) []byte {
+	t.Helper()
+	data, err := Encrypt(plaintext, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestLoadKey(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, append(testKey, '\n'), 0600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, []byte(" \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("AES_KEY", "from-the-environment")
+	if key, err := LoadKey(keyFile); err != nil || !bytes.Equal(key, testKey) {
+		t.Errorf("LoadKey(key file) = %q, %v; want the trimmed key", key, err)
+	}
+	if key, err := LoadKey(""); err != nil || string(key) != "from-the-environment" {
+		t.Errorf("LoadKey(\"\") = %q, %v; want $AES_KEY", key, err)
+	}
+	if _, err := LoadKey(emptyFile); err == nil {
+		t.Error("LoadKey accepted an empty key file")
+	}
+	if _, err := LoadKey(filepath.Join(dir, "missing")); err == nil {
+		t.Error("LoadKey accepted a missing key file")
+	}
+	t.Setenv("AES_KEY", "")
+	if _, err := LoadKey(""); err == nil {
+		t.Error("LoadKey succeeded without a key")
+	}
+}
+
+func TestReadEncryptedDataset(t *testing.T) {
+	dir := t.TempDir()
+	plaintext := []byte(`[{"CertificationID": "lpic1-101-500", "Questions": {}}]`)
+	encrypted, err := EncryptDataset(plaintext, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "dataset.json"+EncryptedSuffix)
+	if err := os.WriteFile(path, encrypted, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("AES_KEY", string(testKey))
+	data, err := ReadDataset(path, "")
+	if err != nil {
+		t.Fatalf("ReadDataset: %v", err)
+	}
+	if !bytes.Equal(data, plaintext) {
+		t.Errorf("ReadDataset() = %q, want the decrypted dataset", data)
+	}
+	certSets, err := LoadFullData(path, "")
+	if err != nil || len(certSets) != 1 || certSets[0].CertificationID != "lpic1-101-500" {
+		t.Errorf("LoadFullData() = %v, %v; want lpic1-101-500", certSets, err)
+	}
+
+	t.Setenv("AES_KEY", "fedcba9876543210fedcba9876543210")
+	if _, err := ReadDataset(path, ""); err == nil {
+		t.Error("ReadDataset decrypted the dataset with the wrong key")
+	}
+}
@@ -3,40 +3,94 @@ package database
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/SqiSch/lpic-cli/internal/types"
 )
 
-func LoadFullData(filename string) ([]*types.CertificationSet, error) {
-	var certSets []*types.CertificationSet
-	file, err := os.Open(filename)
+// ReadDataset returns the JSON of a dataset. location is a file path or an
+// http(s) URL. Datasets ending in .enc are decrypted in memory with the key
+// from LoadKey(keyFile), so their plaintext is never written to disk.
+func ReadDataset(location string, keyFile string) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+		path = location
+	)
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		if u, err := url.Parse(location); err == nil {
+			path = u.Path
+		}
+		data, err = fetchURL(location)
+	} else {
+		data, err = os.ReadFile(location)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
 
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&certSets); err != nil {
-		return nil, fmt.Errorf("failed to decode certification sets: %w", err)
+	if !strings.HasSuffix(path, EncryptedSuffix) {
+		return data, nil
 	}
-	return certSets, nil
+	key, err := LoadKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err = DecryptDataset(data, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", location, err)
+	}
+	return data, nil
+}
 
+// fetchTimeout bounds downloading a dataset, including reading the body, so
+// an unresponsive server does not hang the client on startup.
+const fetchTimeout = 60 * time.Second
+
+var httpClient = &http.Client{Timeout: fetchTimeout}
+
+func fetchURL(location string) ([]byte, error) {
+	resp, err := httpClient.Get(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dataset: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch dataset: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset from response: %w", err)
+	}
+	return data, nil
 }
 
-func LoadDatabaseFromFile(filename string, certID string) (*types.CertificationSet, error) {
-	var certSets []types.CertificationSet
-	file, err := os.Open(filename)
+func LoadFullData(filename string, keyFile string) ([]*types.CertificationSet, error) {
+	var certSets []*types.CertificationSet
+	data, err := ReadDataset(filename, keyFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, fmt.Errorf("failed to load dataset: %w", err)
 	}
-	defer file.Close()
 
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&certSets); err != nil {
+	if err := json.Unmarshal(data, &certSets); err != nil {
 		return nil, fmt.Errorf("failed to decode certification sets: %w", err)
 	}
+	return certSets, nil
+
+}
+
+func LoadDatabaseFromFile(filename string, certID string, keyFile string) (*types.CertificationSet, error) {
+	certSets, err := LoadFullData(filename, keyFile)
+	if err != nil {
+		return nil, err
+	}
 
 	for _, cs := range certSets {
 		if cs.CertificationID == certID {
@@ -48,7 +102,7 @@ func LoadDatabaseFromFile(filename string, certID string) (*types.CertificationS
 			sort.Slice(keys, func(i, j int) bool {
 				return keys[i].ID < keys[j].ID
 			})
-			return &cs, nil
+			return cs, nil
 		}
 	}
 
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadDatasetURL(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dataset.json":
+			w.Write([]byte(`[]`))
+		case "/slow.json":
+			<-release
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := httpClient
+	httpClient = &http.Client{Timeout: 100 * time.Millisecond}
+	defer func() { httpClient = client }()
+
+	if data, err := ReadDataset(server.URL+"/dataset.json", ""); err != nil || string(data) != "[]" {
+		t.Errorf("ReadDataset = %q, %v, want []", data, err)
+	}
+	if _, err := ReadDataset(server.URL+"/missing.json", ""); err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("missing dataset: err = %v, want HTTP 404", err)
+	}
+
+	started := time.Now()
+	if _, err := ReadDataset(server.URL+"/slow.json", ""); err == nil {
+		t.Error("unresponsive server: no error")
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("unresponsive server: gave up after %v", elapsed)
+	}
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"time"
@@ -10,26 +11,44 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func MongoConnect() *mongo.Client {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+// MongoURIFromEnv returns $MONGO_URI, or a localhost URI using the optional
+// $MONGO_USER and $MONGO_PASSWORD credentials.
+func MongoURIFromEnv() string {
+	if uri := os.Getenv("MONGO_URI"); uri != "" {
+		return uri
+	}
 	mongoUser := os.Getenv("MONGO_USER")
 	mongoPassword := os.Getenv("MONGO_PASSWORD")
 	mongoURI := "mongodb://localhost:27017"
 	if mongoUser != "" && mongoPassword != "" {
 		mongoURI = "mongodb://" + mongoUser + ":" + mongoPassword + "@localhost:27017"
 	}
+	return mongoURI
+}
+
+// MongoConnectURI connects to the MongoDB at uri and verifies the connection.
+func MongoConnectURI(ctx context.Context, uri string) (*mongo.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
 	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB: %v", err)
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
 	}
 
 	// Verify connection
 	if err := client.Ping(ctx, nil); err != nil {
-		log.Fatalf("MongoDB ping failed: %v", err)
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("MongoDB ping failed: %w", err)
 	}
 
+	return client, nil
+}
+
+func MongoConnect() *mongo.Client {
+	client, err := MongoConnectURI(context.Background(), MongoURIFromEnv())
+	if err != nil {
+		log.Fatal(err)
+	}
 	return client
 }
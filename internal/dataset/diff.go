@@ -0,0 +1,300 @@
+package dataset
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// AnswerChange describes an answer that was added, removed or reworded.
+// Answers are matched by AnswerID, answers without one by position.
+type AnswerChange struct {
+	Kind     ChangeKind `json:"kind"`
+	AnswerID string     `json:"answerId"`
+	OldText  string     `json:"oldText,omitempty"`
+	NewText  string     `json:"newText,omitempty"`
+}
+
+// QuestionChange describes how a question differs between two datasets. For
+// added and removed questions only the text of the existing side is set.
+type QuestionChange struct {
+	Kind           ChangeKind     `json:"kind"`
+	QuestionID     int            `json:"questionId"`
+	OldText        string         `json:"oldText,omitempty"`
+	NewText        string         `json:"newText,omitempty"`
+	Answers        []AnswerChange `json:"answers,omitempty"`
+	CorrectChanged bool           `json:"correctChanged,omitempty"`
+	OldCorrect     []string       `json:"oldCorrect,omitempty"`
+	NewCorrect     []string       `json:"newCorrect,omitempty"`
+	OldExplanation string         `json:"oldExplanation,omitempty"`
+	NewExplanation string         `json:"newExplanation,omitempty"`
+}
+
+// TextChanged reports whether the question text differs.
+func (c QuestionChange) TextChanged() bool {
+	return c.Kind == ChangeModified && c.OldText != c.NewText
+}
+
+// ExplanationChanged reports whether the explanation differs.
+func (c QuestionChange) ExplanationChanged() bool {
+	return c.Kind == ChangeModified && c.OldExplanation != c.NewExplanation
+}
+
+// TestsetChange describes a testset that was added or removed or whose
+// questions changed. Changes of the question order only are not reported.
+type TestsetChange struct {
+	Kind             ChangeKind `json:"kind"`
+	TestsetID        string     `json:"testsetId"`
+	OldName          string     `json:"oldName,omitempty"`
+	NewName          string     `json:"newName,omitempty"`
+	AddedQuestions   []int      `json:"addedQuestions,omitempty"`
+	RemovedQuestions []int      `json:"removedQuestions,omitempty"`
+}
+
+// CertificationDiff holds the changes of one certification.
+type CertificationDiff struct {
+	Kind            ChangeKind       `json:"kind"`
+	CertificationID string           `json:"certificationId"`
+	Questions       []QuestionChange `json:"questions,omitempty"`
+	Testsets        []TestsetChange  `json:"testsets,omitempty"`
+}
+
+// Count returns the number of question changes of kind.
+func (d CertificationDiff) Count(kind ChangeKind) int {
+	count := 0
+	for _, change := range d.Questions {
+		if change.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// Diff lists the certifications that differ between two datasets.
+type Diff struct {
+	Certifications []CertificationDiff `json:"certifications"`
+}
+
+// Empty reports whether both datasets have the same content.
+func (d *Diff) Empty() bool {
+	return len(d.Certifications) == 0
+}
+
+// Compare returns the changes from oldSets to newSets. Certifications are
+// matched by CertificationID, questions by ID and testsets by their key.
+func Compare(oldSets, newSets []*types.CertificationSet) *Diff {
+	oldByID := certificationsByID(oldSets)
+	newByID := certificationsByID(newSets)
+
+	diff := &Diff{Certifications: []CertificationDiff{}}
+	for _, id := range unionKeys(oldByID, newByID) {
+		oldSet, newSet := oldByID[id], newByID[id]
+		certDiff := CertificationDiff{Kind: ChangeModified, CertificationID: id}
+		switch {
+		case oldSet == nil:
+			certDiff.Kind = ChangeAdded
+			oldSet = &types.CertificationSet{}
+		case newSet == nil:
+			certDiff.Kind = ChangeRemoved
+			newSet = &types.CertificationSet{}
+		}
+		certDiff.Questions = compareQuestions(oldSet.Questions, newSet.Questions)
+		certDiff.Testsets = compareTestsets(oldSet.Testsets, newSet.Testsets)
+		if certDiff.Kind != ChangeModified || len(certDiff.Questions) > 0 || len(certDiff.Testsets) > 0 {
+			diff.Certifications = append(diff.Certifications, certDiff)
+		}
+	}
+	return diff
+}
+
+// certificationsByID keeps the first set of every ID, like LoadDatabaseFromFile.
+func certificationsByID(sets []*types.CertificationSet) map[string]*types.CertificationSet {
+	byID := make(map[string]*types.CertificationSet, len(sets))
+	for _, set := range sets {
+		if _, ok := byID[set.CertificationID]; !ok {
+			byID[set.CertificationID] = set
+		}
+	}
+	return byID
+}
+
+func compareQuestions(oldQuestions, newQuestions map[int]*types.Question) []QuestionChange {
+	var changes []QuestionChange
+	for _, id := range unionKeys(oldQuestions, newQuestions) {
+		oldQuestion, newQuestion := oldQuestions[id], newQuestions[id]
+		switch {
+		case oldQuestion == nil:
+			changes = append(changes, QuestionChange{Kind: ChangeAdded, QuestionID: id, NewText: newQuestion.Text})
+		case newQuestion == nil:
+			changes = append(changes, QuestionChange{Kind: ChangeRemoved, QuestionID: id, OldText: oldQuestion.Text})
+		default:
+			if change, ok := compareQuestion(id, oldQuestion, newQuestion); ok {
+				changes = append(changes, change)
+			}
+		}
+	}
+	return changes
+}
+
+func compareQuestion(id int, oldQuestion, newQuestion *types.Question) (QuestionChange, bool) {
+	change := QuestionChange{Kind: ChangeModified, QuestionID: id}
+	if oldQuestion.Text != newQuestion.Text {
+		change.OldText, change.NewText = oldQuestion.Text, newQuestion.Text
+	}
+	if oldQuestion.Explanation != newQuestion.Explanation {
+		change.OldExplanation, change.NewExplanation = oldQuestion.Explanation, newQuestion.Explanation
+	}
+
+	oldAnswers, newAnswers := answersByID(oldQuestion.Answers), answersByID(newQuestion.Answers)
+	for _, answerID := range unionKeys(oldAnswers, newAnswers) {
+		oldAnswer, newAnswer := oldAnswers[answerID], newAnswers[answerID]
+		switch {
+		case oldAnswer == nil:
+			change.Answers = append(change.Answers, AnswerChange{Kind: ChangeAdded, AnswerID: answerID, NewText: newAnswer.Text})
+		case newAnswer == nil:
+			change.Answers = append(change.Answers, AnswerChange{Kind: ChangeRemoved, AnswerID: answerID, OldText: oldAnswer.Text})
+		case oldAnswer.Text != newAnswer.Text:
+			change.Answers = append(change.Answers, AnswerChange{Kind: ChangeModified, AnswerID: answerID, OldText: oldAnswer.Text, NewText: newAnswer.Text})
+		}
+	}
+
+	oldCorrect, newCorrect := correctAnswerIDs(oldAnswers), correctAnswerIDs(newAnswers)
+	oldCorrect = append(oldCorrect, kindSolutions(oldQuestion)...)
+	newCorrect = append(newCorrect, kindSolutions(newQuestion)...)
+	if !slices.Equal(oldCorrect, newCorrect) {
+		change.CorrectChanged = true
+		change.OldCorrect, change.NewCorrect = oldCorrect, newCorrect
+	}
+
+	modified := change.TextChanged() || change.ExplanationChanged() || len(change.Answers) > 0 || change.CorrectChanged
+	return change, modified
+}
+
+// answersByID keys answers by AnswerID. Answers without one are keyed by
+// their position, e.g. "#2".
+func answersByID(answers []*types.Answer) map[string]*types.Answer {
+	byID := make(map[string]*types.Answer, len(answers))
+	for i, answer := range answers {
+		id := answer.AnswerID
+		if id == "" {
+			id = "#" + strconv.Itoa(i)
+		}
+		if _, ok := byID[id]; !ok {
+			byID[id] = answer
+		}
+	}
+	return byID
+}
+
+func correctAnswerIDs(answers map[string]*types.Answer) []string {
+	correct := []string{}
+	for id, answer := range answers {
+		if answer.IsCorrect {
+			correct = append(correct, id)
+		}
+	}
+	sort.Strings(correct)
+	return correct
+}
+
+// kindSolutions lists the accepted answers, patterns and rules of a
+// fill-in-the-blank question, the numbered items of an ordering question or
+// the pairs of a matching question, so a change of them counts as a change of
+// the correct answers.
+func kindSolutions(question *types.Question) []string {
+	if question.IsArranged() {
+		var solutions []string
+		for i, text := range question.SolutionTexts() {
+			if question.Kind == types.QuestionKindOrdering {
+				text = strconv.Itoa(i+1) + ". " + text
+			}
+			solutions = append(solutions, strconv.Quote(text))
+		}
+		return solutions
+	}
+	if !question.IsFillIn() {
+		return nil
+	}
+	var solutions []string
+	for _, accepted := range question.FillIn.Accepted {
+		solutions = append(solutions, strconv.Quote(accepted))
+	}
+	for _, pattern := range question.FillIn.Patterns {
+		solutions = append(solutions, "/"+pattern+"/")
+	}
+	if question.FillIn.CaseSensitive {
+		solutions = append(solutions, "case-sensitive")
+	}
+	if question.FillIn.ExactWhitespace {
+		solutions = append(solutions, "exact-whitespace")
+	}
+	if question.FillIn.Command {
+		solutions = append(solutions, "command")
+	}
+	return solutions
+}
+
+func compareTestsets(oldTestsets, newTestsets map[string]types.Testset) []TestsetChange {
+	var changes []TestsetChange
+	for _, id := range unionKeys(oldTestsets, newTestsets) {
+		oldTestset, inOld := oldTestsets[id]
+		newTestset, inNew := newTestsets[id]
+		change := TestsetChange{Kind: ChangeModified, TestsetID: id}
+		switch {
+		case !inOld:
+			change.Kind = ChangeAdded
+			change.NewName = newTestset.TestsetName
+		case !inNew:
+			change.Kind = ChangeRemoved
+			change.OldName = oldTestset.TestsetName
+		case oldTestset.TestsetName != newTestset.TestsetName:
+			change.OldName, change.NewName = oldTestset.TestsetName, newTestset.TestsetName
+		}
+		change.AddedQuestions = missingFrom(newTestset.QuestionsIds, oldTestset.QuestionsIds)
+		change.RemovedQuestions = missingFrom(oldTestset.QuestionsIds, newTestset.QuestionsIds)
+
+		if change.Kind != ChangeModified || change.OldName != change.NewName ||
+			len(change.AddedQuestions) > 0 || len(change.RemovedQuestions) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// missingFrom returns the sorted IDs of ids that are not in other.
+func missingFrom(ids, other []int) []int {
+	var missing []int
+	for _, id := range ids {
+		if !slices.Contains(other, id) && !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+	sort.Ints(missing)
+	return missing
+}
+
+// unionKeys returns the sorted keys of both maps.
+func unionKeys[K cmp.Ordered, V any](a, b map[K]V) []K {
+	keys := make([]K, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
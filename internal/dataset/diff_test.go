@@ -0,0 +1,225 @@
+package dataset
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// choice returns a question with an answer per text; the answer IDs are "a",
+// "b", … and the answers in correct are the correct ones.
+func choice(id int, text string, answers []string, correct ...string) *types.Question {
+	question := &types.Question{ID: id, Text: text}
+	for i, answerText := range answers {
+		answerID := string(rune('a' + i))
+		question.Answers = append(question.Answers, &types.Answer{
+			AnswerID:  answerID,
+			Text:      answerText,
+			IsCorrect: slices.Contains(correct, answerID),
+		})
+	}
+	return question
+}
+
+func certSet(id string, questions []*types.Question, testsets map[string]types.Testset) *types.CertificationSet {
+	set := &types.CertificationSet{CertificationID: id, Questions: make(map[int]*types.Question), Testsets: testsets}
+	for _, question := range questions {
+		set.Questions[question.ID] = question
+	}
+	return set
+}
+
+func TestCompare(t *testing.T) {
+	lsmod := func() *types.Question {
+		return choice(1, "Which command lists the loaded kernel modules?", []string{"lsmod", "modprobe -l"}, "a")
+	}
+	dmesg := func() *types.Question {
+		return &types.Question{ID: 2, Text: "Which command shows the kernel ring buffer?", Kind: types.QuestionKindFillIn,
+			FillIn: &types.FillIn{Accepted: []string{"dmesg"}}}
+	}
+	boot := func() *types.Question {
+		return &types.Question{ID: 3, Text: "Order the boot steps.", Kind: types.QuestionKindOrdering,
+			Ordering: []string{"BIOS", "boot loader", "kernel"}}
+	}
+	basics := func() map[string]types.Testset {
+		return map[string]types.Testset{"basics": {TestsetName: "Basics", QuestionsIds: []int{1, 2, 3}}}
+	}
+	base := func() []*types.CertificationSet {
+		return []*types.CertificationSet{certSet("lpic1-101-500", []*types.Question{lsmod(), dmesg(), boot()}, basics())}
+	}
+	// modified returns base with change applied to its certification set.
+	modified := func(change func(set *types.CertificationSet)) []*types.CertificationSet {
+		sets := base()
+		change(sets[0])
+		return sets
+	}
+	certDiff := func(questions []QuestionChange, testsets []TestsetChange) *Diff {
+		return &Diff{Certifications: []CertificationDiff{
+			{Kind: ChangeModified, CertificationID: "lpic1-101-500", Questions: questions, Testsets: testsets},
+		}}
+	}
+
+	tests := []struct {
+		name string
+		old  []*types.CertificationSet
+		new  []*types.CertificationSet
+		want *Diff
+	}{
+		{
+			name: "same content",
+			old:  base(),
+			new:  base(),
+			want: &Diff{Certifications: []CertificationDiff{}},
+		},
+		{
+			name: "testset question order",
+			old:  base(),
+			new: modified(func(set *types.CertificationSet) {
+				set.Testsets["basics"] = types.Testset{TestsetName: "Basics", QuestionsIds: []int{3, 1, 2}}
+			}),
+			want: &Diff{Certifications: []CertificationDiff{}},
+		},
+		{
+			name: "certification added",
+			old:  nil,
+			new:  []*types.CertificationSet{certSet("lpic1-102-500", []*types.Question{lsmod()}, map[string]types.Testset{"t": {TestsetName: "T", QuestionsIds: []int{1}}})},
+			want: &Diff{Certifications: []CertificationDiff{{
+				Kind: ChangeAdded, CertificationID: "lpic1-102-500",
+				Questions: []QuestionChange{{Kind: ChangeAdded, QuestionID: 1, NewText: lsmod().Text}},
+				Testsets:  []TestsetChange{{Kind: ChangeAdded, TestsetID: "t", NewName: "T", AddedQuestions: []int{1}}},
+			}}},
+		},
+		{
+			name: "certification removed",
+			old:  []*types.CertificationSet{certSet("lpic1-102-500", []*types.Question{lsmod()}, nil)},
+			new:  nil,
+			want: &Diff{Certifications: []CertificationDiff{{
+				Kind: ChangeRemoved, CertificationID: "lpic1-102-500",
+				Questions: []QuestionChange{{Kind: ChangeRemoved, QuestionID: 1, OldText: lsmod().Text}},
+			}}},
+		},
+		{
+			name: "questions added and removed",
+			old:  base(),
+			new: modified(func(set *types.CertificationSet) {
+				delete(set.Questions, 2)
+				set.Questions[4] = choice(4, "Which file lists the mounted filesystems?", []string{"/etc/mtab"}, "a")
+				set.Testsets["basics"] = types.Testset{TestsetName: "Basics", QuestionsIds: []int{1, 3, 4}}
+			}),
+			want: certDiff(
+				[]QuestionChange{
+					{Kind: ChangeRemoved, QuestionID: 2, OldText: dmesg().Text},
+					{Kind: ChangeAdded, QuestionID: 4, NewText: "Which file lists the mounted filesystems?"},
+				},
+				[]TestsetChange{{Kind: ChangeModified, TestsetID: "basics", AddedQuestions: []int{4}, RemovedQuestions: []int{2}}},
+			),
+		},
+		{
+			name: "text and explanation",
+			old:  base(),
+			new: modified(func(set *types.CertificationSet) {
+				set.Questions[1].Text = "Which command shows the loaded kernel modules?"
+				set.Questions[1].Explanation = "lsmod reads /proc/modules."
+			}),
+			want: certDiff([]QuestionChange{{
+				Kind: ChangeModified, QuestionID: 1,
+				OldText: lsmod().Text, NewText: "Which command shows the loaded kernel modules?",
+				NewExplanation: "lsmod reads /proc/modules.",
+			}}, nil),
+		},
+		{
+			name: "answers added, removed and reworded",
+			old:  base(),
+			new: modified(func(set *types.CertificationSet) {
+				set.Questions[1] = choice(1, lsmod().Text, []string{"lsmod", "modinfo", "cat /proc/modules"}, "c")
+				set.Questions[1].Answers = set.Questions[1].Answers[1:]
+			}),
+			want: certDiff([]QuestionChange{{
+				Kind: ChangeModified, QuestionID: 1,
+				Answers: []AnswerChange{
+					{Kind: ChangeRemoved, AnswerID: "a", OldText: "lsmod"},
+					{Kind: ChangeModified, AnswerID: "b", OldText: "modprobe -l", NewText: "modinfo"},
+					{Kind: ChangeAdded, AnswerID: "c", NewText: "cat /proc/modules"},
+				},
+				CorrectChanged: true, OldCorrect: []string{"a"}, NewCorrect: []string{"c"},
+			}}, nil),
+		},
+		{
+			name: "answers without ID are matched by position",
+			old: []*types.CertificationSet{certSet("lpic1-101-500", []*types.Question{{ID: 1, Text: "Q", Answers: []*types.Answer{
+				{Text: "lsmod", IsCorrect: true}, {Text: "modinfo"},
+			}}}, nil)},
+			new: []*types.CertificationSet{certSet("lpic1-101-500", []*types.Question{{ID: 1, Text: "Q", Answers: []*types.Answer{
+				{Text: "lsmod"}, {Text: "modinfo", IsCorrect: true},
+			}}}, nil)},
+			want: certDiff([]QuestionChange{{
+				Kind: ChangeModified, QuestionID: 1,
+				CorrectChanged: true, OldCorrect: []string{"#0"}, NewCorrect: []string{"#1"},
+			}}, nil),
+		},
+		{
+			name: "fill-in solutions",
+			old:  base(),
+			new: modified(func(set *types.CertificationSet) {
+				set.Questions[2].FillIn = &types.FillIn{Accepted: []string{"dmesg"}, Patterns: []string{`journalctl -k|journalctl --dmesg`}, Command: true}
+			}),
+			want: certDiff([]QuestionChange{{
+				Kind: ChangeModified, QuestionID: 2,
+				CorrectChanged: true,
+				OldCorrect:     []string{`"dmesg"`},
+				NewCorrect:     []string{`"dmesg"`, "/journalctl -k|journalctl --dmesg/", "command"},
+			}}, nil),
+		},
+		{
+			name: "ordering items",
+			old:  base(),
+			new: modified(func(set *types.CertificationSet) {
+				set.Questions[3].Ordering = []string{"BIOS", "kernel", "boot loader"}
+			}),
+			want: certDiff([]QuestionChange{{
+				Kind: ChangeModified, QuestionID: 3,
+				CorrectChanged: true,
+				OldCorrect:     []string{`"1. BIOS"`, `"2. boot loader"`, `"3. kernel"`},
+				NewCorrect:     []string{`"1. BIOS"`, `"2. kernel"`, `"3. boot loader"`},
+			}}, nil),
+		},
+		{
+			name: "testsets renamed and added",
+			old:  base(),
+			new: modified(func(set *types.CertificationSet) {
+				set.Testsets = map[string]types.Testset{
+					"basics":  {TestsetName: "Kernel basics", QuestionsIds: []int{1, 2, 3}},
+					"modules": {TestsetName: "Modules", QuestionsIds: []int{1}},
+				}
+			}),
+			want: certDiff(nil, []TestsetChange{
+				{Kind: ChangeModified, TestsetID: "basics", OldName: "Basics", NewName: "Kernel basics"},
+				{Kind: ChangeAdded, TestsetID: "modules", NewName: "Modules", AddedQuestions: []int{1}},
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.old, tt.new)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare() = %+v, want %+v", got, tt.want)
+			}
+			if got.Empty() != (len(tt.want.Certifications) == 0) {
+				t.Errorf("Empty() = %v", got.Empty())
+			}
+		})
+	}
+}
+
+func TestCertificationDiffCount(t *testing.T) {
+	diff := CertificationDiff{Questions: []QuestionChange{
+		{Kind: ChangeAdded}, {Kind: ChangeModified}, {Kind: ChangeAdded},
+	}}
+	for kind, want := range map[ChangeKind]int{ChangeAdded: 2, ChangeRemoved: 0, ChangeModified: 1} {
+		if got := diff.Count(kind); got != want {
+			t.Errorf("Count(%s) = %d, want %d", kind, got, want)
+		}
+	}
+}
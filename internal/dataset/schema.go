@@ -0,0 +1,303 @@
+package dataset
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is the published JSON Schema of datasets.
+//
+//go:embed dataset.schema.json
+var Schema []byte
+
+// schemaKeywords are the keywords understood by schemaValidator. Loading a
+// schema with other keywords fails, so they cannot be ignored by accident.
+var schemaKeywords = map[string]bool{
+	"$schema": true, "$id": true, "$defs": true, "title": true, "description": true,
+	"$ref": true, "type": true, "enum": true, "required": true, "properties": true,
+	"additionalProperties": true, "propertyNames": true, "items": true,
+	"minItems": true, "minLength": true, "pattern": true,
+}
+
+// schemaValidator checks JSON values against the subset of JSON Schema used
+// by dataset.schema.json.
+type schemaValidator struct {
+	root     map[string]any
+	patterns map[string]*regexp.Regexp
+}
+
+func newSchemaValidator(schema []byte) (*schemaValidator, error) {
+	root, err := decodeJSON(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode schema: %w", err)
+	}
+	rootObject, ok := root.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("schema is not an object")
+	}
+	v := &schemaValidator{root: rootObject, patterns: make(map[string]*regexp.Regexp)}
+	if err := v.check(rootObject); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// check rejects unknown keywords and compiles all patterns.
+func (v *schemaValidator) check(schema map[string]any) error {
+	for keyword, value := range schema {
+		if !schemaKeywords[keyword] {
+			return fmt.Errorf("unsupported schema keyword %q", keyword)
+		}
+		switch keyword {
+		case "pattern":
+			pattern, _ := value.(string)
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid schema pattern %q: %w", pattern, err)
+			}
+			v.patterns[pattern] = re
+		case "$defs", "properties":
+			subs, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("schema keyword %q must be an object", keyword)
+			}
+			for name, sub := range subs {
+				subSchema, ok := sub.(map[string]any)
+				if !ok {
+					return fmt.Errorf("schema of %s %q must be an object", keyword, name)
+				}
+				if err := v.check(subSchema); err != nil {
+					return err
+				}
+			}
+		case "additionalProperties", "propertyNames", "items":
+			if _, ok := value.(bool); ok && keyword == "additionalProperties" {
+				continue
+			}
+			sub, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("unsupported form of schema keyword %q", keyword)
+			}
+			if err := v.check(sub); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validate calls report for every violation of schema by instance.
+func (v *schemaValidator) validate(schema map[string]any, instance any, path string, report func(path, message string)) {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, err := v.resolve(ref)
+		if err != nil {
+			report(path, err.Error())
+			return
+		}
+		v.validate(resolved, instance, path, report)
+	}
+
+	if types, ok := schema["type"]; ok && !matchesType(types, instance) {
+		report(path, fmt.Sprintf("expected %s, got %s", describeTypes(types), jsonType(instance)))
+		return
+	}
+	if enum, ok := schema["enum"].([]any); ok && !inEnum(enum, instance) {
+		report(path, fmt.Sprintf("value %s is not one of %s", encode(instance), encode(enum)))
+	}
+
+	switch value := instance.(type) {
+	case map[string]any:
+		v.validateObject(schema, value, path, report)
+	case []any:
+		if minItems, ok := schemaInt(schema["minItems"]); ok && len(value) < minItems {
+			report(path, fmt.Sprintf("expected at least %d item(s), got %d", minItems, len(value)))
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range value {
+				v.validate(items, item, path+"/"+strconv.Itoa(i), report)
+			}
+		}
+	case string:
+		v.validateString(schema, value, path, report)
+	}
+}
+
+func (v *schemaValidator) validateObject(schema map[string]any, object map[string]any, path string, report func(path, message string)) {
+	required, _ := schema["required"].([]any)
+	for _, name := range required {
+		if _, ok := object[name.(string)]; !ok {
+			report(path, fmt.Sprintf("missing required property %q", name))
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	names, _ := schema["propertyNames"].(map[string]any)
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyPath := path + "/" + escapePointer(key)
+		if names != nil {
+			v.validateString(names, key, keyPath, func(path, message string) {
+				report(path, "property name: "+message)
+			})
+		}
+		if property, ok := properties[key]; ok {
+			v.validate(property.(map[string]any), object[key], keyPath, report)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				report(keyPath, fmt.Sprintf("unknown property %q", key))
+			}
+		case map[string]any:
+			v.validate(additional, object[key], keyPath, report)
+		}
+	}
+}
+
+func (v *schemaValidator) validateString(schema map[string]any, value string, path string, report func(path, message string)) {
+	if minLength, ok := schemaInt(schema["minLength"]); ok && utf8.RuneCountInString(value) < minLength {
+		if minLength == 1 {
+			report(path, "must not be empty")
+		} else {
+			report(path, fmt.Sprintf("expected at least %d characters", minLength))
+		}
+	}
+	if pattern, ok := schema["pattern"].(string); ok && !v.patterns[pattern].MatchString(value) {
+		report(path, fmt.Sprintf("%q does not match %s", value, pattern))
+	}
+}
+
+func (v *schemaValidator) resolve(ref string) (map[string]any, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported schema reference %q", ref)
+	}
+	var current any = v.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable schema reference %q", ref)
+		}
+		current = object[unescapePointer(part)]
+	}
+	schema, ok := current.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unresolvable schema reference %q", ref)
+	}
+	return schema, nil
+}
+
+func matchesType(types any, instance any) bool {
+	switch t := types.(type) {
+	case string:
+		return isType(t, instance)
+	case []any:
+		for _, name := range t {
+			if isType(name.(string), instance) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isType(name string, instance any) bool {
+	switch name {
+	case "integer":
+		number, ok := instance.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := strconv.ParseInt(number.String(), 10, 64)
+		return err == nil
+	default:
+		return jsonType(instance) == name
+	}
+}
+
+func jsonType(instance any) string {
+	switch instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", instance)
+}
+
+func describeTypes(types any) string {
+	if list, ok := types.([]any); ok {
+		names := make([]string, 0, len(list))
+		for _, name := range list {
+			names = append(names, name.(string))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(types)
+}
+
+func inEnum(enum []any, instance any) bool {
+	encoded := encode(instance)
+	for _, value := range enum {
+		if encode(value) == encoded {
+			return true
+		}
+	}
+	return false
+}
+
+func encode(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func schemaInt(value any) (int, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	i, err := strconv.Atoi(number.String())
+	return i, err == nil
+}
+
+// decodeJSON decodes data keeping numbers as json.Number.
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func unescapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
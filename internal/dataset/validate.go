@@ -0,0 +1,388 @@
+// Package dataset checks question banks before they are shipped to the
+// client, e.g. from a pre-commit hook, and compares two versions of them.
+package dataset
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/objectives"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+type Severity string
+
+const (
+	// SeverityError marks data the client cannot use as intended.
+	SeverityError Severity = "error"
+	// SeverityWarning marks suspicious data the client still handles.
+	SeverityWarning Severity = "warning"
+)
+
+// Rules reported by Validate.
+const (
+	RuleJSON                   = "json"
+	RuleSchema                 = "schema"
+	RuleDuplicateKey           = "duplicate-key"
+	RuleDuplicateCertification = "duplicate-certification-id"
+	RuleDuplicateQuestionID    = "duplicate-question-id"
+	RuleQuestionIDMismatch     = "question-id-mismatch"
+	RuleNoCorrectAnswer        = "no-correct-answer"
+	RuleAllAnswersCorrect      = "all-answers-correct"
+	RuleEmptyAnswerID          = "empty-answer-id"
+	RuleDuplicateAnswerID      = "duplicate-answer-id"
+	RuleMissingQuestion        = "missing-question"
+	RuleDuplicateTestsetEntry  = "duplicate-testset-question"
+	RuleTestsetIDMismatch      = "testset-id-mismatch"
+	RuleEmptyTestset           = "empty-testset"
+	RuleUnknownObjective       = "unknown-objective"
+	RuleInvalidFillIn          = "invalid-fill-in"
+	RuleInvalidArrangement     = "invalid-arrangement"
+)
+
+// Issue is a single finding. Path is a JSON Pointer into the dataset.
+type Issue struct {
+	Severity        Severity `json:"severity"`
+	Rule            string   `json:"rule"`
+	Path            string   `json:"path"`
+	Message         string   `json:"message"`
+	CertificationID string   `json:"certificationId,omitempty"`
+	QuestionID      *int     `json:"questionId,omitempty"`
+	TestsetID       string   `json:"testsetId,omitempty"`
+}
+
+// Report is the outcome of validating one dataset.
+type Report struct {
+	File     string  `json:"file"`
+	Valid    bool    `json:"valid"`
+	Errors   int     `json:"errors"`
+	Warnings int     `json:"warnings"`
+	Issues   []Issue `json:"issues"`
+}
+
+func (r *Report) add(issue Issue) {
+	r.Issues = append(r.Issues, issue)
+	if issue.Severity == SeverityError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+}
+
+// embeddedSchema validates against Schema. It is loaded once.
+var embeddedSchema, embeddedSchemaErr = newSchemaValidator(Schema)
+
+// Validate checks the dataset JSON in data against the published Schema and
+// the content rules. A dataset is valid if no errors were found. An error is
+// only returned if the embedded Schema cannot be loaded.
+func Validate(data []byte) (*Report, error) {
+	if embeddedSchemaErr != nil {
+		return nil, fmt.Errorf("failed to load the dataset schema: %w", embeddedSchemaErr)
+	}
+	report := &Report{Issues: []Issue{}}
+	defer func() { report.Valid = report.Errors == 0 }()
+
+	instance, err := decodeJSON(data)
+	if err == nil {
+		err = trailingData(data)
+	}
+	if err != nil {
+		report.add(Issue{Severity: SeverityError, Rule: RuleJSON, Path: "", Message: err.Error()})
+		return report, nil
+	}
+
+	checkDuplicateKeys(data, report)
+
+	embeddedSchema.validate(embeddedSchema.root, instance, "", func(path, message string) {
+		report.add(Issue{Severity: SeverityError, Rule: RuleSchema, Path: path, Message: message})
+	})
+
+	var certSets []types.CertificationSet
+	if err := json.Unmarshal(data, &certSets); err != nil {
+		// The schema violations already describe why the dataset does not
+		// decode, and the content rules need the decoded dataset.
+		if report.Errors == 0 {
+			report.add(Issue{Severity: SeverityError, Rule: RuleJSON, Path: "", Message: err.Error()})
+		}
+		return report, nil
+	}
+	checkContent(certSets, report)
+	return report, nil
+}
+
+func trailingData(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var value json.RawMessage
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("unexpected data after the top-level value")
+	}
+	return nil
+}
+
+// checkDuplicateKeys reports object keys that occur more than once. They are
+// lost when the dataset is decoded, e.g. a second question with the same ID
+// silently replaces the first one.
+func checkDuplicateKeys(data []byte, report *Report) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var walk func(path []string) error
+	walk = func(path []string) error {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'):
+			seen := make(map[string]bool)
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				key := keyToken.(string)
+				if seen[key] {
+					report.add(duplicateKeyIssue(path, key))
+				}
+				seen[key] = true
+				if err := walk(append(path, key)); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+			return err
+		case json.Delim('['):
+			for i := 0; decoder.More(); i++ {
+				if err := walk(append(path, strconv.Itoa(i))); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+			return err
+		}
+		return nil
+	}
+	// The data was decoded before, so there are no syntax errors.
+	_ = walk(nil)
+}
+
+func duplicateKeyIssue(path []string, key string) Issue {
+	issue := Issue{Severity: SeverityError, Rule: RuleDuplicateKey, Path: pointer(append(path, key))}
+	if len(path) == 2 {
+		switch path[1] {
+		case "Questions":
+			issue.Rule = RuleDuplicateQuestionID
+			issue.Message = fmt.Sprintf("question %s is defined more than once, only the last definition is used", key)
+			if id, err := strconv.Atoi(key); err == nil {
+				issue.QuestionID = &id
+			}
+			return issue
+		case "Testsets":
+			issue.Message = fmt.Sprintf("testset %s is defined more than once, only the last definition is used", key)
+			issue.TestsetID = key
+			return issue
+		}
+	}
+	issue.Message = fmt.Sprintf("property %q is defined more than once, only the last value is used", key)
+	return issue
+}
+
+func checkContent(certSets []types.CertificationSet, report *Report) {
+	certIndex := make(map[string]int, len(certSets))
+	for i, certSet := range certSets {
+		certPath := "/" + strconv.Itoa(i)
+		certID := certSet.CertificationID
+		if first, ok := certIndex[certID]; ok {
+			report.add(Issue{Severity: SeverityError, Rule: RuleDuplicateCertification, Path: certPath + "/CertificationID", CertificationID: certID,
+				Message: fmt.Sprintf("certification %s is already defined at /%d, the client only loads the first one", certID, first)})
+		} else {
+			certIndex[certID] = i
+		}
+
+		checkQuestions(certSet, certPath, report)
+		checkTestsets(certSet, certPath, report)
+	}
+}
+
+func checkQuestions(certSet types.CertificationSet, certPath string, report *Report) {
+	keys := make([]int, 0, len(certSet.Questions))
+	for key := range certSet.Questions {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	// only certifications of a known exam can be checked for typos
+	knownObjectives := make(map[string]bool)
+	for _, objective := range objectives.ForCertification(certSet.CertificationID) {
+		knownObjectives[objective.ID] = true
+	}
+
+	definedAt := make(map[int]int, len(keys))
+	for _, key := range keys {
+		question := certSet.Questions[key]
+		questionPath := certPath + "/Questions/" + strconv.Itoa(key)
+		issue := func(severity Severity, rule, path, message string) {
+			id := question.ID
+			report.add(Issue{Severity: severity, Rule: rule, Path: path, Message: message, CertificationID: certSet.CertificationID, QuestionID: &id})
+		}
+
+		if question.ID != key {
+			issue(SeverityError, RuleQuestionIDMismatch, questionPath+"/ID",
+				fmt.Sprintf("question is stored under key %d but has ID %d", key, question.ID))
+		}
+		if other, ok := definedAt[question.ID]; ok {
+			issue(SeverityError, RuleDuplicateQuestionID, questionPath+"/ID",
+				fmt.Sprintf("question ID %d is also used by the question stored under key %d", question.ID, other))
+		} else {
+			definedAt[question.ID] = key
+		}
+
+		checkObjectives(question, questionPath, knownObjectives, issue)
+		if question.Kind == types.QuestionKindFillIn || question.FillIn != nil {
+			checkFillIn(question, questionPath, issue)
+			continue
+		}
+		if question.Kind == types.QuestionKindOrdering || question.Kind == types.QuestionKindMatching ||
+			len(question.Ordering) > 0 || len(question.Matching) > 0 {
+			checkArrangement(question, questionPath, issue)
+			continue
+		}
+
+		correct := 0
+		answerIDs := make(map[string]int, len(question.Answers))
+		for i, answer := range question.Answers {
+			answerPath := questionPath + "/Answers/" + strconv.Itoa(i)
+			if answer.IsCorrect {
+				correct++
+			}
+			if strings.TrimSpace(answer.AnswerID) == "" {
+				issue(SeverityError, RuleEmptyAnswerID, answerPath+"/AnswerID",
+					"answer has no AnswerID, marked answers cannot be stored")
+				continue
+			}
+			if first, ok := answerIDs[answer.AnswerID]; ok {
+				issue(SeverityError, RuleDuplicateAnswerID, answerPath+"/AnswerID",
+					fmt.Sprintf("AnswerID %q is also used by answer %d", answer.AnswerID, first))
+			} else {
+				answerIDs[answer.AnswerID] = i
+			}
+		}
+		switch {
+		case correct == 0:
+			issue(SeverityError, RuleNoCorrectAnswer, questionPath+"/Answers", "question has no correct answer")
+		case correct == len(question.Answers):
+			issue(SeverityError, RuleAllAnswersCorrect, questionPath+"/Answers", "all answers of the question are correct")
+		}
+	}
+}
+
+// checkObjectives warns about objectives of question unknown to the exam.
+func checkObjectives(question *types.Question, questionPath string, knownObjectives map[string]bool, issue func(severity Severity, rule, path, message string)) {
+	for i, id := range question.Objectives {
+		if len(knownObjectives) > 0 && !knownObjectives[id] {
+			issue(SeverityWarning, RuleUnknownObjective, questionPath+"/Objectives/"+strconv.Itoa(i),
+				fmt.Sprintf("objective %q is not an objective of the certification's exam", id))
+		}
+	}
+}
+
+// checkArrangement checks an ordering or matching question, which is
+// answered by arranging its Ordering items or Matching pairs.
+func checkArrangement(question *types.Question, questionPath string, issue func(severity Severity, rule, path, message string)) {
+	field, other := "Ordering", len(question.Matching) > 0
+	if question.Kind == types.QuestionKindMatching {
+		field, other = "Matching", len(question.Ordering) > 0
+	}
+	switch {
+	case question.Kind != types.QuestionKindOrdering && question.Kind != types.QuestionKindMatching:
+		issue(SeverityError, RuleInvalidArrangement, questionPath+"/Kind",
+			fmt.Sprintf("question has Ordering or Matching but its Kind is not %q or %q", types.QuestionKindOrdering, types.QuestionKindMatching))
+		return
+	case other:
+		issue(SeverityError, RuleInvalidArrangement, questionPath,
+			fmt.Sprintf("%s question must only have %s", question.Kind, field))
+	}
+	if len(question.Answers) > 0 {
+		issue(SeverityError, RuleInvalidArrangement, questionPath+"/Answers",
+			fmt.Sprintf("%s question must not have Answers", question.Kind))
+	}
+	if err := question.ValidateArrangement(); err != nil {
+		issue(SeverityError, RuleInvalidArrangement, questionPath+"/"+field, question.Kind+" question: "+err.Error())
+	}
+}
+
+// checkFillIn checks a fill-in-the-blank question, which is answered by
+// typing instead of marking Answers.
+func checkFillIn(question *types.Question, questionPath string, issue func(severity Severity, rule, path, message string)) {
+	switch {
+	case question.Kind != types.QuestionKindFillIn:
+		issue(SeverityError, RuleInvalidFillIn, questionPath+"/Kind",
+			fmt.Sprintf("question has FillIn but its Kind is not %q", types.QuestionKindFillIn))
+	case question.FillIn == nil:
+		issue(SeverityError, RuleInvalidFillIn, questionPath+"/FillIn", "fill-in question has no FillIn")
+		return
+	}
+	if len(question.Answers) > 0 {
+		issue(SeverityError, RuleInvalidFillIn, questionPath+"/Answers", "fill-in question must not have Answers")
+	}
+	if err := question.FillIn.Validate(); err != nil {
+		issue(SeverityError, RuleInvalidFillIn, questionPath+"/FillIn", "fill-in question: "+err.Error())
+	}
+}
+
+func checkTestsets(certSet types.CertificationSet, certPath string, report *Report) {
+	keys := make([]string, 0, len(certSet.Testsets))
+	for key := range certSet.Testsets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		testset := certSet.Testsets[key]
+		testsetPath := certPath + "/Testsets/" + escapePointer(key)
+		issue := func(severity Severity, rule, path, message string, questionID *int) {
+			report.add(Issue{Severity: severity, Rule: rule, Path: path, Message: message, CertificationID: certSet.CertificationID, TestsetID: key, QuestionID: questionID})
+		}
+
+		if testset.TestsetID != key {
+			issue(SeverityWarning, RuleTestsetIDMismatch, testsetPath+"/TestsetID",
+				fmt.Sprintf("testset is stored under key %s but has TestsetID %s, -testsetId uses the key", key, testset.TestsetID), nil)
+		}
+		if len(testset.QuestionsIds) == 0 {
+			issue(SeverityError, RuleEmptyTestset, testsetPath+"/QuestionsIds",
+				"testset has no questions and cannot be started", nil)
+		}
+
+		seen := make(map[int]bool, len(testset.QuestionsIds))
+		for i, questionID := range testset.QuestionsIds {
+			id := questionID
+			idPath := testsetPath + "/QuestionsIds/" + strconv.Itoa(i)
+			if _, ok := certSet.Questions[questionID]; !ok {
+				issue(SeverityError, RuleMissingQuestion, idPath,
+					fmt.Sprintf("question %d does not exist, the testset cannot be started", questionID), &id)
+			}
+			if seen[questionID] {
+				issue(SeverityWarning, RuleDuplicateTestsetEntry, idPath,
+					fmt.Sprintf("question %d is listed more than once", questionID), &id)
+			}
+			seen[questionID] = true
+		}
+	}
+}
+
+func pointer(path []string) string {
+	var b strings.Builder
+	for _, part := range path {
+		b.WriteString("/")
+		b.WriteString(escapePointer(part))
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package dataset
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSchemaLoads(t *testing.T) {
+	if embeddedSchemaErr != nil {
+		t.Fatalf("embedded schema: %v", embeddedSchemaErr)
+	}
+}
+
+func TestSchemaRejectsUnsupported(t *testing.T) {
+	tests := []struct {
+		name, schema, err string
+	}{
+		{"unknown keyword", `{"type": "object", "maxProperties": 3}`, `unsupported schema keyword "maxProperties"`},
+		{"nested unknown keyword", `{"properties": {"ID": {"type": "string", "format": "uuid"}}}`, `unsupported schema keyword "format"`},
+		{"unknown keyword in $defs", `{"$defs": {"id": {"oneOf": []}}}`, `unsupported schema keyword "oneOf"`},
+		{"unknown keyword in items", `{"items": {"uniqueItems": true}}`, `unsupported schema keyword "uniqueItems"`},
+		{"tuple items", `{"items": [{"type": "string"}]}`, `unsupported form of schema keyword "items"`},
+		{"properties of the wrong type", `{"properties": {"ID": true}}`, `schema of properties "ID" must be an object`},
+		{"invalid pattern", `{"pattern": "("}`, `invalid schema pattern "("`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newSchemaValidator([]byte(tt.schema))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+				t.Errorf("newSchemaValidator() = %v, want %s", err, tt.err)
+			}
+		})
+	}
+
+	if _, err := newSchemaValidator([]byte(`{"additionalProperties": false, "properties": {"ID": {"type": "string"}}}`)); err != nil {
+		t.Errorf("newSchemaValidator() = %v for a supported schema", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		file     string
+		valid    bool
+		errors   []string
+		warnings []string
+	}{
+		{file: "valid.json", valid: true},
+		{file: "duplicate-ids.json", errors: []string{RuleDuplicateQuestionID}},
+		{file: "missing-question.json", errors: []string{RuleMissingQuestion}},
+		{file: "no-correct-answer.json", errors: []string{RuleNoCorrectAnswer}},
+		{file: "unknown-property.json", errors: []string{RuleSchema}},
+		{file: "warnings.json", valid: true, warnings: []string{RuleDuplicateTestsetEntry, RuleTestsetIDMismatch}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			report, err := Validate(data)
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+
+			var errors, warnings []string
+			for _, issue := range report.Issues {
+				if issue.Severity == SeverityError {
+					errors = append(errors, issue.Rule)
+				} else {
+					warnings = append(warnings, issue.Rule)
+				}
+			}
+			sort.Strings(errors)
+			sort.Strings(warnings)
+			if report.Valid != tt.valid || !reflect.DeepEqual(errors, tt.errors) || !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("valid %v, errors %v, warnings %v; want %v, %v, %v; issues: %+v",
+					report.Valid, errors, warnings, tt.valid, tt.errors, tt.warnings, report.Issues)
+			}
+			if report.Errors != len(tt.errors) || report.Warnings != len(tt.warnings) {
+				t.Errorf("counted %d errors and %d warnings, want %d and %d", report.Errors, report.Warnings, len(tt.errors), len(tt.warnings))
+			}
+		})
+	}
+}
+
+func TestValidateMalformedJSON(t *testing.T) {
+	for _, data := range []string{`[{"CertificationID": "x"`, `[] []`, ``} {
+		report, err := Validate([]byte(data))
+		if err != nil {
+			t.Fatalf("Validate(%q): %v", data, err)
+		}
+		if report.Valid || len(report.Issues) != 1 || report.Issues[0].Rule != RuleJSON {
+			t.Errorf("Validate(%q) = %+v, want one %s error", data, report, RuleJSON)
+		}
+	}
+}
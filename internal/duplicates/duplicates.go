@@ -0,0 +1,277 @@
+// Package duplicates finds questions that ask the same with slightly
+// different wording, e.g. the copies of a question in several scraped
+// full_test_N and topic testsets.
+//
+// Every question is reduced to the character shingles of its text and
+// answers. MinHash signatures of the shingle sets are split into bands
+// (locality-sensitive hashing), so only questions sharing a band are compared.
+// Candidates whose shingle sets have a Jaccard similarity of at least the
+// threshold are clustered.
+package duplicates
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// DefaultThreshold is the Jaccard similarity from which two questions are
+// considered near-duplicates.
+const DefaultThreshold = 0.7
+
+const (
+	// shingleSize is the number of characters of a shingle. Short character
+	// shingles keep reworded questions similar where word shingles would
+	// differ in every shingle touching a changed word.
+	shingleSize = 3
+	// numHash is bands * rows. With 32 bands of 4 rows questions with a
+	// similarity of 0.5 become candidates with a probability of 87%, of 0.7
+	// with 99.98%.
+	bands    = 32
+	rows     = 4
+	numHash  = bands * rows
+	hashSeed = 1
+)
+
+// Cluster is a group of near-duplicate questions.
+type Cluster struct {
+	// IDs are the question IDs in ascending order.
+	IDs []int `json:"ids"`
+	// Similarity is the lowest similarity of the pairs that joined the cluster.
+	Similarity float64 `json:"similarity"`
+	// Testsets are the IDs of the testsets containing one of the questions.
+	Testsets []string `json:"testsets"`
+}
+
+// hashFunc is one of the permutations of the MinHash signature.
+type hashFunc struct {
+	a, b uint64
+}
+
+var hashFuncs = newHashFuncs()
+
+func newHashFuncs() []hashFunc {
+	// a fixed seed keeps the clusters stable between runs
+	rng := rand.New(rand.NewSource(hashSeed))
+	funcs := make([]hashFunc, numHash)
+	for i := range funcs {
+		funcs[i] = hashFunc{a: rng.Uint64() | 1, b: rng.Uint64()}
+	}
+	return funcs
+}
+
+func (h hashFunc) apply(x uint64) uint64 {
+	x = x*h.a + h.b
+	// mix the high bits into the low bits, the multiplication alone keeps the
+	// low bits of x
+	x ^= x >> 29
+	x *= 0xbf58476d1ce4e5b9
+	return x ^ x>>32
+}
+
+type document struct {
+	id        int
+	shingles  map[uint64]bool
+	signature [numHash]uint64
+}
+
+// Find returns the clusters of near-duplicate questions of certSet whose
+// similarity is at least threshold, ordered by their first question ID.
+func Find(certSet *types.CertificationSet, threshold float64) []Cluster {
+	docs := make([]*document, 0, len(certSet.Questions))
+	for _, question := range certSet.Questions {
+		docs = append(docs, newDocument(question))
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].id < docs[j].id })
+
+	// questions sharing the signature of a band are candidates
+	buckets := make(map[uint64][]int)
+	for i, doc := range docs {
+		for band := 0; band < bands; band++ {
+			key := bandKey(band, doc.signature[band*rows:(band+1)*rows])
+			buckets[key] = append(buckets[key], i)
+		}
+	}
+
+	u := newUnionFind(len(docs))
+	similarity := make(map[int]float64) // lowest similarity per root
+	compared := make(map[[2]int]bool)
+	for _, bucket := range buckets {
+		for x := 0; x < len(bucket); x++ {
+			for y := x + 1; y < len(bucket); y++ {
+				pair := [2]int{bucket[x], bucket[y]}
+				if compared[pair] {
+					continue
+				}
+				compared[pair] = true
+				s := jaccard(docs[pair[0]].shingles, docs[pair[1]].shingles)
+				if s < threshold {
+					continue
+				}
+				lowest := s
+				for _, i := range pair {
+					if v, ok := similarity[u.find(i)]; ok && v < lowest {
+						lowest = v
+					}
+				}
+				similarity[u.union(pair[0], pair[1])] = lowest
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	for i := range docs {
+		root := u.find(i)
+		members[root] = append(members[root], i)
+	}
+	testsets := questionTestsets(certSet)
+	var clusters []Cluster
+	for root, indexes := range members {
+		if len(indexes) < 2 {
+			continue
+		}
+		cluster := Cluster{Similarity: similarity[root]}
+		seen := make(map[string]bool)
+		for _, i := range indexes {
+			cluster.IDs = append(cluster.IDs, docs[i].id)
+			for _, testset := range testsets[docs[i].id] {
+				if !seen[testset] {
+					seen[testset] = true
+					cluster.Testsets = append(cluster.Testsets, testset)
+				}
+			}
+		}
+		sort.Ints(cluster.IDs)
+		sort.Strings(cluster.Testsets)
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].IDs[0] < clusters[j].IDs[0] })
+	return clusters
+}
+
+// Assign marks every question of clusters but the first as a duplicate of
+// the first, so statistics count each cluster once. It returns the number of
+// marked questions.
+func Assign(certSet *types.CertificationSet, clusters []Cluster) int {
+	marked := 0
+	for _, cluster := range clusters {
+		for _, id := range cluster.IDs[1:] {
+			if question, ok := certSet.Questions[id]; ok {
+				question.SetDuplicateOf(cluster.IDs[0])
+				marked++
+			}
+		}
+	}
+	return marked
+}
+
+func newDocument(question *types.Question) *document {
+	// answers are often shuffled between copies
+	var answers []string
+	for _, text := range question.SolutionTexts() {
+		answers = append(answers, normalize(text))
+	}
+	sort.Strings(answers)
+	text := []rune(strings.Join(append([]string{normalize(question.Text)}, answers...), " "))
+
+	doc := &document{id: question.ID, shingles: make(map[uint64]bool)}
+	for i := 0; i+shingleSize <= len(text) || i == 0; i++ {
+		end := min(i+shingleSize, len(text))
+		h := fnv.New64a()
+		h.Write([]byte(string(text[i:end])))
+		doc.shingles[h.Sum64()] = true
+	}
+
+	for i := range doc.signature {
+		doc.signature[i] = ^uint64(0)
+	}
+	for shingle := range doc.shingles {
+		for i, f := range hashFuncs {
+			if v := f.apply(shingle); v < doc.signature[i] {
+				doc.signature[i] = v
+			}
+		}
+	}
+	return doc
+}
+
+// normalize lowercases text and reduces everything but letters and digits to
+// single spaces, so punctuation and formatting do not count as differences.
+func normalize(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+func bandKey(band int, values []uint64) uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(band))
+	h.Write(buf[:])
+	for _, v := range values {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
+
+func jaccard(a, b map[uint64]bool) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for shingle := range a {
+		if b[shingle] {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 1
+	}
+	return float64(shared) / float64(union)
+}
+
+func questionTestsets(certSet *types.CertificationSet) map[int][]string {
+	testsets := make(map[int][]string)
+	for id, testset := range certSet.Testsets {
+		for _, questionID := range testset.QuestionsIds {
+			testsets[questionID] = append(testsets[questionID], id)
+		}
+	}
+	return testsets
+}
+
+type unionFind struct {
+	parent []int
+}
+
+func newUnionFind(n int) *unionFind {
+	u := &unionFind{parent: make([]int, n)}
+	for i := range u.parent {
+		u.parent[i] = i
+	}
+	return u
+}
+
+func (u *unionFind) find(i int) int {
+	for u.parent[i] != i {
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+	return i
+}
+
+// union joins the sets of i and j and returns the root of the joined set.
+func (u *unionFind) union(i, j int) int {
+	ri, rj := u.find(i), u.find(j)
+	if ri != rj {
+		u.parent[rj] = ri
+	}
+	return ri
+}
@@ -0,0 +1,111 @@
+package duplicates
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+func question(id int, text string, answers ...string) *types.Question {
+	question := &types.Question{ID: id, Text: text}
+	for i, answer := range answers {
+		question.Answers = append(question.Answers, &types.Answer{Text: answer, IsCorrect: i == 0})
+	}
+	return question
+}
+
+func testCertSet() *types.CertificationSet {
+	questions := []*types.Question{
+		question(1, "Which command lists the loaded kernel modules?", "lsmod", "modprobe -l", "insmod"),
+		// the same question with shuffled answers and other punctuation
+		question(7, "Which command lists the loaded kernel-modules:", "insmod", "LSMOD", "modprobe -l"),
+		// reworded
+		question(12, "Which command lists all loaded kernel modules?", "lsmod", "modprobe -l", "insmod"),
+		question(3, "Which file contains the mounted filesystems?", "/etc/mtab", "/etc/fstab"),
+		question(4, "Which command changes the owner of a file?", "chown", "chmod"),
+		question(5, "Which file contains the mounted file systems?", "/etc/mtab", "/etc/fstab"),
+	}
+	certSet := &types.CertificationSet{
+		Questions: make(map[int]*types.Question),
+		Testsets: map[string]types.Testset{
+			"full_test_1": {QuestionsIds: []int{1, 3, 4}},
+			"full_test_2": {QuestionsIds: []int{7, 5}},
+			"modules":     {QuestionsIds: []int{1, 12}},
+		},
+	}
+	for _, q := range questions {
+		certSet.Questions[q.ID] = q
+	}
+	return certSet
+}
+
+func TestFind(t *testing.T) {
+	clusters := Find(testCertSet(), DefaultThreshold)
+	if len(clusters) != 2 {
+		t.Fatalf("Find() = %+v, want 2 clusters", clusters)
+	}
+
+	modules := clusters[0]
+	if !reflect.DeepEqual(modules.IDs, []int{1, 7, 12}) || !reflect.DeepEqual(modules.Testsets, []string{"full_test_1", "full_test_2", "modules"}) {
+		t.Errorf("first cluster = %+v, want questions 1, 7 and 12 of all testsets", modules)
+	}
+	if modules.Similarity < DefaultThreshold || modules.Similarity == 1 {
+		t.Errorf("similarity of the reworded question = %v, want below 1 and at least %v", modules.Similarity, DefaultThreshold)
+	}
+
+	mtab := clusters[1]
+	if !reflect.DeepEqual(mtab.IDs, []int{3, 5}) || !reflect.DeepEqual(mtab.Testsets, []string{"full_test_1", "full_test_2"}) {
+		t.Errorf("second cluster = %+v, want questions 3 and 5", mtab)
+	}
+
+	if again := Find(testCertSet(), DefaultThreshold); !reflect.DeepEqual(again, clusters) {
+		t.Errorf("Find() changed from %+v to %+v", clusters, again)
+	}
+	if strict := Find(testCertSet(), 1); len(strict) != 1 || !reflect.DeepEqual(strict[0].IDs, []int{1, 7}) || strict[0].Similarity != 1 {
+		t.Errorf("Find() with threshold 1 = %+v, want only the copies 1 and 7", strict)
+	}
+}
+
+func TestAssign(t *testing.T) {
+	certSet := testCertSet()
+	clusters := []Cluster{{IDs: []int{1, 7, 12}}, {IDs: []int{3, 5, 99}}}
+	if marked := Assign(certSet, clusters); marked != 3 {
+		t.Errorf("Assign() = %d, want 3", marked)
+	}
+	for id, want := range map[int]int{1: 0, 7: 1, 12: 1, 3: 0, 5: 3, 4: 0} {
+		if got := certSet.Questions[id].GetDuplicateOf(); got != want {
+			t.Errorf("question %d is a duplicate of %d, want %d", id, got, want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if got := normalize("  Which   FILE: /etc/fstab?\n"); got != "which file etc fstab" {
+		t.Errorf("normalize() = %q", got)
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	set := func(values ...uint64) map[uint64]bool {
+		s := make(map[uint64]bool)
+		for _, v := range values {
+			s[v] = true
+		}
+		return s
+	}
+	tests := []struct {
+		a, b map[uint64]bool
+		want float64
+	}{
+		{set(1, 2, 3), set(1, 2, 3), 1},
+		{set(1, 2, 3), set(2, 3, 4), 0.5},
+		{set(1, 2), set(3, 4), 0},
+		{set(), set(), 1},
+	}
+	for _, tt := range tests {
+		if got := jaccard(tt.a, tt.b); got != tt.want {
+			t.Errorf("jaccard(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package exam
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/SqiSch/lpic-cli/internal/objectives"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+const (
+	// DefaultQuestionCount and DefaultTimeLimit mirror a single LPIC-1 exam.
+	DefaultQuestionCount = 60
+	DefaultTimeLimit     = 90 * time.Minute
+
+	MinScore  = 200
+	MaxScore  = 800
+	PassScore = 500
+
+	// PassRatio is the share of correct answers that maps to PassScore.
+	// LPI does not publish its scaling, so this is an approximation.
+	PassRatio = 0.65
+)
+
+// Draw picks count questions at random from the whole certification set.
+// Like the real exam, the questions are spread over the objectives of the
+// certification's exam by their weight. Questions without a known objective
+// fill the places of objectives with too few questions, and all questions are
+// drawn alike for certifications of an unknown exam. The returned questions
+// are copies with no marked answers, so an exam never starts with answers
+// from earlier practice sessions.
+func Draw(certSet *types.CertificationSet, count int, rng *rand.Rand) ([]*types.Question, error) {
+	if len(certSet.Questions) == 0 {
+		return nil, fmt.Errorf("no questions in certification set")
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("invalid question count %d", count)
+	}
+
+	ids := make([]int, 0, len(certSet.Questions))
+	for id := range certSet.Questions {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	rng.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	if count > len(ids) {
+		count = len(ids)
+	}
+
+	drawn := drawByObjective(certSet, ids, count)
+	rng.Shuffle(len(drawn), func(i, j int) { drawn[i], drawn[j] = drawn[j], drawn[i] })
+
+	questions := make([]*types.Question, 0, count)
+	for _, id := range drawn {
+		questions = append(questions, freshCopy(certSet.Questions[id]))
+	}
+	return questions, nil
+}
+
+// drawByObjective takes count of the shuffled ids. Every objective of the
+// exam gets its share of count by weight, filled by the first questions whose
+// first objective it is; places left open go to the first questions not
+// taken yet.
+func drawByObjective(certSet *types.CertificationSet, ids []int, count int) []int {
+	catalog := objectives.ForCertification(certSet.CertificationID)
+	totalWeight := 0
+	for _, objective := range catalog {
+		totalWeight += objective.Weight
+	}
+	if totalWeight == 0 {
+		return ids[:count]
+	}
+
+	pools := make(map[string][]int, len(catalog))
+	for _, id := range ids {
+		if question := certSet.Questions[id]; len(question.Objectives) > 0 {
+			pools[question.Objectives[0]] = append(pools[question.Objectives[0]], id)
+		}
+	}
+
+	// largest remainder, so the places add up to count
+	places := make([]int, len(catalog))
+	order := make([]int, len(catalog))
+	left := count
+	for i, objective := range catalog {
+		places[i] = count * objective.Weight / totalWeight
+		left -= places[i]
+		order[i] = i
+	}
+	remainder := func(i int) int { return count * catalog[i].Weight % totalWeight }
+	sort.SliceStable(order, func(a, b int) bool { return remainder(order[a]) > remainder(order[b]) })
+	for _, i := range order[:left] {
+		places[i]++
+	}
+
+	drawn := make([]int, 0, count)
+	taken := make(map[int]bool, count)
+	for i, objective := range catalog {
+		pool := pools[objective.ID]
+		for _, id := range pool[:min(places[i], len(pool))] {
+			drawn = append(drawn, id)
+			taken[id] = true
+		}
+	}
+	for _, id := range ids {
+		if len(drawn) == count {
+			break
+		}
+		if !taken[id] {
+			drawn = append(drawn, id)
+		}
+	}
+	return drawn
+}
+
+func freshCopy(question *types.Question) *types.Question {
+	answers := make([]*types.Answer, 0, len(question.Answers))
+	for _, answer := range question.Answers {
+		answers = append(answers, &types.Answer{
+			Text:      answer.Text,
+			IsCorrect: answer.IsCorrect,
+			AnswerID:  answer.AnswerID,
+		})
+	}
+	return &types.Question{
+		ID:          question.ID,
+		Text:        question.Text,
+		Answers:     answers,
+		Explanation: question.Explanation,
+		Objectives:  question.Objectives,
+		Kind:        question.Kind,
+		FillIn:      question.FillIn,
+		Ordering:    question.Ordering,
+		Matching:    question.Matching,
+	}
+}
+
+// ScaledScore maps a raw result of points out of total questions onto the
+// LPI 200-800 scale. The scale is piecewise linear so that PassRatio lands
+// exactly on PassScore.
+func ScaledScore(points float64, total int) int {
+	if total <= 0 {
+		return MinScore
+	}
+	ratio := points / float64(total)
+	var score float64
+	if ratio < PassRatio {
+		score = MinScore + (PassScore-MinScore)*ratio/PassRatio
+	} else {
+		score = PassScore + (MaxScore-PassScore)*(ratio-PassRatio)/(1-PassRatio)
+	}
+	return int(score + 0.5)
+}
+
+// Score grades the questions of an attempt and builds the result including
+// a breakdown for every testset that contributed at least one question.
+func Score(certSet *types.CertificationSet, questions []*types.Question, startedAt, finishedAt time.Time) *types.ExamResult {
+	result := &types.ExamResult{
+		ID:              fmt.Sprintf("%s-%d", certSet.CertificationID, startedAt.UnixNano()),
+		CertificationID: certSet.CertificationID,
+		StartedAt:       startedAt,
+		FinishedAt:      finishedAt,
+		MarkedAnswers:   make(map[int][]string),
+		Total:           len(questions),
+	}
+
+	drawn := make(map[int]bool, len(questions))
+	correct := make(map[int]bool, len(questions))
+	for _, question := range questions {
+		drawn[question.ID] = true
+		result.QuestionIDs = append(result.QuestionIDs, question.ID)
+		for _, answer := range question.GetAnsweredOptions() {
+			result.MarkedAnswers[question.ID] = append(result.MarkedAnswers[question.ID], answer.AnswerID)
+		}
+		if question.IsAnsweredCorrectly() {
+			correct[question.ID] = true
+			result.Correct++
+		}
+		result.Points += question.Credit()
+	}
+	result.ScaledScore = ScaledScore(result.Points, result.Total)
+	result.Passed = result.ScaledScore >= PassScore
+
+	for testsetID, testset := range certSet.Testsets {
+		score := types.TestsetScore{TestsetID: testsetID, TestsetName: testset.TestsetName}
+		for _, id := range testset.QuestionsIds {
+			if !drawn[id] {
+				continue
+			}
+			score.Total++
+			if correct[id] {
+				score.Correct++
+			}
+		}
+		if score.Total > 0 {
+			result.Testsets = append(result.Testsets, score)
+		}
+	}
+	sort.Slice(result.Testsets, func(i, j int) bool {
+		return result.Testsets[i].TestsetID < result.Testsets[j].TestsetID
+	})
+
+	return result
+}
@@ -0,0 +1,273 @@
+package exam
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SqiSch/lpic-cli/internal/objectives"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+func TestScaledScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		points float64
+		total  int
+		want   int
+	}{
+		{"nothing correct", 0, 60, MinScore},
+		{"half of the pass ratio", 19.5, 60, 350},
+		{"pass ratio", 39, 60, PassScore},
+		{"just below the pass ratio", 38.5, 60, 496},
+		{"everything correct", 60, 60, MaxScore},
+		{"partial credit", 32.5, 50, PassScore},
+		{"empty exam", 0, 0, MinScore},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScaledScore(tt.points, tt.total); got != tt.want {
+				t.Errorf("ScaledScore(%v, %d) = %d, want %d", tt.points, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+// answered returns a two answer question, answered correctly if correct and
+// wrong otherwise.
+func answered(id int, correct bool) *types.Question {
+	question := &types.Question{ID: id, Answers: []*types.Answer{
+		{AnswerID: "a", IsCorrect: true},
+		{AnswerID: "b"},
+	}}
+	if correct {
+		question.Answers[0].SetIsMarked(true)
+	} else {
+		question.Answers[1].SetIsMarked(true)
+	}
+	return question
+}
+
+func TestScore(t *testing.T) {
+	certSet := &types.CertificationSet{
+		CertificationID: "lpic1-101-500",
+		Testsets: map[string]types.Testset{
+			"a": {TestsetName: "A", QuestionsIds: []int{1, 2, 3}},
+			"b": {TestsetName: "B", QuestionsIds: []int{4, 5}},
+			"c": {TestsetName: "C", QuestionsIds: []int{6}},
+		},
+	}
+	startedAt := time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		questions []*types.Question
+		correct   int
+		score     int
+		passed    bool
+		testsets  []types.TestsetScore
+	}{
+		{
+			name:      "nothing correct",
+			questions: []*types.Question{answered(1, false), answered(4, false)},
+			score:     MinScore,
+			testsets:  []types.TestsetScore{{TestsetID: "a", TestsetName: "A", Total: 1}, {TestsetID: "b", TestsetName: "B", Total: 1}},
+		},
+		{
+			name:      "everything correct",
+			questions: []*types.Question{answered(1, true), answered(2, true), answered(4, true)},
+			correct:   3, score: MaxScore, passed: true,
+			testsets: []types.TestsetScore{{TestsetID: "a", TestsetName: "A", Correct: 2, Total: 2}, {TestsetID: "b", TestsetName: "B", Correct: 1, Total: 1}},
+		},
+		{
+			name:      "unanswered questions count as wrong",
+			questions: []*types.Question{answered(1, true), {ID: 5, Answers: []*types.Answer{{AnswerID: "a", IsCorrect: true}}}},
+			correct:   1, score: 431,
+			testsets: []types.TestsetScore{{TestsetID: "a", TestsetName: "A", Correct: 1, Total: 1}, {TestsetID: "b", TestsetName: "B", Total: 1}},
+		},
+		{
+			name:  "empty exam",
+			score: MinScore,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Score(certSet, tt.questions, startedAt, startedAt.Add(time.Hour))
+			if result.Total != len(tt.questions) || result.Correct != tt.correct || result.Points != float64(tt.correct) {
+				t.Errorf("got %d of %d correct, %v points; want %d of %d", result.Correct, result.Total, result.Points, tt.correct, len(tt.questions))
+			}
+			if result.ScaledScore != tt.score || result.Passed != tt.passed {
+				t.Errorf("ScaledScore = %d, Passed = %v; want %d, %v", result.ScaledScore, result.Passed, tt.score, tt.passed)
+			}
+			if !reflect.DeepEqual(result.Testsets, tt.testsets) {
+				t.Errorf("Testsets = %+v, want %+v", result.Testsets, tt.testsets)
+			}
+			for _, question := range tt.questions {
+				if marked := question.GetAnsweredOptions(); len(marked) != len(result.MarkedAnswers[question.ID]) {
+					t.Errorf("MarkedAnswers[%d] = %v, want %d answers", question.ID, result.MarkedAnswers[question.ID], len(marked))
+				}
+			}
+		})
+	}
+}
+
+// objectiveCertSet returns a certification set of an exam with perObjective
+// questions for every objective and unclassified questions without one.
+func objectiveCertSet(certificationID string, perObjective, unclassified int) *types.CertificationSet {
+	certSet := &types.CertificationSet{CertificationID: certificationID, Questions: make(map[int]*types.Question)}
+	add := func(objectiveIDs ...string) {
+		id := len(certSet.Questions) + 1
+		question := answered(id, true)
+		question.Text = fmt.Sprintf("Question %d", id)
+		question.Objectives = objectiveIDs
+		certSet.Questions[id] = question
+	}
+	for _, objective := range objectives.ForCertification(certificationID) {
+		for range perObjective {
+			add(objective.ID)
+		}
+	}
+	for range unclassified {
+		add()
+	}
+	return certSet
+}
+
+func TestDraw(t *testing.T) {
+	catalog := objectives.ForCertification("lpic1-101-500")
+	totalWeight := 0
+	for _, objective := range catalog {
+		totalWeight += objective.Weight
+	}
+
+	tests := []struct {
+		name             string
+		certSet          *types.CertificationSet
+		count            int
+		wantCount        int
+		perObjective     func(weight int) int
+		rounding         bool
+		wantUnclassified int
+	}{
+		{
+			name:         "one question per weight",
+			certSet:      objectiveCertSet("lpic1-101-500", 10, 20),
+			count:        totalWeight,
+			wantCount:    totalWeight,
+			perObjective: func(weight int) int { return weight },
+		},
+		{
+			name:         "half the exam",
+			certSet:      objectiveCertSet("lpic1-101-500", 10, 20),
+			count:        totalWeight / 2,
+			wantCount:    totalWeight / 2,
+			perObjective: func(weight int) int { return (weight + 1) / 2 },
+			// the odd weights share the places left by rounding down
+			rounding: true,
+		},
+		{
+			name:             "unclassified questions fill small objectives",
+			certSet:          objectiveCertSet("lpic1-101-500", 1, 100),
+			count:            totalWeight,
+			wantCount:        totalWeight,
+			perObjective:     func(int) int { return 1 },
+			wantUnclassified: totalWeight - len(catalog),
+		},
+		{
+			name:      "unknown exam",
+			certSet:   objectiveCertSet("custom", 0, 30),
+			count:     10,
+			wantCount: 10,
+		},
+		{
+			name:      "more than there are",
+			certSet:   objectiveCertSet("custom", 0, 5),
+			count:     10,
+			wantCount: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			questions, err := Draw(tt.certSet, tt.count, rand.New(rand.NewSource(1)))
+			if err != nil {
+				t.Fatalf("Draw: %v", err)
+			}
+			if len(questions) != tt.wantCount {
+				t.Fatalf("drew %d questions, want %d", len(questions), tt.wantCount)
+			}
+
+			seen := make(map[int]bool, len(questions))
+			byObjective := make(map[string]int)
+			unclassified := 0
+			for _, question := range questions {
+				if seen[question.ID] {
+					t.Errorf("question %d drawn twice", question.ID)
+				}
+				seen[question.ID] = true
+				if question == tt.certSet.Questions[question.ID] || len(question.GetAnsweredOptions()) != 0 {
+					t.Errorf("question %d is not a fresh copy", question.ID)
+				}
+				if len(question.Objectives) == 0 {
+					unclassified++
+				} else {
+					byObjective[question.Objectives[0]]++
+				}
+			}
+			if tt.perObjective != nil {
+				for _, objective := range catalog {
+					got, want := byObjective[objective.ID], tt.perObjective(objective.Weight)
+					if got != want && !(tt.rounding && got == want-1) {
+						t.Errorf("objective %s (weight %d): %d questions, want %d", objective.ID, objective.Weight, got, want)
+					}
+				}
+				if unclassified != tt.wantUnclassified {
+					t.Errorf("%d unclassified questions, want %d", unclassified, tt.wantUnclassified)
+				}
+			}
+		})
+	}
+}
+
+func TestDrawSeed(t *testing.T) {
+	certSet := objectiveCertSet("lpic1-101-500", 5, 10)
+	ids := func(seed int64) []int {
+		questions, err := Draw(certSet, 30, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			t.Fatalf("Draw: %v", err)
+		}
+		ids := make([]int, 0, len(questions))
+		for _, question := range questions {
+			ids = append(ids, question.ID)
+		}
+		return ids
+	}
+	if first, again := ids(42), ids(42); !reflect.DeepEqual(first, again) {
+		t.Errorf("the same seed drew %v and %v", first, again)
+	}
+	if first, other := ids(42), ids(43); reflect.DeepEqual(first, other) {
+		t.Errorf("different seeds drew the same questions %v", first)
+	}
+
+	if _, err := Draw(&types.CertificationSet{}, 10, rand.New(rand.NewSource(1))); err == nil {
+		t.Error("drew from an empty certification set")
+	}
+	if _, err := Draw(certSet, 0, rand.New(rand.NewSource(1))); err == nil {
+		t.Error("drew zero questions")
+	}
+}
+
+func TestScorePartialCredit(t *testing.T) {
+	ordering := &types.Question{ID: 2, Kind: types.QuestionKindOrdering, Ordering: []string{"BIOS", "boot loader", "kernel", "init"}}
+	ordering.Arrange([]int{0, 2, 1, 3})
+	questions := []*types.Question{answered(1, true), ordering}
+
+	result := Score(&types.CertificationSet{CertificationID: "lpic1-101-500"}, questions, time.Time{}, time.Time{})
+	if result.Correct != 1 || result.Points != 1.5 {
+		t.Errorf("got %d correct and %v points, want 1 and 1.5", result.Correct, result.Points)
+	}
+	if want := ScaledScore(1.5, 2); result.ScaledScore != want {
+		t.Errorf("ScaledScore = %d, want %d", result.ScaledScore, want)
+	}
+}
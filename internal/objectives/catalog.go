@@ -0,0 +1,180 @@
+package objectives
+
+// topics are the topic titles of the LPIC-1 exams 101-500 and 102-500.
+var topics = map[string]string{
+	"101": "System Architecture",
+	"102": "Linux Installation and Package Management",
+	"103": "GNU and Unix Commands",
+	"104": "Devices, Linux Filesystems, Filesystem Hierarchy Standard",
+	"105": "Shells and Shell Scripting",
+	"106": "User Interfaces and Desktops",
+	"107": "Administrative Tasks",
+	"108": "Essential System Services",
+	"109": "Networking Fundamentals",
+	"110": "Security",
+}
+
+// catalog holds the objectives of LPIC-1 version 5.0 with their weights.
+// Commands that are also common English words in questions, like which,
+// who or less, are left out of the keywords.
+var catalog = []Objective{
+	{ID: "101.1", Title: "Determine and configure hardware settings", Weight: 2, Keywords: []string{
+		"lsusb", "lspci", "lsmod", "modprobe", "insmod", "rmmod", "modinfo", "udev", "udevadm", "sysfs", "/sys",
+		"dbus", "hotplug", "coldplug", "kernel module", "kernel modules", "/proc/interrupts", "/proc/ioports", "/proc/dma",
+	}},
+	{ID: "101.2", Title: "Boot the system", Weight: 3, Keywords: []string{
+		"dmesg", "bootloader", "boot loader", "initramfs", "initrd", "kernel parameters", "boot process", "boot sequence",
+		"upstart", "sysvinit", "uefi", "bios",
+	}},
+	{ID: "101.3", Title: "Change runlevels / boot targets and shutdown or reboot system", Weight: 3, Keywords: []string{
+		"runlevel", "runlevels", "telinit", "shutdown", "reboot", "halt", "poweroff", "systemctl", "inittab", "/etc/inittab",
+		"boot target", "default.target", "multi-user.target", "graphical.target", "rescue.target", "emergency.target",
+		"isolate", "wall", "acpid",
+	}},
+	{ID: "102.1", Title: "Design hard disk layout", Weight: 2, Keywords: []string{
+		"swap space", "partition scheme", "partitioning scheme", "lvm", "logical volume", "logical volumes",
+		"pvcreate", "vgcreate", "lvcreate", "mount point", "efi system partition",
+	}},
+	{ID: "102.2", Title: "Install a boot manager", Weight: 2, Keywords: []string{
+		"grub", "grub2", "grub-install", "grub-mkconfig", "grub2-mkconfig", "update-grub", "grub.cfg", "menu.lst",
+		"/etc/default/grub", "mbr", "boot manager",
+	}},
+	{ID: "102.3", Title: "Manage shared libraries", Weight: 1, Keywords: []string{
+		"ldd", "ldconfig", "ld.so", "ld.so.conf", "ld.so.cache", "/etc/ld.so.conf", "ld_library_path", "$ld_library_path",
+		"shared library", "shared libraries",
+	}},
+	{ID: "102.4", Title: "Use Debian package management", Weight: 3, Keywords: []string{
+		"dpkg", "dpkg-reconfigure", "apt", "apt-get", "apt-cache", "aptitude", "sources.list", "/etc/apt/sources.list",
+		".deb", "deb",
+	}},
+	{ID: "102.5", Title: "Use RPM and YUM package management", Weight: 3, Keywords: []string{
+		"rpm", "rpm2cpio", "yum", "yumdownloader", "dnf", "zypper", "yum.conf", "/etc/yum.conf", "/etc/yum.repos.d", ".rpm",
+	}},
+	{ID: "102.6", Title: "Linux as a virtualization guest", Weight: 1, Keywords: []string{
+		"virtualization", "virtual machine", "virtual machines", "hypervisor", "container", "containers", "guest",
+		"cloud-init", "machine-id", "/etc/machine-id", "iaas",
+	}},
+	{ID: "103.1", Title: "Work on the command line", Weight: 4, Keywords: []string{
+		"bash", "echo", "env", "unset", "pwd", "uname", "history", ".bash_history", "histfile", "$histfile", "$path",
+		"man", "apropos", "whatis", "exec", "quoting",
+	}},
+	{ID: "103.2", Title: "Process text streams using filters", Weight: 2, Keywords: []string{
+		"bzcat", "xzcat", "zcat", "cut", "head", "tail", "nl", "od", "paste", "sed", "sort", "split", "tr",
+		"uniq", "wc", "md5sum", "sha256sum", "sha512sum",
+	}},
+	{ID: "103.3", Title: "Perform basic file management", Weight: 4, Keywords: []string{
+		"cp", "mv", "rm", "mkdir", "rmdir", "touch", "ls", "find", "tar", "cpio", "dd", "gzip", "gunzip", "bzip2",
+		"bunzip2", "xz", "unxz", "file globbing", "globbing", "wildcard", "wildcards",
+	}},
+	{ID: "103.4", Title: "Use streams, pipes and redirects", Weight: 4, Keywords: []string{
+		"tee", "xargs", "stdin", "stdout", "stderr", "standard input", "standard output", "standard error",
+		"redirect", "redirection", "redirects", "pipe", "pipes", "command substitution",
+	}},
+	{ID: "103.5", Title: "Create, monitor and kill processes", Weight: 4, Keywords: []string{
+		"ps", "top", "kill", "killall", "pkill", "pgrep", "free", "uptime", "nohup", "jobs", "bg", "fg", "screen",
+		"tmux", "watch", "signal", "signals", "sighup", "sigterm", "sigkill", "background job",
+	}},
+	{ID: "103.6", Title: "Modify process execution priorities", Weight: 2, Keywords: []string{
+		"nice", "renice", "niceness", "nice value", "priority", "priorities",
+	}},
+	{ID: "103.7", Title: "Search text files using regular expressions", Weight: 3, Keywords: []string{
+		"grep", "egrep", "fgrep", "sed", "regex", "regexp", "regular expression", "regular expressions",
+	}},
+	{ID: "103.8", Title: "Basic file editing", Weight: 3, Keywords: []string{
+		"vi", "vim", "nano", "emacs", "editor", "$editor", "insert mode", "command mode",
+	}},
+	{ID: "104.1", Title: "Create partitions and filesystems", Weight: 2, Keywords: []string{
+		"fdisk", "gdisk", "parted", "mkfs", "mkfs.ext4", "mkfs.xfs", "mkfs.vfat", "mke2fs", "mkswap", "partition",
+		"partitions", "partition table", "gpt", "ext2", "ext3", "ext4", "xfs", "vfat", "exfat", "btrfs",
+	}},
+	{ID: "104.2", Title: "Maintain the integrity of filesystems", Weight: 2, Keywords: []string{
+		"fsck", "e2fsck", "du", "df", "tune2fs", "xfs_repair", "xfs_fsr", "xfs_db", "debugfs", "dumpe2fs", "inode", "inodes",
+	}},
+	{ID: "104.3", Title: "Control mounting and unmounting of filesystems", Weight: 3, Keywords: []string{
+		"mount", "umount", "fstab", "/etc/fstab", "blkid", "lsblk", "uuid", "automount", "mount unit",
+	}},
+	{ID: "104.5", Title: "Manage file permissions and ownership", Weight: 3, Keywords: []string{
+		"chmod", "chown", "chgrp", "umask", "suid", "sgid", "setuid", "setgid", "sticky bit", "permission",
+		"permissions", "ownership",
+	}},
+	{ID: "104.6", Title: "Create and change hard and symbolic links", Weight: 2, Keywords: []string{
+		"ln", "hard link", "hard links", "hardlink", "symbolic link", "symbolic links", "symlink", "symlinks", "soft link",
+	}},
+	{ID: "104.7", Title: "Find system files and place files in the correct location", Weight: 2, Keywords: []string{
+		"locate", "updatedb", "updatedb.conf", "whereis", "fhs", "filesystem hierarchy standard",
+	}},
+	{ID: "105.1", Title: "Customize and use the shell environment", Weight: 4, Keywords: []string{
+		".bashrc", ".bash_profile", ".bash_login", ".bash_logout", ".profile", "/etc/profile", "/etc/bash.bashrc",
+		"/etc/skel", "alias", "unalias", "source", "environment variable", "environment variables", "login shell",
+	}},
+	{ID: "105.2", Title: "Customize or write simple scripts", Weight: 4, Keywords: []string{
+		"script", "scripts", "shell script", "shebang", "seq", "exit status", "return code", "positional parameter",
+		"positional parameters", "$1", "$0", "$#", "$@",
+	}},
+	{ID: "106.1", Title: "Install and configure X11", Weight: 2, Keywords: []string{
+		"xorg", "x11", "xorg.conf", "/etc/x11/xorg.conf", "xdpyinfo", "xwininfo", "xhost", "xauth", "$display",
+		"wayland", "x server", "x window system",
+	}},
+	{ID: "106.2", Title: "Graphical Desktops", Weight: 1, Keywords: []string{
+		"kde", "gnome", "xfce", "lxde", "desktop environment", "window manager", "xdmcp", "vnc", "spice", "rdp",
+	}},
+	{ID: "106.3", Title: "Accessibility", Weight: 1, Keywords: []string{
+		"accessibility", "screen reader", "orca", "sticky keys", "slow keys", "bounce keys", "mouse keys", "braille",
+		"on-screen keyboard", "high contrast", "magnifier", "emacspeak",
+	}},
+	{ID: "107.1", Title: "Manage user and group accounts and related system files", Weight: 5, Keywords: []string{
+		"useradd", "userdel", "usermod", "groupadd", "groupdel", "groupmod", "passwd", "chage", "getent", "gpasswd",
+		"/etc/passwd", "/etc/shadow", "/etc/group", "/etc/gshadow", "user account", "user accounts",
+	}},
+	{ID: "107.2", Title: "Automate system administration tasks by scheduling jobs", Weight: 4, Keywords: []string{
+		"cron", "crontab", "/etc/crontab", "anacron", "atq", "atrm", "at.allow", "at.deny", "cron.allow", "cron.deny",
+		"systemd timer", "timer", "timers", "systemd-run",
+	}},
+	{ID: "107.3", Title: "Localisation and internationalisation", Weight: 3, Keywords: []string{
+		"locale", "$lang", "lc_all", "$lc_all", "lc_ctype", "iconv", "tzselect", "tzconfig", "timezone", "time zone",
+		"/etc/timezone", "/etc/localtime", "utf-8", "unicode", "ascii", "iso-8859",
+	}},
+	{ID: "108.1", Title: "Maintain system time", Weight: 3, Keywords: []string{
+		"date", "hwclock", "ntp", "ntpd", "ntpdate", "ntpq", "chrony", "chronyc", "timedatectl", "pool.ntp.org",
+		"/etc/ntp.conf", "hardware clock", "system clock", "system time",
+	}},
+	{ID: "108.2", Title: "System logging", Weight: 4, Keywords: []string{
+		"syslog", "rsyslog", "rsyslogd", "/etc/rsyslog.conf", "logger", "logrotate", "journalctl", "journald",
+		"systemd-journald", "systemd-cat", "/var/log", "/var/log/messages", "/var/log/syslog", "klogd", "facility",
+	}},
+	{ID: "108.3", Title: "Mail Transfer Agent (MTA) basics", Weight: 3, Keywords: []string{
+		"mta", "sendmail", "postfix", "exim", "mailq", "newaliases", "/etc/aliases", ".forward", "mail",
+		"mail transfer agent", "smtp",
+	}},
+	{ID: "108.4", Title: "Manage printers and printing", Weight: 2, Keywords: []string{
+		"cups", "cupsd", "/etc/cups", "lpr", "lpq", "lprm", "lp", "lpstat", "lpadmin", "printer", "printers", "print queue",
+	}},
+	{ID: "109.1", Title: "Fundamentals of internet protocols", Weight: 4, Keywords: []string{
+		"tcp", "udp", "icmp", "ipv4", "ipv6", "subnet", "subnets", "netmask", "network mask", "cidr", "/etc/services",
+		"port", "ports", "broadcast", "private address", "private addresses",
+	}},
+	{ID: "109.2", Title: "Persistent network configuration", Weight: 4, Keywords: []string{
+		"/etc/hosts", "/etc/hostname", "hostname", "hostnamectl", "nmcli", "networkmanager", "/etc/nsswitch.conf",
+		"nsswitch.conf", "/etc/network/interfaces", "ifup", "ifdown", "systemd-networkd", "network interface",
+	}},
+	{ID: "109.3", Title: "Basic network troubleshooting", Weight: 4, Keywords: []string{
+		"ping", "ping6", "traceroute", "traceroute6", "tracepath", "netstat", "ss", "ip", "ifconfig", "route",
+		"netcat", "nc", "arp",
+	}},
+	{ID: "109.4", Title: "Configure client side DNS", Weight: 2, Keywords: []string{
+		"dns", "resolv.conf", "/etc/resolv.conf", "dig", "nslookup", "systemd-resolved", "resolvectl", "name server",
+		"nameserver",
+	}},
+	{ID: "110.1", Title: "Perform security administration tasks", Weight: 3, Keywords: []string{
+		"sudo", "sudoers", "/etc/sudoers", "visudo", "su", "nmap", "lsof", "fuser", "ulimit", "open ports",
+	}},
+	{ID: "110.2", Title: "Setup host security", Weight: 3, Keywords: []string{
+		"nologin", "/etc/nologin", "inetd", "xinetd", "/etc/inetd.conf", "/etc/xinetd.d", "tcp wrappers",
+		"hosts.allow", "hosts.deny", "shadow passwords",
+	}},
+	{ID: "110.3", Title: "Securing data with encryption", Weight: 4, Keywords: []string{
+		"ssh", "sshd", "ssh-keygen", "ssh-agent", "ssh-add", "ssh_config", "sshd_config", "known_hosts",
+		"authorized_keys", "id_rsa", "ecdsa", "ed25519", "host key", "gpg", "gnupg", "gpg-agent", "private key",
+		"public key", "port forwarding", "tunnel",
+	}},
+}
@@ -0,0 +1,105 @@
+package objectives
+
+import (
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// Keywords in the question text count more than keywords in the answers,
+// which often list commands of other objectives as wrong options.
+const (
+	textScore   = 2
+	answerScore = 1
+)
+
+// Classify returns the IDs of the objectives of candidates whose keywords
+// question mentions most, or nil if it mentions none. Every keyword counts
+// once per question text and once in the answers.
+func Classify(question *types.Question, candidates []Objective) []string {
+	text := newWords(question.Text)
+	answers := newWords(strings.Join(question.SolutionTexts(), "\n"))
+
+	best := 0
+	var ids []string
+	for _, objective := range candidates {
+		score := 0
+		for _, keyword := range objective.Keywords {
+			if text.contains(keyword) {
+				score += textScore
+			} else if answers.contains(keyword) {
+				score += answerScore
+			}
+		}
+		switch {
+		case score == 0 || score < best:
+		case score > best:
+			best = score
+			ids = []string{objective.ID}
+		default:
+			ids = append(ids, objective.ID)
+		}
+	}
+	return ids
+}
+
+// Assign sets the objectives of every question of certSet that names none
+// to the ones returned by Classify. It returns the number of classified
+// questions. Certifications without known objectives are left unchanged.
+func Assign(certSet *types.CertificationSet) int {
+	candidates := ForCertification(certSet.CertificationID)
+	if candidates == nil {
+		return 0
+	}
+	classified := 0
+	for _, question := range certSet.Questions {
+		if len(question.Objectives) > 0 {
+			continue
+		}
+		if question.Objectives = Classify(question, candidates); question.Objectives != nil {
+			classified++
+		}
+	}
+	return classified
+}
+
+// words is a text split into lower case words. Commands, options, paths and
+// variables like /etc/fstab or $PATH are kept as one word.
+type words struct {
+	set    map[string]bool
+	joined string
+}
+
+func newWords(text string) words {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("._-/$#@", r)
+	})
+	w := words{set: make(map[string]bool, len(fields))}
+	for i, field := range fields {
+		// a word ending a sentence
+		field = strings.TrimRight(field, ".")
+		fields[i] = field
+		if field == "" {
+			continue
+		}
+		w.set[field] = true
+		// /etc/fstab also mentions fstab, package.deb also .deb
+		if strings.Contains(field, "/") {
+			w.set[path.Base(field)] = true
+		}
+		if ext := path.Ext(field); ext != "" && ext != field {
+			w.set[ext] = true
+		}
+	}
+	w.joined = " " + strings.Join(fields, " ") + " "
+	return w
+}
+
+func (w words) contains(keyword string) bool {
+	if strings.Contains(keyword, " ") {
+		return strings.Contains(w.joined, " "+keyword+" ")
+	}
+	return w.set[keyword]
+}
@@ -0,0 +1,87 @@
+package objectives
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+func TestClassify(t *testing.T) {
+	candidates := []Objective{
+		{ID: "1.1", Keywords: []string{"lsmod", "modprobe", "kernel module"}},
+		{ID: "1.2", Keywords: []string{"grep", "regular expression", "fstab"}},
+		{ID: "1.3", Keywords: []string{"mount", ".deb"}},
+	}
+	tests := []struct {
+		name     string
+		question *types.Question
+		want     []string
+	}{
+		{
+			name:     "keyword in the text",
+			question: &types.Question{Text: "Which command lists every loaded kernel module?"},
+			want:     []string{"1.1"},
+		},
+		{
+			name:     "case and punctuation",
+			question: &types.Question{Text: "What does LSMOD show, and why?"},
+			want:     []string{"1.1"},
+		},
+		{
+			name: "text counts more than answers",
+			question: &types.Question{Text: "How do you mount a filesystem?", Answers: []*types.Answer{
+				{Text: "grep"}, {Text: "modprobe", IsCorrect: true},
+			}},
+			want: []string{"1.3"},
+		},
+		{
+			name:     "keywords in the answers",
+			question: &types.Question{Text: "Which command?", Answers: []*types.Answer{{Text: "grep"}, {Text: "lsmod", IsCorrect: true}}},
+			want:     []string{"1.1", "1.2"},
+		},
+		{
+			name:     "file names and extensions",
+			question: &types.Question{Text: "Which file lists /etc/fstab entries for package.deb?"},
+			want:     []string{"1.2", "1.3"},
+		},
+		{
+			name:     "phrases are matched as words",
+			question: &types.Question{Text: "Which kernel modules are loaded?"},
+		},
+		{
+			name:     "no keyword",
+			question: &types.Question{Text: "What is the answer?"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.question, candidates); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssign(t *testing.T) {
+	certSet := &types.CertificationSet{CertificationID: "lpic1-101-500", Questions: map[int]*types.Question{
+		1: {ID: 1, Text: "Which command shows the kernel ring buffer?", Kind: types.QuestionKindFillIn, FillIn: &types.FillIn{Accepted: []string{"dmesg"}}},
+		2: {ID: 2, Text: "Which command lists the loaded modules?", Objectives: []string{"104.1"}},
+		3: {ID: 3, Text: "What is the answer?"},
+	}}
+	if classified := Assign(certSet); classified != 1 {
+		t.Errorf("Assign() = %d, want 1", classified)
+	}
+	for id, want := range map[int][]string{1: {"101.2"}, 2: {"104.1"}, 3: nil} {
+		if got := certSet.Questions[id].Objectives; !reflect.DeepEqual(got, want) {
+			t.Errorf("question %d objectives = %v, want %v", id, got, want)
+		}
+	}
+
+	unknown := &types.CertificationSet{CertificationID: "cka", Questions: map[int]*types.Question{
+		1: {ID: 1, Text: "Which command shows the kernel ring buffer? dmesg"},
+	}}
+	if classified := Assign(unknown); classified != 0 || unknown.Questions[1].Objectives != nil {
+		t.Errorf("Assign() classified %d questions of an unknown exam", classified)
+	}
+}
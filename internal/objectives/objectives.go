@@ -0,0 +1,110 @@
+// Package objectives knows the LPI exam objectives (e.g. 103.7 "Search text
+// files using regular expressions") and assigns them to questions that do not
+// name their objectives, by the commands and keywords they mention.
+package objectives
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// Objective is an exam objective. Weight is the number of exam questions LPI
+// assigns to it.
+type Objective struct {
+	ID     string
+	Title  string
+	Weight int
+	// Keywords are lower case commands, files and terms that point to the
+	// objective. Keywords with spaces are matched as phrases.
+	Keywords []string
+}
+
+// Topic returns the topic of the objective, e.g. 103 for 103.7.
+func (o Objective) Topic() string {
+	topic, _, _ := strings.Cut(o.ID, ".")
+	return topic
+}
+
+// Exam returns the exam the objective belongs to, e.g. 101 for 103.7.
+func (o Objective) Exam() string {
+	for _, exam := range exams {
+		for _, topic := range exam.topics {
+			if topic == o.Topic() {
+				return exam.id
+			}
+		}
+	}
+	return ""
+}
+
+var exams = []struct {
+	id     string
+	topics []string
+}{
+	{id: "101", topics: []string{"101", "102", "103", "104"}},
+	{id: "102", topics: []string{"105", "106", "107", "108", "109", "110"}},
+}
+
+// All returns the objectives of all exams in order.
+func All() []Objective {
+	return catalog
+}
+
+// Lookup returns the objective with id.
+func Lookup(id string) (Objective, bool) {
+	for _, objective := range catalog {
+		if objective.ID == id {
+			return objective, true
+		}
+	}
+	return Objective{}, false
+}
+
+// ForCertification returns the objectives of the exam a certification set
+// covers, e.g. topics 101 to 104 for lpic1-101-500. Certifications that
+// name no known exam get nil, their questions are not classified.
+func ForCertification(certificationID string) []Objective {
+	fields := strings.FieldsFunc(certificationID, func(r rune) bool { return !unicode.IsDigit(r) })
+	for _, field := range fields {
+		var objectives []Objective
+		for _, objective := range catalog {
+			if objective.Exam() == field {
+				objectives = append(objectives, objective)
+			}
+		}
+		if objectives != nil {
+			return objectives
+		}
+	}
+	return nil
+}
+
+// Match reports whether id is selected by filter. A filter selects the
+// objective with that ID or, like 103, all objectives of a topic.
+func Match(id, filter string) bool {
+	return id == filter || strings.HasPrefix(id, filter+".")
+}
+
+// HasObjective reports whether one of the objectives of question matches filter.
+func HasObjective(question *types.Question, filter string) bool {
+	for _, id := range question.Objectives {
+		if Match(id, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// Title returns "<id> <title>" of the objective or topic id, or id if it
+// is not known.
+func Title(id string) string {
+	if objective, ok := Lookup(id); ok {
+		return objective.ID + " " + objective.Title
+	}
+	if title, ok := topics[id]; ok {
+		return "Topic " + id + " " + title
+	}
+	return id
+}
@@ -0,0 +1,108 @@
+package objectives
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+func TestCatalog(t *testing.T) {
+	seen := make(map[string]bool)
+	weights := make(map[string]int)
+	for _, objective := range All() {
+		if seen[objective.ID] {
+			t.Errorf("objective %s is listed twice", objective.ID)
+		}
+		seen[objective.ID] = true
+		if _, ok := topics[objective.Topic()]; !ok {
+			t.Errorf("objective %s has no topic title", objective.ID)
+		}
+		weights[objective.Exam()] += objective.Weight
+		for _, keyword := range objective.Keywords {
+			if keyword != strings.ToLower(strings.TrimSpace(keyword)) {
+				t.Errorf("keyword %q of objective %s is not trimmed lower case", keyword, objective.ID)
+			}
+		}
+	}
+	// both exams have 60 questions
+	for _, exam := range []string{"101", "102"} {
+		if weights[exam] != 60 {
+			t.Errorf("the weights of exam %s add up to %d, want 60", exam, weights[exam])
+		}
+	}
+}
+
+func TestForCertification(t *testing.T) {
+	tests := []struct {
+		certificationID string
+		exam            string
+	}{
+		{"lpic1-101-500", "101"},
+		{"lpic1-102-500", "102"},
+		{"LPIC-1 102", "102"},
+		{"lpic2-201-450", ""},
+		{"cka", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.certificationID, func(t *testing.T) {
+			objectives := ForCertification(tt.certificationID)
+			if tt.exam == "" {
+				if objectives != nil {
+					t.Errorf("ForCertification() = %d objectives, want none", len(objectives))
+				}
+				return
+			}
+			if len(objectives) == 0 {
+				t.Fatal("ForCertification() = no objectives")
+			}
+			for _, objective := range objectives {
+				if objective.Exam() != tt.exam {
+					t.Errorf("objective %s is of exam %s, want %s", objective.ID, objective.Exam(), tt.exam)
+				}
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		id, filter string
+		match      bool
+	}{
+		{"103.7", "103.7", true},
+		{"103.7", "103", true},
+		{"103.7", "103.", false},
+		{"103.7", "10", false},
+		{"103.7", "103.1", false},
+		{"110.1", "110", true},
+		{"110.1", "11", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.id, tt.filter); got != tt.match {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.id, tt.filter, got, tt.match)
+		}
+	}
+
+	question := &types.Question{Objectives: []string{"101.1", "103.7"}}
+	if !HasObjective(question, "103") || HasObjective(question, "104") {
+		t.Errorf("HasObjective() does not match the objectives %v", question.Objectives)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	tests := map[string]string{
+		"101.2": "101.2 Boot the system",
+		"110":   "Topic 110 Security",
+		"999.1": "999.1",
+	}
+	for id, want := range tests {
+		if got := Title(id); got != want {
+			t.Errorf("Title(%q) = %q, want %q", id, got, want)
+		}
+	}
+	if _, ok := Lookup("999.1"); ok {
+		t.Error("Lookup found an unknown objective")
+	}
+}
@@ -0,0 +1,303 @@
+// Package printable renders questions as a mock exam with an answer key, as
+// a self-contained HTML page or as Markdown, e.g. for study groups.
+package printable
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+type Format string
+
+const (
+	FormatHTML     Format = "html"
+	FormatMarkdown Format = "markdown"
+)
+
+// ParseFormat returns the format called name. Besides the format names the
+// file extensions html, htm, md and markdown are accepted.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "html", "htm":
+		return FormatHTML, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown format %q, expected html or markdown", name)
+}
+
+// Sheet is a printable exam. The questions are numbered in their order.
+type Sheet struct {
+	Title string
+	// Subtitle describes the selection, e.g. the testset and the seed.
+	Subtitle  string
+	Questions []*types.Question
+}
+
+type sheetOption struct {
+	Letter  string
+	Text    string
+	Correct bool
+}
+
+type sheetQuestion struct {
+	Number  int
+	ID      int
+	Text    string
+	Choose  int
+	Options []sheetOption
+	FillIn  bool
+	// Instruction explains how ordering and matching questions are answered.
+	Instruction string
+	// Lefts are the items of a matching question, matched with Options.
+	Lefts       []string
+	Correct     string
+	Explanation string
+}
+
+type sheetData struct {
+	Title     string
+	Subtitle  string
+	Questions []sheetQuestion
+	WithExam  bool
+	WithKey   bool
+}
+
+func newSheetData(sheet Sheet, withExam, withKey bool) sheetData {
+	data := sheetData{Title: sheet.Title, Subtitle: sheet.Subtitle, WithExam: withExam, WithKey: withKey}
+	for i, question := range sheet.Questions {
+		q := sheetQuestion{Number: i + 1, ID: question.ID, Text: strings.TrimSpace(question.Text), Explanation: strings.TrimSpace(question.Explanation)}
+		if question.IsFillIn() {
+			q.FillIn = true
+			q.Correct = question.FillIn.Solution()
+			if len(question.FillIn.Accepted) > 0 {
+				q.Correct = strings.Join(question.FillIn.Accepted, " / ")
+			}
+			data.Questions = append(data.Questions, q)
+			continue
+		}
+		if question.IsArranged() {
+			arrangedQuestion(&q, question)
+			data.Questions = append(data.Questions, q)
+			continue
+		}
+		var correct []string
+		for j, answer := range question.Answers {
+			letter := optionLetter(j)
+			q.Options = append(q.Options, sheetOption{Letter: letter, Text: strings.TrimSpace(answer.Text), Correct: answer.IsCorrect})
+			if answer.IsCorrect {
+				correct = append(correct, letter)
+			}
+		}
+		if len(correct) > 1 {
+			q.Choose = len(correct)
+		}
+		q.Correct = strings.Join(correct, ", ")
+		data.Questions = append(data.Questions, q)
+	}
+	return data
+}
+
+// arrangedQuestion sets the options and the key of an ordering or matching
+// question: the shuffled items to be put in order, or the options to be
+// matched with the Lefts.
+func arrangedQuestion(q *sheetQuestion, question *types.Question) {
+	var correct []string
+	if question.Kind == types.QuestionKindMatching {
+		q.Instruction = "Match each item with a letter."
+		options := question.MatchOptions()
+		for j, option := range options {
+			q.Options = append(q.Options, sheetOption{Letter: optionLetter(j), Text: strings.TrimSpace(option)})
+		}
+		for i, pair := range question.Matching {
+			q.Lefts = append(q.Lefts, strings.TrimSpace(pair.Left))
+			correct = append(correct, fmt.Sprintf("%d-%s", i+1, optionLetter(slices.Index(options, pair.Right))))
+		}
+		q.Correct = strings.Join(correct, ", ")
+		return
+	}
+	q.Instruction = "Put in order."
+	arrangement := question.InitialArrangement()
+	letters := make([]string, len(arrangement))
+	for j, item := range arrangement {
+		q.Options = append(q.Options, sheetOption{Letter: optionLetter(j), Text: strings.TrimSpace(question.Ordering[item])})
+		letters[item] = optionLetter(j)
+	}
+	q.Correct = strings.Join(letters, ", ")
+}
+
+// optionLetter returns A to Z, then AA, AB and so on.
+func optionLetter(i int) string {
+	if i < 26 {
+		return string(rune('A' + i))
+	}
+	return optionLetter(i/26-1) + optionLetter(i%26)
+}
+
+// WriteExam renders the questions of sheet. If withKey is set the answer key
+// follows on a new page.
+func WriteExam(w io.Writer, format Format, sheet Sheet, withKey bool) error {
+	return write(w, format, newSheetData(sheet, true, withKey))
+}
+
+// WriteKey renders only the answer key of sheet.
+func WriteKey(w io.Writer, format Format, sheet Sheet) error {
+	data := newSheetData(sheet, false, true)
+	data.Title += " – Answer key"
+	return write(w, format, data)
+}
+
+func write(w io.Writer, format Format, data sheetData) error {
+	switch format {
+	case FormatHTML:
+		return htmlTemplate.Execute(w, data)
+	case FormatMarkdown:
+		_, err := io.WriteString(w, markdown(data))
+		return err
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+var htmlTemplate = template.Must(template.New("sheet").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: Arial, Helvetica, sans-serif; font-size: 11pt; line-height: 1.4; max-width: 48em; margin: 2em auto; padding: 0 1em; color: #000; }
+h1 { font-size: 18pt; margin-bottom: 0.2em; }
+.subtitle { color: #555; margin-top: 0; }
+.question { break-inside: avoid; page-break-inside: avoid; margin: 1.2em 0; }
+.text, .explanation { white-space: pre-wrap; }
+.choose { font-style: italic; }
+ol.options { list-style: none; padding-left: 1.5em; }
+ol.options li { margin: 0.2em 0; white-space: pre-wrap; }
+.blank { padding-left: 1.5em; }
+.letter { font-weight: bold; display: inline-block; min-width: 2em; }
+.key { break-before: page; page-break-before: always; }
+.key dt { font-weight: bold; margin-top: 0.8em; }
+.key dd { margin-left: 2em; }
+.id { color: #777; font-weight: normal; font-size: 9pt; }
+</style>
+</head>
+<body>
+{{- if .WithExam}}
+<h1>{{.Title}}</h1>
+{{- if .Subtitle}}
+<p class="subtitle">{{.Subtitle}}</p>
+{{- end}}
+{{- range .Questions}}
+<div class="question">
+<p><strong>{{.Number}}.</strong> <span class="text">{{.Text}}</span>{{if .Choose}} <span class="choose">(Choose {{.Choose}}.)</span>{{end}}{{if .Instruction}} <span class="choose">({{.Instruction}})</span>{{end}}</p>
+{{- if .Lefts}}
+<ol class="lefts">
+{{- range .Lefts}}
+<li>{{.}} <span class="blank">____</span></li>
+{{- end}}
+</ol>
+{{- end}}
+{{- if .FillIn}}
+<p class="blank">Answer: ______________________________</p>
+{{- else}}
+<ol class="options">
+{{- range .Options}}
+<li><span class="letter">{{.Letter}}.</span>{{.Text}}</li>
+{{- end}}
+</ol>
+{{- end}}
+</div>
+{{- end}}
+{{- end}}
+{{- if .WithKey}}
+<div class="key">
+<h1>{{if .WithExam}}Answer key{{else}}{{.Title}}{{end}}</h1>
+{{- if and .Subtitle (not .WithExam)}}
+<p class="subtitle">{{.Subtitle}}</p>
+{{- end}}
+<dl>
+{{- range .Questions}}
+<dt>{{.Number}}. {{.Correct}} <span class="id">(question {{.ID}})</span></dt>
+{{- if .Explanation}}
+<dd class="explanation">{{.Explanation}}</dd>
+{{- end}}
+{{- end}}
+</dl>
+</div>
+{{- end}}
+</body>
+</html>
+`))
+
+func markdown(data sheetData) string {
+	var b strings.Builder
+	if data.WithExam {
+		fmt.Fprintf(&b, "# %s\n\n", escapeMarkdown(data.Title))
+		if data.Subtitle != "" {
+			fmt.Fprintf(&b, "_%s_\n\n", escapeMarkdown(data.Subtitle))
+		}
+		for _, q := range data.Questions {
+			fmt.Fprintf(&b, "**%d.** %s", q.Number, markdownText(q.Text))
+			if q.Choose > 0 {
+				fmt.Fprintf(&b, " _(Choose %d.)_", q.Choose)
+			}
+			if q.Instruction != "" {
+				fmt.Fprintf(&b, " _(%s)_", q.Instruction)
+			}
+			b.WriteString("\n\n")
+			for i, left := range q.Lefts {
+				fmt.Fprintf(&b, "%d. %s %s\n", i+1, markdownText(left), strings.Repeat(`\_`, 4))
+			}
+			if len(q.Lefts) > 0 {
+				b.WriteString("\n")
+			}
+			if q.FillIn {
+				fmt.Fprintf(&b, "Answer: %s\n", strings.Repeat(`\_`, 30))
+			}
+			for _, option := range q.Options {
+				fmt.Fprintf(&b, "- **%s.** %s\n", option.Letter, markdownText(option.Text))
+			}
+			b.WriteString("\n")
+		}
+	}
+	if data.WithKey {
+		if data.WithExam {
+			b.WriteString("---\n\n## Answer key\n\n")
+		} else {
+			fmt.Fprintf(&b, "# %s\n\n", escapeMarkdown(data.Title))
+			if data.Subtitle != "" {
+				fmt.Fprintf(&b, "_%s_\n\n", escapeMarkdown(data.Subtitle))
+			}
+		}
+		for _, q := range data.Questions {
+			fmt.Fprintf(&b, "**%d. %s** (question %d)", q.Number, escapeMarkdown(q.Correct), q.ID)
+			if q.Explanation != "" {
+				fmt.Fprintf(&b, "  \n%s", markdownText(q.Explanation))
+			}
+			b.WriteString("\n\n")
+		}
+	}
+	return b.String()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`<`, `\<`, `>`, `\>`, `#`, `\#`, `|`, `\|`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// markdownText escapes s and keeps its line breaks.
+func markdownText(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = escapeMarkdown(strings.TrimRight(line, " \t"))
+	}
+	return strings.Join(lines, "  \n")
+}
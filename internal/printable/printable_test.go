@@ -0,0 +1,166 @@
+package printable
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+	}{
+		{"html", FormatHTML},
+		{".htm", FormatHTML},
+		{"HTML", FormatHTML},
+		{"markdown", FormatMarkdown},
+		{".md", FormatMarkdown},
+		{"pdf", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		format, err := ParseFormat(tt.name)
+		if format != tt.format || (err == nil) != (tt.format != "") {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", tt.name, format, err, tt.format)
+		}
+	}
+}
+
+func TestOptionLetter(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		if got := optionLetter(i); got != want {
+			t.Errorf("optionLetter(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func testSheet() Sheet {
+	return Sheet{
+		Title:    "LPIC-1 101 – Mock exam",
+		Subtitle: "Testset basics",
+		Questions: []*types.Question{
+			{ID: 11, Text: "Which commands list *files*?\nChoose wisely.", Answers: []*types.Answer{
+				{Text: "ls", IsCorrect: true},
+				{Text: "cd <dir>"},
+				{Text: "dir", IsCorrect: true},
+			}, Explanation: "dir is ls -C -b."},
+			{ID: 12, Text: "Which command shows the kernel release?", Kind: types.QuestionKindFillIn,
+				FillIn: &types.FillIn{Accepted: []string{"uname -r", "uname --kernel-release"}}},
+			{ID: 13, Text: "Match the runlevels.", Kind: types.QuestionKindMatching, Matching: []types.Pair{
+				{Left: "runlevel 3", Right: "multi-user.target"},
+				{Left: "runlevel 5", Right: "graphical.target"},
+			}},
+		},
+	}
+}
+
+func TestWriteExamMarkdown(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteExam(&b, FormatMarkdown, testSheet(), true); err != nil {
+		t.Fatalf("WriteExam: %v", err)
+	}
+	want := `# LPIC-1 101 – Mock exam
+
+_Testset basics_
+
+**1.** Which commands list \*files\*?  
+Choose wisely. _(Choose 2.)_
+
+- **A.** ls
+- **B.** cd \<dir\>
+- **C.** dir
+
+**2.** Which command shows the kernel release?
+
+Answer: ` + strings.Repeat(`\_`, 30) + `
+
+**3.** Match the runlevels. _(Match each item with a letter.)_
+
+1. runlevel 3 \_\_\_\_
+2. runlevel 5 \_\_\_\_
+
+- **A.** graphical.target
+- **B.** multi-user.target
+
+---
+
+## Answer key
+
+**1. A, C** (question 11)  
+dir is ls -C -b.
+
+**2. uname -r / uname --kernel-release** (question 12)
+
+**3. 1-B, 2-A** (question 13)
+
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteExam() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteKey(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteKey(&b, FormatMarkdown, testSheet()); err != nil {
+		t.Fatalf("WriteKey: %v", err)
+	}
+	got := b.String()
+	if !strings.HasPrefix(got, "# LPIC-1 101 – Mock exam – Answer key\n\n_Testset basics_\n\n**1. A, C**") {
+		t.Errorf("WriteKey() =\n%s\nwant the title, the subtitle and then the key", got)
+	}
+	if strings.Contains(got, "Choose") || strings.Contains(got, "## Answer key") {
+		t.Errorf("WriteKey() =\n%s\nwant no questions", got)
+	}
+}
+
+func TestWriteExamHTML(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteExam(&b, FormatHTML, testSheet(), false); err != nil {
+		t.Fatalf("WriteExam: %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"<title>LPIC-1 101 – Mock exam</title>",
+		`<span class="choose">(Choose 2.)</span>`,
+		`<li><span class="letter">B.</span>cd &lt;dir&gt;</li>`,
+		`<p class="blank">Answer: `,
+		`<li>runlevel 5 <span class="blank">____</span></li>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteExam() has no %q", want)
+		}
+	}
+	if strings.Contains(got, `class="key"`) {
+		t.Error("WriteExam() without key has an answer key")
+	}
+}
+
+func TestOrderingKey(t *testing.T) {
+	question := &types.Question{ID: 1, Kind: types.QuestionKindOrdering, Ordering: []string{"BIOS", "boot loader", "kernel", "init"}}
+	data := newSheetData(Sheet{Questions: []*types.Question{question}}, true, true)
+	q := data.Questions[0]
+	if q.Instruction != "Put in order." || len(q.Options) != len(question.Ordering) {
+		t.Fatalf("ordering question = %+v", q)
+	}
+	// reading the options in the order of the key gives the items in order
+	textByLetter := make(map[string]string, len(q.Options))
+	for _, option := range q.Options {
+		textByLetter[option.Letter] = option.Text
+	}
+	var ordered []string
+	for _, letter := range strings.Split(q.Correct, ", ") {
+		ordered = append(ordered, textByLetter[letter])
+	}
+	if strings.Join(ordered, ",") != strings.Join(question.Ordering, ",") {
+		t.Errorf("key %q orders the items as %v", q.Correct, ordered)
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := WriteExam(&bytes.Buffer{}, "pdf", testSheet(), true); err == nil {
+		t.Error("WriteExam accepted an unknown format")
+	}
+}
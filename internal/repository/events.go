@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+func newEventID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate event id: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// eventKey orders events by time in key-sorted stores.
+func eventKey(event types.AnswerEvent) []byte {
+	return []byte(fmt.Sprintf("%020d-%s", event.Timestamp.UnixNano(), event.ID))
+}
+
+func sortEvents(events []types.AnswerEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+}
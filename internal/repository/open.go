@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	BackendNuts   = "nuts"
+	BackendSQLite = "sqlite"
+	BackendMongo  = "mongo"
+	BackendMemory = "memory"
+)
+
+// Config selects and configures the progress backend opened by Open.
+type Config struct {
+	// Backend is one of BackendNuts (default), BackendSQLite, BackendMongo or
+	// BackendMemory, which keeps nothing after the process exits.
+	Backend string
+	// Dir is the state directory of the file based backends, see resolveStatePath.
+	Dir       string
+	Mongo     MongoConfig
+	Namespace Namespace
+	// MigrateLegacy lets the nuts backend move the legacy, un-namespaced
+	// progress into the opened namespace, see migrateLegacy. Only the trainer
+	// sets it: every other command leaves the legacy progress alone, so it
+	// does not end up in whichever certification a command happens to open.
+	MigrateLegacy bool
+}
+
+// Open opens the progress backend described by cfg.
+func Open(ctx context.Context, cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case "", BackendNuts:
+		return OpenNutsQuestionRepository(cfg.Dir, cfg.Namespace, cfg.MigrateLegacy), nil
+	case BackendSQLite:
+		path, err := resolveStatePath(cfg.Dir, sqliteFileName)
+		if err != nil {
+			return nil, err
+		}
+		return NewSQLiteQuestionRepository(path, cfg.Namespace)
+	case BackendMongo:
+		return NewMongoQuestionRepository(ctx, cfg.Mongo, cfg.Namespace)
+	case BackendMemory:
+		return NewMemoryQuestionRepository(cfg.Namespace), nil
+	default:
+		return nil, fmt.Errorf("unknown state backend %q", cfg.Backend)
+	}
+}
+
+// Opener opens one storage scoped to different namespaces. Backends that lock
+// their storage can only be open once at a time, so close a Store before
+// opening the next one.
+type Opener func(ns Namespace) (Store, error)
+
+// Opener returns an Opener for the backend described by cfg. cfg.Namespace is
+// replaced by the namespace passed to the Opener.
+func (cfg Config) Opener(ctx context.Context) Opener {
+	return func(ns Namespace) (Store, error) {
+		cfg.Namespace = ns
+		return Open(ctx, cfg)
+	}
+}
+
+// resolveStatePath returns the location of the state entry name inside baseDir.
+// If baseDir is empty, $HOME is used and a leading ~ is expanded. If baseDir
+// already ends with name it is used directly; otherwise name is appended.
+func resolveStatePath(baseDir string, name string) (string, error) {
+	if strings.TrimSpace(baseDir) == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		return filepath.Join(homeDir, name), nil
+	}
+
+	// Expand leading ~
+	if strings.HasPrefix(baseDir, "~") {
+		homeDir, err := os.UserHomeDir()
+		if err == nil {
+			baseDir = filepath.Join(homeDir, strings.TrimPrefix(baseDir, "~"))
+		}
+	}
+	if filepath.Base(baseDir) == name {
+		return baseDir, nil
+	}
+	return filepath.Join(baseDir, name), nil
+}
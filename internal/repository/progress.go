@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"sort"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// summarizeProgress computes a ProgressSummary for backends without query support.
+func summarizeProgress(states []types.QuestionStateDB) *types.ProgressSummary {
+	summary := &types.ProgressSummary{Questions: len(states)}
+	for _, state := range states {
+		switch state.AnsweredState {
+		case types.AnsweredTrue:
+			summary.Correct++
+		case types.AnsweredFalse:
+			summary.Incorrect++
+		}
+		if state.Important {
+			summary.Important++
+		}
+		summary.Attempts += state.Attempts
+	}
+	return summary
+}
+
+// dailyProgress groups answer attempts by UTC day for backends without query support.
+func dailyProgress(events []types.AnswerEvent) []types.DailyProgress {
+	byDay := make(map[string]*types.DailyProgress)
+	for _, event := range events {
+		if event.Kind == types.AnswerEventReset {
+			continue
+		}
+		day := event.Timestamp.UTC().Format("2006-01-02")
+		progress, ok := byDay[day]
+		if !ok {
+			progress = &types.DailyProgress{Day: day}
+			byDay[day] = progress
+		}
+		progress.Attempts++
+		switch event.Result {
+		case types.AnsweredTrue:
+			progress.Correct++
+		case types.AnsweredFalse:
+			progress.Incorrect++
+		}
+		progress.TimeSpent += event.TimeSpent
+	}
+
+	days := make([]types.DailyProgress, 0, len(byDay))
+	for _, progress := range byDay {
+		days = append(days, *progress)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Day < days[j].Day })
+	return days
+}
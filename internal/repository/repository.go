@@ -2,13 +2,121 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"strings"
 
 	"github.com/SqiSch/lpic-cli/internal/types"
 )
 
+// ErrNotFound is returned when a requested entry does not exist.
+var ErrNotFound = errors.New("not found")
+
+// DefaultProfile is used when a Namespace has no explicit profile.
+const DefaultProfile = "default"
+
+// Namespace scopes stored progress to one certification and user profile,
+// so question IDs shared between certifications do not leak progress.
+// The zero Namespace addresses the legacy, un-namespaced data.
+type Namespace struct {
+	CertificationID string
+	Profile         string
+}
+
+func (ns Namespace) IsZero() bool {
+	return ns.CertificationID == "" && ns.Profile == ""
+}
+
+// GetProfile returns the profile, falling back to DefaultProfile.
+func (ns Namespace) GetProfile() string {
+	if strings.TrimSpace(ns.Profile) == "" {
+		return DefaultProfile
+	}
+	return ns.Profile
+}
+
+// String returns "<profile>/<certificationId>", or "" for the zero Namespace.
+func (ns Namespace) String() string {
+	if ns.IsZero() {
+		return ""
+	}
+	return ns.GetProfile() + "/" + ns.CertificationID
+}
+
+func sortedNamespaces(seen map[Namespace]bool) []Namespace {
+	namespaces := make([]Namespace, 0, len(seen))
+	for ns := range seen {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].String() < namespaces[j].String()
+	})
+	return namespaces
+}
+
 type QuestionRepository interface {
 	UpsertQuestion(ctx context.Context, question *types.Question) error
 	GetQuestion(ctx context.Context, id string) (*types.Question, error)
 	DeleteQuestion(ctx context.Context, id string) error
 	GetAnsweredQuestions() ([]types.QuestionStateDB, error)
 }
+
+// ExamRepository persists finished exam attempts for later review.
+type ExamRepository interface {
+	SaveExamResult(ctx context.Context, result *types.ExamResult) error
+	GetExamResults(ctx context.Context) ([]types.ExamResult, error)
+}
+
+// HistoryRepository keeps every answer attempt as an append-only event log.
+// Recording an event also updates the derived QuestionStateDB of the question.
+type HistoryRepository interface {
+	RecordAnswer(ctx context.Context, event types.AnswerEvent) error
+	GetAnswerHistory(ctx context.Context) ([]types.AnswerEvent, error)
+}
+
+// ProgressRepository answers aggregate questions about the stored progress.
+type ProgressRepository interface {
+	GetProgressSummary(ctx context.Context) (*types.ProgressSummary, error)
+	GetDailyProgress(ctx context.Context) ([]types.DailyProgress, error)
+}
+
+// SessionRepository persists practice sessions so they can be resumed.
+type SessionRepository interface {
+	// SaveSession stores session, replacing a stored session with the same ID.
+	SaveSession(ctx context.Context, session types.SessionState) error
+	// GetLatestSession returns the most recently updated session or ErrNotFound.
+	GetLatestSession(ctx context.Context) (*types.SessionState, error)
+}
+
+// TransferRepository gives raw access to the stored progress, so it can be
+// copied between state directories without replaying the answer history.
+type TransferRepository interface {
+	// GetNamespaces lists every namespace with stored progress. The legacy,
+	// un-namespaced progress is not included.
+	GetNamespaces(ctx context.Context) ([]Namespace, error)
+	// PutQuestionState stores state as is, replacing the stored state.
+	PutQuestionState(ctx context.Context, state types.QuestionStateDB) error
+	// AppendAnswerEvents appends events to the history without applying them
+	// to the question states. Events whose ID is already stored in the
+	// namespace are skipped.
+	AppendAnswerEvents(ctx context.Context, events []types.AnswerEvent) error
+}
+
+// LegacyRepository is implemented by backends that may hold progress stored
+// by versions without namespaces.
+type LegacyRepository interface {
+	// HasLegacyProgress reports whether the legacy, un-namespaced progress
+	// holds entries that were never migrated into a namespace.
+	HasLegacyProgress(ctx context.Context) (bool, error)
+}
+
+// Store is a complete progress backend as used by the client.
+type Store interface {
+	QuestionRepository
+	ExamRepository
+	HistoryRepository
+	ProgressRepository
+	SessionRepository
+	TransferRepository
+	Close() error
+}
@@ -0,0 +1,395 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+var _ Store = (*MemoryQuestionRepository)(nil)
+
+// ErrClosed is returned by the in-memory backend after Close.
+var ErrClosed = errors.New("repository closed")
+
+// memoryData is the storage shared by all namespaces of an in-memory backend.
+type memoryData struct {
+	mu       sync.RWMutex
+	states   map[string]map[int]types.QuestionStateDB
+	events   map[string][]types.AnswerEvent
+	exams    map[string]map[string]types.ExamResult
+	sessions map[string]map[string]types.SessionState
+
+	// namespaces maps the keys of the maps above back to their Namespace
+	namespaces map[string]Namespace
+}
+
+// MemoryQuestionRepository keeps progress in memory only. It is meant for tests
+// and for running the client without a state directory.
+type MemoryQuestionRepository struct {
+	data   *memoryData
+	ns     Namespace
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewMemoryQuestionRepository returns an empty in-memory repository scoped to ns.
+func NewMemoryQuestionRepository(ns Namespace) *MemoryQuestionRepository {
+	return &MemoryQuestionRepository{
+		data: &memoryData{
+			states:     make(map[string]map[int]types.QuestionStateDB),
+			events:     make(map[string][]types.AnswerEvent),
+			exams:      make(map[string]map[string]types.ExamResult),
+			sessions:   make(map[string]map[string]types.SessionState),
+			namespaces: make(map[string]Namespace),
+		},
+		ns: ns,
+	}
+}
+
+// WithNamespace returns a repository scoped to ns that shares the stored data
+// with m, like opening the same state directory again. Closing one of them
+// does not close the other.
+func (m *MemoryQuestionRepository) WithNamespace(ns Namespace) *MemoryQuestionRepository {
+	return &MemoryQuestionRepository{data: m.data, ns: ns}
+}
+
+func (m *MemoryQuestionRepository) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
+
+func (m *MemoryQuestionRepository) checkOpen() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+// cloneState returns a copy of state that does not share the marked answers
+// and tags, so callers can not modify the stored data.
+func cloneState(state types.QuestionStateDB) types.QuestionStateDB {
+	state.MarkedAnswers = append([]string{}, state.MarkedAnswers...)
+	state.Tags = slices.Clone(state.Tags)
+	return state
+}
+
+// cloneExamResult deep copies a result the same way the other backends do by
+// serializing it.
+func cloneExamResult(result *types.ExamResult) (types.ExamResult, error) {
+	var clone types.ExamResult
+	value, err := json.Marshal(result)
+	if err != nil {
+		return clone, fmt.Errorf("failed to marshal exam result: %w", err)
+	}
+	if err := json.Unmarshal(value, &clone); err != nil {
+		return clone, fmt.Errorf("failed to unmarshal exam result: %w", err)
+	}
+	return clone, nil
+}
+
+// touch records that the namespace has stored progress. The caller must hold
+// the write lock.
+func (m *MemoryQuestionRepository) touch() string {
+	key := m.ns.String()
+	if !m.ns.IsZero() {
+		m.data.namespaces[key] = Namespace{CertificationID: m.ns.CertificationID, Profile: m.ns.GetProfile()}
+	}
+	return key
+}
+
+func (m *MemoryQuestionRepository) questionStates() map[int]types.QuestionStateDB {
+	key := m.touch()
+	states, ok := m.data.states[key]
+	if !ok {
+		states = make(map[int]types.QuestionStateDB)
+		m.data.states[key] = states
+	}
+	return states
+}
+
+// UpsertQuestion implements QuestionRepository.
+func (m *MemoryQuestionRepository) UpsertQuestion(ctx context.Context, question *types.Question) error {
+	if err := m.checkOpen(); err != nil {
+		return err
+	}
+	m.data.mu.Lock()
+	defer m.data.mu.Unlock()
+
+	states := m.questionStates()
+	state, ok := states[question.ID]
+	if !ok {
+		state = types.QuestionStateDB{QuestionID: question.ID}
+	}
+	state.MarkedAnswers = make([]string, 0)
+	for _, answer := range question.GetAnsweredOptions() {
+		state.MarkedAnswers = append(state.MarkedAnswers, answer.AnswerID)
+	}
+	state.AnsweredState = question.AnsweredState
+	state.Important = question.GetIsImportant()
+	state.Tags = question.GetTags()
+	state.Note = question.GetNote()
+	state.TypedAnswer = question.GetTypedAnswer()
+	states[question.ID] = state
+	return nil
+}
+
+// GetQuestion implements QuestionRepository. The returned question only carries
+// the stored state: its ID, answered state, important flag, tags, note and
+// marked or typed answer.
+func (m *MemoryQuestionRepository) GetQuestion(ctx context.Context, id string) (*types.Question, error) {
+	if err := m.checkOpen(); err != nil {
+		return nil, err
+	}
+	questionID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question id %q: %w", id, err)
+	}
+	m.data.mu.RLock()
+	defer m.data.mu.RUnlock()
+
+	state, ok := m.data.states[m.ns.String()][questionID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return questionFromState(cloneState(state)), nil
+}
+
+// DeleteQuestion implements QuestionRepository. The answer history is kept.
+func (m *MemoryQuestionRepository) DeleteQuestion(ctx context.Context, id string) error {
+	if err := m.checkOpen(); err != nil {
+		return err
+	}
+	questionID, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid question id %q: %w", id, err)
+	}
+	m.data.mu.Lock()
+	defer m.data.mu.Unlock()
+
+	delete(m.data.states[m.ns.String()], questionID)
+	return nil
+}
+
+func (m *MemoryQuestionRepository) GetAnsweredQuestions() ([]types.QuestionStateDB, error) {
+	if err := m.checkOpen(); err != nil {
+		return nil, err
+	}
+	m.data.mu.RLock()
+	defer m.data.mu.RUnlock()
+
+	var questionStates []types.QuestionStateDB
+	for _, state := range m.data.states[m.ns.String()] {
+		questionStates = append(questionStates, cloneState(state))
+	}
+	sort.Slice(questionStates, func(i, j int) bool {
+		return questionStates[i].QuestionID < questionStates[j].QuestionID
+	})
+	return questionStates, nil
+}
+
+// SaveExamResult implements ExamRepository.
+func (m *MemoryQuestionRepository) SaveExamResult(ctx context.Context, result *types.ExamResult) error {
+	if err := m.checkOpen(); err != nil {
+		return err
+	}
+	clone, err := cloneExamResult(result)
+	if err != nil {
+		return err
+	}
+	m.data.mu.Lock()
+	defer m.data.mu.Unlock()
+
+	key := m.touch()
+	if m.data.exams[key] == nil {
+		m.data.exams[key] = make(map[string]types.ExamResult)
+	}
+	m.data.exams[key][result.ID] = clone
+	return nil
+}
+
+// GetExamResults implements ExamRepository. Results are ordered by start time.
+func (m *MemoryQuestionRepository) GetExamResults(ctx context.Context) ([]types.ExamResult, error) {
+	if err := m.checkOpen(); err != nil {
+		return nil, err
+	}
+	m.data.mu.RLock()
+	defer m.data.mu.RUnlock()
+
+	var results []types.ExamResult
+	for _, result := range m.data.exams[m.ns.String()] {
+		clone, err := cloneExamResult(&result)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, clone)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].StartedAt.Before(results[j].StartedAt)
+	})
+	return results, nil
+}
+
+// RecordAnswer implements HistoryRepository.
+func (m *MemoryQuestionRepository) RecordAnswer(ctx context.Context, event types.AnswerEvent) error {
+	if err := m.checkOpen(); err != nil {
+		return err
+	}
+	if event.ID == "" {
+		event.ID = newEventID()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	event.AnswerIDs = append([]string{}, event.AnswerIDs...)
+
+	m.data.mu.Lock()
+	defer m.data.mu.Unlock()
+
+	key := m.touch()
+	m.data.events[key] = append(m.data.events[key], event)
+
+	states := m.questionStates()
+	state := states[event.QuestionID]
+	state.Apply(event)
+	states[event.QuestionID] = state
+	return nil
+}
+
+// GetAnswerHistory implements HistoryRepository. Events are ordered by time.
+func (m *MemoryQuestionRepository) GetAnswerHistory(ctx context.Context) ([]types.AnswerEvent, error) {
+	if err := m.checkOpen(); err != nil {
+		return nil, err
+	}
+	m.data.mu.RLock()
+	defer m.data.mu.RUnlock()
+
+	var events []types.AnswerEvent
+	for _, event := range m.data.events[m.ns.String()] {
+		event.AnswerIDs = append([]string{}, event.AnswerIDs...)
+		events = append(events, event)
+	}
+	sortEvents(events)
+	return events, nil
+}
+
+// GetProgressSummary implements ProgressRepository.
+func (m *MemoryQuestionRepository) GetProgressSummary(ctx context.Context) (*types.ProgressSummary, error) {
+	states, err := m.GetAnsweredQuestions()
+	if err != nil {
+		return nil, err
+	}
+	return summarizeProgress(states), nil
+}
+
+// GetDailyProgress implements ProgressRepository.
+func (m *MemoryQuestionRepository) GetDailyProgress(ctx context.Context) ([]types.DailyProgress, error) {
+	events, err := m.GetAnswerHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dailyProgress(events), nil
+}
+
+// SaveSession implements SessionRepository.
+func (m *MemoryQuestionRepository) SaveSession(ctx context.Context, session types.SessionState) error {
+	if err := m.checkOpen(); err != nil {
+		return err
+	}
+	m.data.mu.Lock()
+	defer m.data.mu.Unlock()
+
+	key := m.ns.String()
+	if m.data.sessions[key] == nil {
+		m.data.sessions[key] = make(map[string]types.SessionState)
+	}
+	session.QuestionIDs = append([]int{}, session.QuestionIDs...)
+	m.data.sessions[key][session.ID] = session
+	return nil
+}
+
+// GetLatestSession implements SessionRepository.
+func (m *MemoryQuestionRepository) GetLatestSession(ctx context.Context) (*types.SessionState, error) {
+	if err := m.checkOpen(); err != nil {
+		return nil, err
+	}
+	m.data.mu.RLock()
+	defer m.data.mu.RUnlock()
+
+	var latest *types.SessionState
+	for _, session := range m.data.sessions[m.ns.String()] {
+		if latest == nil || session.UpdatedAt.After(latest.UpdatedAt) {
+			session.QuestionIDs = append([]int{}, session.QuestionIDs...)
+			latest = &session
+		}
+	}
+	if latest == nil {
+		return nil, ErrNotFound
+	}
+	return latest, nil
+}
+
+// GetNamespaces implements TransferRepository.
+func (m *MemoryQuestionRepository) GetNamespaces(ctx context.Context) ([]Namespace, error) {
+	if err := m.checkOpen(); err != nil {
+		return nil, err
+	}
+	m.data.mu.RLock()
+	defer m.data.mu.RUnlock()
+
+	seen := make(map[Namespace]bool)
+	for _, ns := range m.data.namespaces {
+		seen[ns] = true
+	}
+	return sortedNamespaces(seen), nil
+}
+
+// PutQuestionState implements TransferRepository.
+func (m *MemoryQuestionRepository) PutQuestionState(ctx context.Context, state types.QuestionStateDB) error {
+	if err := m.checkOpen(); err != nil {
+		return err
+	}
+	m.data.mu.Lock()
+	defer m.data.mu.Unlock()
+
+	m.questionStates()[state.QuestionID] = cloneState(state)
+	return nil
+}
+
+// AppendAnswerEvents implements TransferRepository.
+func (m *MemoryQuestionRepository) AppendAnswerEvents(ctx context.Context, events []types.AnswerEvent) error {
+	if err := m.checkOpen(); err != nil {
+		return err
+	}
+	m.data.mu.Lock()
+	defer m.data.mu.Unlock()
+
+	key := m.touch()
+	stored := make(map[string]bool, len(m.data.events[key]))
+	for _, event := range m.data.events[key] {
+		stored[event.ID] = true
+	}
+	for _, event := range events {
+		if event.ID == "" {
+			event.ID = newEventID()
+		}
+		if stored[event.ID] {
+			continue
+		}
+		stored[event.ID] = true
+		event.AnswerIDs = append([]string{}, event.AnswerIDs...)
+		m.data.events[key] = append(m.data.events[key], event)
+	}
+	return nil
+}
@@ -0,0 +1,582 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/SqiSch/lpic-cli/internal/database"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+var _ Store = (*MongoQuestionRepository)(nil)
+
+const (
+	DefaultMongoDatabase   = "lpicProgress"
+	DefaultMongoCollection = "progress"
+)
+
+// MongoConfig configures the MongoDB backend. The exam results, the answer
+// history and the sessions are stored next to Collection with the suffixes
+// "_exams", "_events" and "_sessions".
+type MongoConfig struct {
+	URI        string
+	Database   string
+	Collection string
+}
+
+// MongoQuestionRepository stores per-user progress documents in MongoDB so a
+// team can share one instance. Every document carries the profile and
+// certification of its Namespace.
+type MongoQuestionRepository struct {
+	client   *mongo.Client
+	states   *mongo.Collection
+	exams    *mongo.Collection
+	events   *mongo.Collection
+	sessions *mongo.Collection
+	ns       Namespace
+	// transactions is set if the server is a replica set member or mongos,
+	// which can write the answer history and the question state atomically.
+	transactions bool
+}
+
+// maxStateUpdateAttempts bounds the retries of a question state update that
+// lost a race against another client.
+const maxStateUpdateAttempts = 10
+
+type mongoQuestionState struct {
+	ID              string            `bson:"_id"`
+	Profile         string            `bson:"profile"`
+	CertificationID string            `bson:"certificationId"`
+	QuestionID      int               `bson:"questionId"`
+	MarkedAnswers   []string          `bson:"markedAnswers"`
+	AnsweredState   int               `bson:"answeredState"`
+	Important       bool              `bson:"important"`
+	Tags            []string          `bson:"tags,omitempty"`
+	Note            string            `bson:"note,omitempty"`
+	TypedAnswer     string            `bson:"typedAnswer,omitempty"`
+	Attempts        int               `bson:"attempts"`
+	LastAnsweredAt  time.Time         `bson:"lastAnsweredAt,omitempty"`
+	ResetAt         time.Time         `bson:"resetAt,omitempty"`
+	Review          types.ReviewState `bson:"review"`
+	UpdatedAt       time.Time         `bson:"updatedAt"`
+	// Version is incremented by every write, so a state is only replaced if
+	// it was not changed since it was read.
+	Version int64 `bson:"version"`
+}
+
+// mongoAnswerEvent is stored with the namespace in its _id, so the same
+// event can be imported into several namespaces. Documents of older versions
+// have the plain event ID as _id and no EventID.
+type mongoAnswerEvent struct {
+	ID              string    `bson:"_id"`
+	EventID         string    `bson:"eventId"`
+	Profile         string    `bson:"profile"`
+	CertificationID string    `bson:"certificationId"`
+	QuestionID      int       `bson:"questionId"`
+	Kind            string    `bson:"kind"`
+	Timestamp       time.Time `bson:"timestamp"`
+	SessionID       string    `bson:"sessionId"`
+	AnswerIDs       []string  `bson:"answerIds"`
+	TypedAnswer     string    `bson:"typedAnswer,omitempty"`
+	Result          int       `bson:"result"`
+	TimeSpentMillis int64     `bson:"timeSpentMs"`
+}
+
+type mongoSession struct {
+	ID              string             `bson:"_id"`
+	Profile         string             `bson:"profile"`
+	CertificationID string             `bson:"certificationId"`
+	UpdatedAt       time.Time          `bson:"updatedAt"`
+	Session         types.SessionState `bson:"session"`
+}
+
+type mongoExamResult struct {
+	ID              string           `bson:"_id"`
+	Profile         string           `bson:"profile"`
+	CertificationID string           `bson:"certificationId"`
+	StartedAt       time.Time        `bson:"startedAt"`
+	Result          types.ExamResult `bson:"result"`
+}
+
+// NewMongoQuestionRepository connects to MongoDB and returns a repository whose
+// progress is scoped to ns. Empty config fields fall back to MongoURIFromEnv,
+// DefaultMongoDatabase and DefaultMongoCollection.
+func NewMongoQuestionRepository(ctx context.Context, cfg MongoConfig, ns Namespace) (*MongoQuestionRepository, error) {
+	if cfg.URI == "" {
+		cfg.URI = database.MongoURIFromEnv()
+	}
+	if cfg.Database == "" {
+		cfg.Database = DefaultMongoDatabase
+	}
+	if cfg.Collection == "" {
+		cfg.Collection = DefaultMongoCollection
+	}
+
+	client, err := database.MongoConnectURI(ctx, cfg.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	db := client.Database(cfg.Database)
+	m := &MongoQuestionRepository{
+		client:   client,
+		states:   db.Collection(cfg.Collection),
+		exams:    db.Collection(cfg.Collection + "_exams"),
+		events:   db.Collection(cfg.Collection + "_events"),
+		sessions: db.Collection(cfg.Collection + "_sessions"),
+		ns:       ns,
+	}
+
+	namespaceIndex := bson.D{{Key: "profile", Value: 1}, {Key: "certificationId", Value: 1}}
+	for _, collection := range []*mongo.Collection{m.states, m.exams, m.events, m.sessions} {
+		if _, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: namespaceIndex}); err != nil {
+			client.Disconnect(ctx)
+			return nil, fmt.Errorf("failed to create index on %s: %w", collection.Name(), err)
+		}
+	}
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err == nil {
+		m.transactions = hello.SetName != "" || hello.Msg == "isdbgrid"
+	}
+
+	return m, nil
+}
+
+func (m *MongoQuestionRepository) Close() error {
+	if err := m.client.Disconnect(context.Background()); err != nil {
+		return fmt.Errorf("failed to disconnect from MongoDB: %w", err)
+	}
+	return nil
+}
+
+func (m *MongoQuestionRepository) filter() bson.M {
+	return bson.M{"profile": m.ns.GetProfile(), "certificationId": m.ns.CertificationID}
+}
+
+func (m *MongoQuestionRepository) stateID(questionID int) string {
+	return fmt.Sprintf("%s/%s/%d", m.ns.GetProfile(), m.ns.CertificationID, questionID)
+}
+
+func (m *MongoQuestionRepository) getQuestionState(ctx context.Context, questionID int) (*types.QuestionStateDB, error) {
+	doc, err := m.getStateDocument(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	return doc.toState(), nil
+}
+
+func (m *MongoQuestionRepository) getStateDocument(ctx context.Context, questionID int) (*mongoQuestionState, error) {
+	var doc mongoQuestionState
+	err := m.states.FindOne(ctx, bson.M{"_id": m.stateID(questionID)}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve question state: %w", err)
+	}
+	return &doc, nil
+}
+
+// errStateChanged is returned by writeQuestionState if another client changed
+// the state since it was read.
+var errStateChanged = errors.New("question state was changed concurrently")
+
+// updateQuestionState applies update to the stored state of the question,
+// or to an empty one. If another client changed the state since it was read,
+// the update is retried on the new state, so concurrent writes are not lost.
+// Inside a transaction use writeQuestionState instead: a failed write aborts
+// the transaction, which WithTransaction retries as a whole.
+func (m *MongoQuestionRepository) updateQuestionState(ctx context.Context, questionID int, update func(state *types.QuestionStateDB)) error {
+	for attempt := 0; attempt < maxStateUpdateAttempts; attempt++ {
+		err := m.writeQuestionState(ctx, questionID, update)
+		if errors.Is(err, errStateChanged) || mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("failed to store question state of question %d: changed concurrently %d times", questionID, maxStateUpdateAttempts)
+}
+
+// writeQuestionState applies update to the stored state of the question, or
+// to an empty one, and writes it once. The state is only written if no other
+// client changed it since it was read, otherwise errStateChanged or a
+// duplicate key error is returned.
+func (m *MongoQuestionRepository) writeQuestionState(ctx context.Context, questionID int, update func(state *types.QuestionStateDB)) error {
+	current, err := m.getStateDocument(ctx, questionID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	state := &types.QuestionStateDB{QuestionID: questionID}
+	if current != nil {
+		state = current.toState()
+	}
+	update(state)
+	state.QuestionID = questionID
+	doc := m.stateDocument(*state)
+
+	if current == nil {
+		doc.Version = 1
+		if _, err := m.states.InsertOne(ctx, doc); err != nil {
+			return fmt.Errorf("failed to store question state: %w", err)
+		}
+		return nil
+	}
+
+	doc.Version = current.Version + 1
+	result, err := m.states.ReplaceOne(ctx, bson.M{"_id": doc.ID, "version": current.Version}, doc)
+	if err != nil {
+		return fmt.Errorf("failed to store question state: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return errStateChanged
+	}
+	return nil
+}
+
+func (m *MongoQuestionRepository) stateDocument(state types.QuestionStateDB) mongoQuestionState {
+	markedAnswers := state.MarkedAnswers
+	if markedAnswers == nil {
+		markedAnswers = []string{}
+	}
+	return mongoQuestionState{
+		ID:              m.stateID(state.QuestionID),
+		Profile:         m.ns.GetProfile(),
+		CertificationID: m.ns.CertificationID,
+		QuestionID:      state.QuestionID,
+		MarkedAnswers:   markedAnswers,
+		AnsweredState:   int(state.AnsweredState),
+		Important:       state.Important,
+		Tags:            state.Tags,
+		Note:            state.Note,
+		TypedAnswer:     state.TypedAnswer,
+		Attempts:        state.Attempts,
+		LastAnsweredAt:  state.LastAnsweredAt,
+		ResetAt:         state.ResetAt,
+		Review:          state.Review,
+		UpdatedAt:       time.Now(),
+	}
+}
+
+func (doc mongoQuestionState) toState() *types.QuestionStateDB {
+	return &types.QuestionStateDB{
+		QuestionID:     doc.QuestionID,
+		MarkedAnswers:  doc.MarkedAnswers,
+		AnsweredState:  types.AnsweredState(doc.AnsweredState),
+		Important:      doc.Important,
+		Tags:           doc.Tags,
+		Note:           doc.Note,
+		TypedAnswer:    doc.TypedAnswer,
+		Attempts:       doc.Attempts,
+		LastAnsweredAt: doc.LastAnsweredAt,
+		ResetAt:        doc.ResetAt,
+		Review:         doc.Review,
+	}
+}
+
+// UpsertQuestion implements QuestionRepository.
+func (m *MongoQuestionRepository) UpsertQuestion(ctx context.Context, question *types.Question) error {
+	return m.updateQuestionState(ctx, question.ID, func(state *types.QuestionStateDB) {
+		state.MarkedAnswers = make([]string, 0)
+		for _, answer := range question.GetAnsweredOptions() {
+			state.MarkedAnswers = append(state.MarkedAnswers, answer.AnswerID)
+		}
+		state.AnsweredState = question.AnsweredState
+		state.Important = question.GetIsImportant()
+		state.Tags = question.GetTags()
+		state.Note = question.GetNote()
+		state.TypedAnswer = question.GetTypedAnswer()
+	})
+}
+
+// GetQuestion implements QuestionRepository. The returned question only carries
+// the stored state: its ID, answered state, important flag, tags, note and
+// marked or typed answer.
+func (m *MongoQuestionRepository) GetQuestion(ctx context.Context, id string) (*types.Question, error) {
+	questionID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question id %q: %w", id, err)
+	}
+	state, err := m.getQuestionState(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	return questionFromState(*state), nil
+}
+
+// DeleteQuestion implements QuestionRepository. The answer history is kept.
+func (m *MongoQuestionRepository) DeleteQuestion(ctx context.Context, id string) error {
+	questionID, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid question id %q: %w", id, err)
+	}
+	if _, err := m.states.DeleteOne(ctx, bson.M{"_id": m.stateID(questionID)}); err != nil {
+		return fmt.Errorf("failed to delete question state: %w", err)
+	}
+	return nil
+}
+
+func (m *MongoQuestionRepository) GetAnsweredQuestions() ([]types.QuestionStateDB, error) {
+	ctx := context.Background()
+	cursor, err := m.states.Find(ctx, m.filter(), options.Find().SetSort(bson.D{{Key: "questionId", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve question states: %w", err)
+	}
+	var docs []mongoQuestionState
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode question states: %w", err)
+	}
+
+	questionStates := make([]types.QuestionStateDB, 0, len(docs))
+	for _, doc := range docs {
+		questionStates = append(questionStates, *doc.toState())
+	}
+	return questionStates, nil
+}
+
+// RecordAnswer implements HistoryRepository. On replica sets and sharded
+// clusters the event and the updated state are written in one transaction.
+// A standalone server has no transactions, so the event is inserted first and
+// a failed state update can be repaired by replaying the history.
+func (m *MongoQuestionRepository) RecordAnswer(ctx context.Context, event types.AnswerEvent) error {
+	if event.ID == "" {
+		event.ID = newEventID()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	apply := func(state *types.QuestionStateDB) {
+		state.Apply(event)
+	}
+	if !m.transactions {
+		if _, err := m.events.InsertOne(ctx, m.eventDocument(event)); err != nil {
+			return fmt.Errorf("failed to store answer event: %w", err)
+		}
+		return m.updateQuestionState(ctx, event.QuestionID, apply)
+	}
+
+	session, err := m.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start MongoDB session: %w", err)
+	}
+	defer session.EndSession(ctx)
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if _, err := m.events.InsertOne(sc, m.eventDocument(event)); err != nil {
+			return nil, fmt.Errorf("failed to store answer event: %w", err)
+		}
+		return nil, m.writeQuestionState(sc, event.QuestionID, apply)
+	})
+	return err
+}
+
+func (m *MongoQuestionRepository) eventDocument(event types.AnswerEvent) mongoAnswerEvent {
+	return mongoAnswerEvent{
+		ID:              m.ns.String() + "/" + event.ID,
+		EventID:         event.ID,
+		Profile:         m.ns.GetProfile(),
+		CertificationID: m.ns.CertificationID,
+		QuestionID:      event.QuestionID,
+		Kind:            string(event.Kind),
+		Timestamp:       event.Timestamp,
+		SessionID:       event.SessionID,
+		AnswerIDs:       event.AnswerIDs,
+		TypedAnswer:     event.TypedAnswer,
+		Result:          int(event.Result),
+		TimeSpentMillis: event.TimeSpent.Milliseconds(),
+	}
+}
+
+// GetAnswerHistory implements HistoryRepository.
+func (m *MongoQuestionRepository) GetAnswerHistory(ctx context.Context) ([]types.AnswerEvent, error) {
+	cursor, err := m.events.Find(ctx, m.filter(), options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve answer history: %w", err)
+	}
+	var docs []mongoAnswerEvent
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode answer history: %w", err)
+	}
+
+	events := make([]types.AnswerEvent, 0, len(docs))
+	for _, doc := range docs {
+		id := doc.EventID
+		if id == "" {
+			id = doc.ID
+		}
+		events = append(events, types.AnswerEvent{
+			ID:              id,
+			Kind:            types.AnswerEventKind(doc.Kind),
+			Timestamp:       doc.Timestamp,
+			SessionID:       doc.SessionID,
+			CertificationID: doc.CertificationID,
+			QuestionID:      doc.QuestionID,
+			AnswerIDs:       doc.AnswerIDs,
+			TypedAnswer:     doc.TypedAnswer,
+			Result:          types.AnsweredState(doc.Result),
+			TimeSpent:       time.Duration(doc.TimeSpentMillis) * time.Millisecond,
+		})
+	}
+	return events, nil
+}
+
+// SaveExamResult implements ExamRepository.
+func (m *MongoQuestionRepository) SaveExamResult(ctx context.Context, result *types.ExamResult) error {
+	doc := mongoExamResult{
+		ID:              m.ns.String() + "/" + result.ID,
+		Profile:         m.ns.GetProfile(),
+		CertificationID: m.ns.CertificationID,
+		StartedAt:       result.StartedAt,
+		Result:          *result,
+	}
+	_, err := m.exams.ReplaceOne(ctx, bson.M{"_id": doc.ID}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to store exam result: %w", err)
+	}
+	return nil
+}
+
+// GetExamResults implements ExamRepository. Results are ordered by start time.
+func (m *MongoQuestionRepository) GetExamResults(ctx context.Context) ([]types.ExamResult, error) {
+	cursor, err := m.exams.Find(ctx, m.filter(), options.Find().SetSort(bson.D{{Key: "startedAt", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve exam results: %w", err)
+	}
+	var docs []mongoExamResult
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode exam results: %w", err)
+	}
+
+	results := make([]types.ExamResult, 0, len(docs))
+	for _, doc := range docs {
+		results = append(results, doc.Result)
+	}
+	return results, nil
+}
+
+// GetProgressSummary implements ProgressRepository.
+func (m *MongoQuestionRepository) GetProgressSummary(ctx context.Context) (*types.ProgressSummary, error) {
+	states, err := m.GetAnsweredQuestions()
+	if err != nil {
+		return nil, err
+	}
+	return summarizeProgress(states), nil
+}
+
+// GetDailyProgress implements ProgressRepository.
+func (m *MongoQuestionRepository) GetDailyProgress(ctx context.Context) ([]types.DailyProgress, error) {
+	events, err := m.GetAnswerHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dailyProgress(events), nil
+}
+
+// SaveSession implements SessionRepository.
+func (m *MongoQuestionRepository) SaveSession(ctx context.Context, session types.SessionState) error {
+	doc := mongoSession{
+		ID:              m.ns.String() + "/" + session.ID,
+		Profile:         m.ns.GetProfile(),
+		CertificationID: m.ns.CertificationID,
+		UpdatedAt:       session.UpdatedAt,
+		Session:         session,
+	}
+	_, err := m.sessions.ReplaceOne(ctx, bson.M{"_id": doc.ID}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+	return nil
+}
+
+// GetLatestSession implements SessionRepository.
+func (m *MongoQuestionRepository) GetLatestSession(ctx context.Context) (*types.SessionState, error) {
+	var doc mongoSession
+	err := m.sessions.FindOne(ctx, m.filter(), options.FindOne().SetSort(bson.D{{Key: "updatedAt", Value: -1}})).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve session: %w", err)
+	}
+	return &doc.Session, nil
+}
+
+// GetNamespaces implements TransferRepository.
+func (m *MongoQuestionRepository) GetNamespaces(ctx context.Context) ([]Namespace, error) {
+	group := mongo.Pipeline{{{Key: "$group", Value: bson.M{
+		"_id": bson.M{"profile": "$profile", "certificationId": "$certificationId"},
+	}}}}
+
+	seen := make(map[Namespace]bool)
+	for _, collection := range []*mongo.Collection{m.states, m.exams, m.events} {
+		cursor, err := collection.Aggregate(ctx, group)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list namespaces of %s: %w", collection.Name(), err)
+		}
+		var docs []struct {
+			ID struct {
+				Profile         string `bson:"profile"`
+				CertificationID string `bson:"certificationId"`
+			} `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return nil, fmt.Errorf("failed to decode namespaces: %w", err)
+		}
+		for _, doc := range docs {
+			seen[Namespace{CertificationID: doc.ID.CertificationID, Profile: doc.ID.Profile}] = true
+		}
+	}
+	return sortedNamespaces(seen), nil
+}
+
+// PutQuestionState implements TransferRepository.
+func (m *MongoQuestionRepository) PutQuestionState(ctx context.Context, state types.QuestionStateDB) error {
+	return m.updateQuestionState(ctx, state.QuestionID, func(stored *types.QuestionStateDB) {
+		*stored = state
+	})
+}
+
+// AppendAnswerEvents implements TransferRepository.
+func (m *MongoQuestionRepository) AppendAnswerEvents(ctx context.Context, events []types.AnswerEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		if event.ID == "" {
+			event.ID = newEventID()
+		}
+		docs = append(docs, m.eventDocument(event))
+	}
+	// unordered, so the events after one that is already stored are inserted
+	_, err := m.events.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil && !onlyDuplicateKeyErrors(err) {
+		return fmt.Errorf("failed to store answer events: %w", err)
+	}
+	return nil
+}
+
+// onlyDuplicateKeyErrors reports whether every write of a failed bulk write
+// failed because its _id is already stored.
+func onlyDuplicateKeyErrors(err error) bool {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+		return false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if !mongo.IsDuplicateKeyError(writeErr) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// These tests run the MongoDB backend against mocked server responses, so
+// the conditional updates and the transactions are covered without a server.
+
+var mongoTestNS = Namespace{CertificationID: "lpic1-101-500", Profile: "alice"}
+
+// mockMongoRepository returns a repository on the mocked client of mt.
+func mockMongoRepository(mt *mtest.T, transactions bool) *MongoQuestionRepository {
+	return &MongoQuestionRepository{
+		client:       mt.Client,
+		states:       mt.DB.Collection("progress"),
+		exams:        mt.DB.Collection("progress_exams"),
+		events:       mt.DB.Collection("progress_events"),
+		sessions:     mt.DB.Collection("progress_sessions"),
+		ns:           mongoTestNS,
+		transactions: transactions,
+	}
+}
+
+// stateResponse answers a FindOne of the question state with doc, or with no
+// document if doc is nil.
+func stateResponse(mt *mtest.T, doc *mongoQuestionState) bson.D {
+	ns := mt.DB.Name() + ".progress"
+	if doc == nil {
+		return mtest.CreateCursorResponse(0, ns, mtest.FirstBatch)
+	}
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		mt.Fatal(err)
+	}
+	var d bson.D
+	if err := bson.Unmarshal(raw, &d); err != nil {
+		mt.Fatal(err)
+	}
+	return mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, d)
+}
+
+func replaced(n int) bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+}
+
+var duplicateKey = mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 11000, Message: "E11000 duplicate key error"})
+
+// commands returns the names of the commands sent to the mocked server.
+func commands(mt *mtest.T) []string {
+	var names []string
+	for _, started := range mt.GetAllStartedEvents() {
+		names = append(names, started.CommandName)
+	}
+	return names
+}
+
+// replaceFilters returns the filters of the update commands sent.
+func replaceFilters(mt *mtest.T) []bson.Raw {
+	var filters []bson.Raw
+	for _, started := range mt.GetAllStartedEvents() {
+		if started.CommandName == "update" {
+			filters = append(filters, started.Command.Lookup("updates", "0", "q").Document())
+		}
+	}
+	return filters
+}
+
+func TestMongoUpdateQuestionState(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	mark := func(state *types.QuestionStateDB) {
+		state.Important = true
+	}
+	stored := func(version int64) *mongoQuestionState {
+		return &mongoQuestionState{ID: "alice/lpic1-101-500/7", Profile: "alice", CertificationID: "lpic1-101-500", QuestionID: 7, Version: version}
+	}
+
+	mt.Run("changed concurrently", func(mt *mtest.T) {
+		m := mockMongoRepository(mt, false)
+		mt.AddMockResponses(stateResponse(mt, stored(3)), replaced(0), stateResponse(mt, stored(4)), replaced(1))
+		if err := m.updateQuestionState(context.Background(), 7, mark); err != nil {
+			mt.Fatalf("updateQuestionState: %v", err)
+		}
+		filters := replaceFilters(mt)
+		if len(filters) != 2 {
+			mt.Fatalf("sent %d updates, want 2", len(filters))
+		}
+		for i, want := range []int64{3, 4} {
+			if got := filters[i].Lookup("version").AsInt64(); got != want {
+				mt.Errorf("update %d replaced version %d, want %d", i+1, got, want)
+			}
+		}
+	})
+
+	mt.Run("inserted concurrently", func(mt *mtest.T) {
+		m := mockMongoRepository(mt, false)
+		mt.AddMockResponses(stateResponse(mt, nil), duplicateKey, stateResponse(mt, stored(1)), replaced(1))
+		if err := m.updateQuestionState(context.Background(), 7, mark); err != nil {
+			mt.Fatalf("updateQuestionState: %v", err)
+		}
+		want := []string{"find", "insert", "find", "update"}
+		if got := commands(mt); !slices.Equal(got, want) {
+			mt.Errorf("commands = %v, want %v", got, want)
+		}
+	})
+
+	mt.Run("gives up", func(mt *mtest.T) {
+		m := mockMongoRepository(mt, false)
+		for i := 0; i < maxStateUpdateAttempts; i++ {
+			mt.AddMockResponses(stateResponse(mt, stored(int64(i+1))), replaced(0))
+		}
+		if err := m.updateQuestionState(context.Background(), 7, mark); err == nil {
+			mt.Error("updateQuestionState succeeded although the state changed on every attempt")
+		}
+	})
+}
+
+func TestMongoRecordAnswerTransaction(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	event := types.AnswerEvent{ID: "e1", Kind: types.AnswerEventAttempt, QuestionID: 7, AnswerIDs: []string{"a"}, Result: types.AnsweredTrue, Timestamp: time.Now()}
+
+	mt.Run("write errors abort the transaction", func(mt *mtest.T) {
+		m := mockMongoRepository(mt, true)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), stateResponse(mt, nil), duplicateKey, mtest.CreateSuccessResponse())
+		err := m.RecordAnswer(context.Background(), event)
+		if !mongo.IsDuplicateKeyError(err) {
+			mt.Fatalf("RecordAnswer: got %v, want the duplicate key error", err)
+		}
+		want := []string{"insert", "find", "insert", "abortTransaction"}
+		if got := commands(mt); !slices.Equal(got, want) {
+			mt.Errorf("commands = %v, want %v", got, want)
+		}
+	})
+
+	mt.Run("transient errors retry the transaction", func(mt *mtest.T) {
+		m := mockMongoRepository(mt, true)
+		writeConflict := mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 112, Name: "WriteConflict", Message: "write conflict", Labels: []string{"TransientTransactionError"},
+		})
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(), stateResponse(mt, nil), writeConflict, mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(), stateResponse(mt, nil), mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse(),
+		)
+		if err := m.RecordAnswer(context.Background(), event); err != nil {
+			mt.Fatalf("RecordAnswer: %v", err)
+		}
+		want := []string{"insert", "find", "insert", "abortTransaction", "insert", "find", "insert", "commitTransaction"}
+		if got := commands(mt); !slices.Equal(got, want) {
+			mt.Errorf("commands = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestMongoAnswerEventIDs(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("scoped to the namespace", func(mt *mtest.T) {
+		m := mockMongoRepository(mt, false)
+		doc := m.eventDocument(types.AnswerEvent{ID: "e1", QuestionID: 7})
+		if doc.ID != "alice/lpic1-101-500/e1" || doc.EventID != "e1" {
+			mt.Errorf("_id = %q and eventId = %q, want them scoped to %s", doc.ID, doc.EventID, mongoTestNS)
+		}
+	})
+
+	mt.Run("documents of older versions", func(mt *mtest.T) {
+		m := mockMongoRepository(mt, false)
+		ns := mt.DB.Name() + ".progress_events"
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "e1"}, {Key: "questionId", Value: 7}},
+			bson.D{{Key: "_id", Value: "alice/lpic1-101-500/e2"}, {Key: "eventId", Value: "e2"}, {Key: "questionId", Value: 7}},
+		))
+		events, err := m.GetAnswerHistory(context.Background())
+		if err != nil {
+			mt.Fatalf("GetAnswerHistory: %v", err)
+		}
+		if len(events) != 2 || events[0].ID != "e1" || events[1].ID != "e2" {
+			mt.Errorf("history = %+v, want the events e1 and e2", events)
+		}
+	})
+}
+
+func TestOnlyDuplicateKeyErrors(t *testing.T) {
+	duplicate := mongo.WriteError{Code: 11000, Message: "E11000 duplicate key error"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"duplicates", mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: duplicate}, {WriteError: duplicate}}}, true},
+		{"other write error", mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: duplicate}, {WriteError: mongo.WriteError{Code: 2}}}}, false},
+		{"write concern", mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: duplicate}}, WriteConcernError: &mongo.WriteConcernError{Code: 64}}, false},
+		{"no bulk write", errors.New("connection refused"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := onlyDuplicateKeyErrors(tt.err); got != tt.want {
+				t.Errorf("onlyDuplicateKeyErrors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
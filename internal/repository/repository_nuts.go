@@ -3,47 +3,60 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/SqiSch/lpic-cli/internal/types"
 	"github.com/nutsdb/nutsdb"
 )
 
 var _ QuestionRepository = (*NutsQuestionRepository)(nil)
+var _ ExamRepository = (*NutsQuestionRepository)(nil)
+var _ HistoryRepository = (*NutsQuestionRepository)(nil)
+var _ Store = (*NutsQuestionRepository)(nil)
+var _ LegacyRepository = (*NutsQuestionRepository)(nil)
+
+const (
+	answeredQuestionsBucket = "answered_questions"
+	examResultsBucket       = "exam_results"
+	answerEventsBucket      = "answer_events"
+	metaBucket              = "meta"
+	sessionsBucket          = "sessions"
+)
 
 type NutsQuestionRepository struct {
 	db *nutsdb.DB
+	ns Namespace
 }
 
 // NewNutsQuestionRepositoryWithDir creates a NutsDB-backed repository at the provided baseDir.
 // If baseDir is empty, $HOME is used. Data is always stored inside a directory named ".nutsdb".
 // If baseDir already ends with ".nutsdb" it is used directly; otherwise the ".nutsdb" segment is appended.
 func NewNutsQuestionRepositoryWithDir(baseDir string) *NutsQuestionRepository {
-	var resolved string
-	if strings.TrimSpace(baseDir) == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			log.Fatalf("failed to get user home directory: %v", err)
-		}
-		resolved = filepath.Join(homeDir, ".nutsdb")
-	} else {
-		// Expand leading ~
-		if strings.HasPrefix(baseDir, "~") {
-			homeDir, err := os.UserHomeDir()
-			if err == nil {
-				baseDir = filepath.Join(homeDir, strings.TrimPrefix(baseDir, "~"))
-			}
-		}
-		// If the user points to an existing .nutsdb directory, keep it; else append
-		if filepath.Base(baseDir) == ".nutsdb" {
-			resolved = baseDir
-		} else {
-			resolved = filepath.Join(baseDir, ".nutsdb")
-		}
+	return NewNutsQuestionRepositoryWithNamespace(baseDir, Namespace{})
+}
+
+// NewNutsQuestionRepositoryWithNamespace creates a NutsDB-backed repository like
+// NewNutsQuestionRepositoryWithDir whose progress is scoped to ns. If the
+// legacy un-namespaced progress was not migrated yet, ns receives it, see
+// migrateLegacy.
+func NewNutsQuestionRepositoryWithNamespace(baseDir string, ns Namespace) *NutsQuestionRepository {
+	return OpenNutsQuestionRepository(baseDir, ns, true)
+}
+
+// OpenNutsQuestionRepository opens the repository of ns like
+// NewNutsQuestionRepositoryWithNamespace, but moves the legacy progress into
+// ns only if migrateLegacy is set.
+func OpenNutsQuestionRepository(baseDir string, ns Namespace, migrateLegacy bool) *NutsQuestionRepository {
+	resolved, err := resolveStatePath(baseDir, ".nutsdb")
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	if err := os.MkdirAll(resolved, 0o755); err != nil {
@@ -55,9 +68,21 @@ func NewNutsQuestionRepositoryWithDir(baseDir string) *NutsQuestionRepository {
 		log.Fatalf("failed to open nutsdb at %s: %v", resolved, err)
 	}
 
-	ensureBucketExists(db, "answered_questions")
+	n := &NutsQuestionRepository{db: db, ns: ns}
+	for _, bucket := range []string{answeredQuestionsBucket, examResultsBucket, metaBucket, n.bucket(answeredQuestionsBucket), n.bucket(examResultsBucket), n.bucket(answerEventsBucket), n.bucket(sessionsBucket)} {
+		if err := ensureBucketExists(db, bucket); err != nil {
+			log.Fatalf("failed to create bucket %s: %v", bucket, err)
+		}
+	}
+
+	if !migrateLegacy {
+		return n
+	}
+	if err := n.migrateLegacy(); err != nil {
+		log.Fatalf("failed to migrate legacy progress into %s: %v", ns, err)
+	}
 
-	return &NutsQuestionRepository{db: db}
+	return n
 }
 
 // NewNutsQuestionRepository keeps backwards compatibility with previous no-arg constructor.
@@ -75,6 +100,120 @@ func ensureBucketExists(db *nutsdb.DB, bucketName string) error {
 	})
 }
 
+// bucket returns the namespaced name of a bucket.
+func (n *NutsQuestionRepository) bucket(name string) string {
+	return namespacedBucket(name, n.ns)
+}
+
+// namespacedBucket returns the name of bucket name in ns.
+func namespacedBucket(name string, ns Namespace) string {
+	if ns.IsZero() {
+		return name
+	}
+	return name + "/" + ns.String()
+}
+
+// legacyMigratedKey marks in the meta bucket that the legacy, un-namespaced
+// progress was moved into namespaces.
+const legacyMigratedKey = "legacy-migrated"
+
+// legacyMigrated reports whether the legacy progress was migrated already.
+func legacyMigrated(tx *nutsdb.Tx) (bool, error) {
+	metaKeys, err := tx.GetKeys(metaBucket)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve migration markers: %w", err)
+	}
+	for _, key := range metaKeys {
+		if string(key) == legacyMigratedKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// migrateLegacy copies the progress stored before namespaces existed into the
+// first namespace opened with migration, once per store: the question states
+// go to that namespace, the exam results to the namespace of their
+// certification with the same profile. Question states carry no
+// certification, so copying them into every namespace would leak them between
+// certifications. The legacy buckets are left untouched.
+func (n *NutsQuestionRepository) migrateLegacy() error {
+	if n.ns.IsZero() {
+		return nil
+	}
+
+	return n.db.Update(func(tx *nutsdb.Tx) error {
+		if done, err := legacyMigrated(tx); err != nil || done {
+			return err
+		}
+
+		migrated := 0
+		keys, values, err := tx.GetAll(answeredQuestionsBucket)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve legacy question states: %w", err)
+		}
+		for i := range keys {
+			if _, err := tx.Get(n.bucket(answeredQuestionsBucket), keys[i]); err == nil {
+				continue
+			}
+			if err := tx.Put(n.bucket(answeredQuestionsBucket), keys[i], values[i], 0); err != nil {
+				return err
+			}
+			migrated++
+		}
+
+		keys, values, err = tx.GetAll(examResultsBucket)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve legacy exam results: %w", err)
+		}
+		for i := range keys {
+			var result types.ExamResult
+			if err := json.Unmarshal(values[i], &result); err != nil {
+				return fmt.Errorf("failed to unmarshal exam result: %w", err)
+			}
+			if result.CertificationID == "" {
+				continue
+			}
+			bucket := namespacedBucket(examResultsBucket, Namespace{CertificationID: result.CertificationID, Profile: n.ns.Profile})
+			if !tx.ExistBucket(nutsdb.DataStructureBTree, bucket) {
+				if err := tx.NewBucket(nutsdb.DataStructureBTree, bucket); err != nil {
+					return err
+				}
+			}
+			if err := tx.Put(bucket, keys[i], values[i], 0); err != nil {
+				return err
+			}
+			migrated++
+		}
+
+		if migrated > 0 {
+			log.Printf("Migrated %d legacy entries starting with namespace %s", migrated, n.ns)
+		}
+		return tx.Put(metaBucket, []byte(legacyMigratedKey), []byte(time.Now().UTC().Format(time.RFC3339)), 0)
+	})
+}
+
+// HasLegacyProgress implements LegacyRepository.
+func (n *NutsQuestionRepository) HasLegacyProgress(ctx context.Context) (bool, error) {
+	found := false
+	err := n.db.View(func(tx *nutsdb.Tx) error {
+		if done, err := legacyMigrated(tx); err != nil || done {
+			return err
+		}
+		for _, bucket := range []string{answeredQuestionsBucket, examResultsBucket} {
+			keys, err := tx.GetKeys(bucket)
+			if err != nil {
+				return fmt.Errorf("failed to retrieve legacy progress: %w", err)
+			}
+			if len(keys) > 0 {
+				found = true
+			}
+		}
+		return nil
+	})
+	return found, err
+}
+
 func (n *NutsQuestionRepository) Close() error {
 	if err := n.db.Close(); err != nil {
 		return fmt.Errorf("failed to close nutsdb: %w", err)
@@ -82,46 +221,94 @@ func (n *NutsQuestionRepository) Close() error {
 	return nil
 }
 
-// DeleteQuestion implements QuestionRepository.
+// DeleteQuestion implements QuestionRepository. The answer history is kept.
 func (n *NutsQuestionRepository) DeleteQuestion(ctx context.Context, id string) error {
-	panic("unimplemented")
+	questionID, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid question id %q: %w", id, err)
+	}
+	return n.db.Update(func(tx *nutsdb.Tx) error {
+		err := tx.Delete(n.bucket(answeredQuestionsBucket), []byte(fmt.Sprintf("%d", questionID)))
+		if err != nil && !errors.Is(err, nutsdb.ErrKeyNotFound) {
+			return fmt.Errorf("failed to delete question state: %w", err)
+		}
+		return nil
+	})
 }
 
-// GetQuestion implements QuestionRepository.
+// GetQuestion implements QuestionRepository. The returned question only carries
+// the stored state: its ID, answered state, important flag, tags, note and
+// marked or typed answer.
 func (n *NutsQuestionRepository) GetQuestion(ctx context.Context, id string) (*types.Question, error) {
-	panic("unimplemented")
+	questionID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question id %q: %w", id, err)
+	}
+	var state *types.QuestionStateDB
+	err = n.db.View(func(tx *nutsdb.Tx) error {
+		state, err = n.getQuestionState(tx, questionID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return questionFromState(*state), nil
 }
 
 // UpsertQuestion implements QuestionRepository.
 func (n *NutsQuestionRepository) UpsertQuestion(ctx context.Context, question *types.Question) error {
-	markedAnswers := make([]string, 0)
-	for _, answer := range question.Answers {
-		if answer.GetIsMarked() {
-			markedAnswers = append(markedAnswers, answer.AnswerID)
+	return n.db.Update(func(tx *nutsdb.Tx) error {
+		// keep the history derived fields of the stored state
+		state := types.QuestionStateDB{QuestionID: question.ID}
+		current, err := n.getQuestionState(tx, question.ID)
+		if err == nil {
+			state = *current
+		} else if !errors.Is(err, ErrNotFound) {
+			return err
 		}
+		state.MarkedAnswers = make([]string, 0)
+		for _, answer := range question.GetAnsweredOptions() {
+			state.MarkedAnswers = append(state.MarkedAnswers, answer.AnswerID)
+		}
+		state.AnsweredState = question.AnsweredState
+		state.Important = question.GetIsImportant()
+		state.Tags = question.GetTags()
+		state.Note = question.GetNote()
+		state.TypedAnswer = question.GetTypedAnswer()
+		return n.putQuestionState(tx, state)
+	})
+}
+
+// getQuestionState returns the stored state of the question or ErrNotFound.
+func (n *NutsQuestionRepository) getQuestionState(tx *nutsdb.Tx, questionID int) (*types.QuestionStateDB, error) {
+	value, err := tx.Get(n.bucket(answeredQuestionsBucket), []byte(fmt.Sprintf("%d", questionID)))
+	if errors.Is(err, nutsdb.ErrKeyNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve question state: %w", err)
 	}
-	state := types.QuestionStateDB{
-		QuestionID:    question.ID,
-		MarkedAnswers: markedAnswers,
-		AnsweredState: question.AnsweredState,
-		Important:     question.GetIsImportant(),
+	var state types.QuestionStateDB
+	if err := json.Unmarshal(value, &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal question state: %w", err)
 	}
+	return &state, nil
+}
 
-	return n.db.Update(func(tx *nutsdb.Tx) error {
-		key := fmt.Sprintf("%d", question.ID)
-		value, err := json.Marshal(state)
-		if err != nil {
-			return fmt.Errorf("failed to marshal question state: %w", err)
-		}
-		return tx.Put("answered_questions", []byte(key), value, 0)
-	})
+func (n *NutsQuestionRepository) putQuestionState(tx *nutsdb.Tx, state types.QuestionStateDB) error {
+	key := fmt.Sprintf("%d", state.QuestionID)
+	value, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal question state: %w", err)
+	}
+	return tx.Put(n.bucket(answeredQuestionsBucket), []byte(key), value, 0)
 }
 
 func (n *NutsQuestionRepository) GetAnsweredQuestions() ([]types.QuestionStateDB, error) {
 	var questionStates []types.QuestionStateDB
 
 	err := n.db.View(func(tx *nutsdb.Tx) error {
-		_, values, err := tx.GetAll("answered_questions")
+		_, values, err := tx.GetAll(n.bucket(answeredQuestionsBucket))
 		if err != nil {
 			return fmt.Errorf("failed to retrieve certification set: %w", err)
 		}
@@ -144,3 +331,206 @@ func (n *NutsQuestionRepository) GetAnsweredQuestions() ([]types.QuestionStateDB
 
 	return questionStates, nil
 }
+
+// SaveExamResult implements ExamRepository.
+func (n *NutsQuestionRepository) SaveExamResult(ctx context.Context, result *types.ExamResult) error {
+	return n.db.Update(func(tx *nutsdb.Tx) error {
+		value, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to marshal exam result: %w", err)
+		}
+		return tx.Put(n.bucket(examResultsBucket), []byte(result.ID), value, 0)
+	})
+}
+
+// GetExamResults implements ExamRepository. Results are ordered by start time.
+func (n *NutsQuestionRepository) GetExamResults(ctx context.Context) ([]types.ExamResult, error) {
+	var results []types.ExamResult
+
+	err := n.db.View(func(tx *nutsdb.Tx) error {
+		_, values, err := tx.GetAll(n.bucket(examResultsBucket))
+		if err != nil {
+			return fmt.Errorf("failed to retrieve exam results: %w", err)
+		}
+
+		for _, v := range values {
+			var result types.ExamResult
+			if err := json.Unmarshal(v, &result); err != nil {
+				return fmt.Errorf("failed to unmarshal exam result: %w", err)
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].StartedAt.Before(results[j].StartedAt)
+	})
+	return results, nil
+}
+
+// RecordAnswer implements HistoryRepository. The event is appended to the
+// history and applied to the stored question state in the same transaction.
+func (n *NutsQuestionRepository) RecordAnswer(ctx context.Context, event types.AnswerEvent) error {
+	if event.ID == "" {
+		event.ID = newEventID()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	return n.db.Update(func(tx *nutsdb.Tx) error {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal answer event: %w", err)
+		}
+		if err := tx.Put(n.bucket(answerEventsBucket), eventKey(event), value, 0); err != nil {
+			return err
+		}
+
+		state, err := n.getQuestionState(tx, event.QuestionID)
+		if errors.Is(err, ErrNotFound) {
+			state = &types.QuestionStateDB{}
+		} else if err != nil {
+			return err
+		}
+		state.Apply(event)
+		return n.putQuestionState(tx, *state)
+	})
+}
+
+// GetAnswerHistory implements HistoryRepository. Events are ordered by time.
+func (n *NutsQuestionRepository) GetAnswerHistory(ctx context.Context) ([]types.AnswerEvent, error) {
+	var events []types.AnswerEvent
+
+	err := n.db.View(func(tx *nutsdb.Tx) error {
+		_, values, err := tx.GetAll(n.bucket(answerEventsBucket))
+		if err != nil {
+			return fmt.Errorf("failed to retrieve answer history: %w", err)
+		}
+
+		for _, v := range values {
+			var event types.AnswerEvent
+			if err := json.Unmarshal(v, &event); err != nil {
+				return fmt.Errorf("failed to unmarshal answer event: %w", err)
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortEvents(events)
+	return events, nil
+}
+
+// GetProgressSummary implements ProgressRepository.
+func (n *NutsQuestionRepository) GetProgressSummary(ctx context.Context) (*types.ProgressSummary, error) {
+	states, err := n.GetAnsweredQuestions()
+	if err != nil {
+		return nil, err
+	}
+	return summarizeProgress(states), nil
+}
+
+// GetDailyProgress implements ProgressRepository.
+func (n *NutsQuestionRepository) GetDailyProgress(ctx context.Context) ([]types.DailyProgress, error) {
+	events, err := n.GetAnswerHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dailyProgress(events), nil
+}
+
+// SaveSession implements SessionRepository.
+func (n *NutsQuestionRepository) SaveSession(ctx context.Context, session types.SessionState) error {
+	return n.db.Update(func(tx *nutsdb.Tx) error {
+		value, err := json.Marshal(session)
+		if err != nil {
+			return fmt.Errorf("failed to marshal session: %w", err)
+		}
+		return tx.Put(n.bucket(sessionsBucket), []byte(session.ID), value, 0)
+	})
+}
+
+// GetLatestSession implements SessionRepository.
+func (n *NutsQuestionRepository) GetLatestSession(ctx context.Context) (*types.SessionState, error) {
+	var latest *types.SessionState
+
+	err := n.db.View(func(tx *nutsdb.Tx) error {
+		_, values, err := tx.GetAll(n.bucket(sessionsBucket))
+		if err != nil {
+			return fmt.Errorf("failed to retrieve sessions: %w", err)
+		}
+
+		for _, v := range values {
+			var session types.SessionState
+			if err := json.Unmarshal(v, &session); err != nil {
+				return fmt.Errorf("failed to unmarshal session: %w", err)
+			}
+			if latest == nil || session.UpdatedAt.After(latest.UpdatedAt) {
+				latest = &session
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return nil, ErrNotFound
+	}
+	return latest, nil
+}
+
+// GetNamespaces implements TransferRepository.
+func (n *NutsQuestionRepository) GetNamespaces(ctx context.Context) ([]Namespace, error) {
+	seen := make(map[Namespace]bool)
+	err := n.db.View(func(tx *nutsdb.Tx) error {
+		// namespaced buckets are named "<bucket>/<profile>/<certificationId>"
+		return tx.IterateBuckets(nutsdb.DataStructureBTree, "*/*/*", func(bucket string) bool {
+			name, rest, _ := strings.Cut(bucket, "/")
+			profile, certID, _ := strings.Cut(rest, "/")
+			switch name {
+			case answeredQuestionsBucket, examResultsBucket, answerEventsBucket:
+				seen[Namespace{CertificationID: certID, Profile: profile}] = true
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	return sortedNamespaces(seen), nil
+}
+
+// PutQuestionState implements TransferRepository.
+func (n *NutsQuestionRepository) PutQuestionState(ctx context.Context, state types.QuestionStateDB) error {
+	return n.db.Update(func(tx *nutsdb.Tx) error {
+		return n.putQuestionState(tx, state)
+	})
+}
+
+// AppendAnswerEvents implements TransferRepository.
+func (n *NutsQuestionRepository) AppendAnswerEvents(ctx context.Context, events []types.AnswerEvent) error {
+	return n.db.Update(func(tx *nutsdb.Tx) error {
+		for _, event := range events {
+			if event.ID == "" {
+				event.ID = newEventID()
+			}
+			value, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("failed to marshal answer event: %w", err)
+			}
+			if err := tx.Put(n.bucket(answerEventsBucket), eventKey(event), value, 0); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,122 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/repository"
+	"github.com/SqiSch/lpic-cli/internal/types"
+	"github.com/nutsdb/nutsdb"
+)
+
+// writeLegacyProgress stores a question state and an exam result of two
+// certifications the way versions without namespaces did.
+func writeLegacyProgress(t *testing.T, dir string) {
+	t.Helper()
+	ctx := context.Background()
+	legacy := repository.NewNutsQuestionRepositoryWithDir(dir)
+	defer legacy.Close()
+
+	question := &types.Question{ID: 7, Answers: []*types.Answer{{AnswerID: "a", IsCorrect: true}}}
+	question.Answers[0].SetIsMarked(true)
+	question.SetAnsweredState(types.AnsweredTrue)
+	if err := legacy.UpsertQuestion(ctx, question); err != nil {
+		t.Fatalf("UpsertQuestion: %v", err)
+	}
+	for _, result := range []*types.ExamResult{
+		{ID: "exam-101", CertificationID: "lpic1-101-500"},
+		{ID: "exam-102", CertificationID: "lpic1-102-500"},
+	} {
+		if err := legacy.SaveExamResult(ctx, result); err != nil {
+			t.Fatalf("SaveExamResult: %v", err)
+		}
+	}
+}
+
+// countProgress opens ns and returns its number of question states and exam
+// results.
+func countProgress(t *testing.T, dir string, ns repository.Namespace, migrateLegacy bool) (states, results int) {
+	t.Helper()
+	store := repository.OpenNutsQuestionRepository(dir, ns, migrateLegacy)
+	defer store.Close()
+	answered, err := store.GetAnsweredQuestions()
+	if err != nil {
+		t.Fatalf("GetAnsweredQuestions: %v", err)
+	}
+	exams, err := store.GetExamResults(context.Background())
+	if err != nil {
+		t.Fatalf("GetExamResults: %v", err)
+	}
+	return len(answered), len(exams)
+}
+
+func TestNutsLegacyMigration(t *testing.T) {
+	dir := t.TempDir()
+	writeLegacyProgress(t, dir)
+
+	tests := []struct {
+		ns              repository.Namespace
+		states, results int
+	}{
+		// the first namespace opened receives the question states
+		{repository.Namespace{CertificationID: "lpic1-101-500"}, 1, 1},
+		// other certifications only get their exam results
+		{repository.Namespace{CertificationID: "lpic1-102-500"}, 0, 1},
+		{repository.Namespace{CertificationID: "lpic2-201-450"}, 0, 0},
+		{repository.Namespace{CertificationID: "lpic1-101-500", Profile: "bob"}, 0, 0},
+		// reopening does not migrate again
+		{repository.Namespace{CertificationID: "lpic1-101-500"}, 1, 1},
+	}
+	for _, tt := range tests {
+		states, results := countProgress(t, dir, tt.ns, true)
+		if states != tt.states || results != tt.results {
+			t.Errorf("%s: %d question states and %d exam results, want %d and %d", tt.ns, states, results, tt.states, tt.results)
+		}
+	}
+}
+
+func TestNutsLegacyMigrationOptIn(t *testing.T) {
+	dir := t.TempDir()
+	writeLegacyProgress(t, dir)
+	ns := repository.Namespace{CertificationID: "lpic1-102-500", Profile: "alice"}
+
+	if states, results := countProgress(t, dir, ns, false); states != 0 || results != 0 {
+		t.Errorf("without migration: %d question states and %d exam results, want none", states, results)
+	}
+
+	if states, results := countProgress(t, dir, ns, true); states != 1 || results != 1 {
+		t.Errorf("migration after a skipped one: %d question states and %d exam results, want 1 and 1", states, results)
+	}
+}
+
+// TestNutsRecordAnswerCorruptState makes sure a state that cannot be read is
+// not replaced by an empty one.
+func TestNutsRecordAnswerCorruptState(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	ns := repository.Namespace{CertificationID: "lpic1-101-500", Profile: "alice"}
+	repository.NewNutsQuestionRepositoryWithNamespace(dir, ns).Close()
+
+	db, err := nutsdb.Open(nutsdb.DefaultOptions, nutsdb.WithDir(filepath.Join(dir, ".nutsdb")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Update(func(tx *nutsdb.Tx) error {
+		return tx.Put("answered_questions/"+ns.String(), []byte("7"), []byte("{not json"), 0)
+	})
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := repository.NewNutsQuestionRepositoryWithNamespace(dir, ns)
+	defer store.Close()
+	event := types.AnswerEvent{Kind: types.AnswerEventAttempt, QuestionID: 7, AnswerIDs: []string{"a"}, Result: types.AnsweredTrue}
+	if err := store.RecordAnswer(ctx, event); err == nil {
+		t.Error("RecordAnswer replaced a corrupt question state")
+	}
+	if err := store.UpsertQuestion(ctx, &types.Question{ID: 7}); err == nil {
+		t.Error("UpsertQuestion replaced a corrupt question state")
+	}
+}
@@ -0,0 +1,691 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+	_ "modernc.org/sqlite"
+)
+
+var _ Store = (*SQLiteQuestionRepository)(nil)
+
+const (
+	sqliteFileName = ".lpic-cli.sqlite"
+
+	// sqliteTimeFormat is understood by the SQLite date functions and sorts
+	// lexicographically. All times are stored in UTC.
+	sqliteTimeFormat = "2006-01-02 15:04:05.000000000"
+)
+
+// sqliteSchema is plain SQL on purpose: the database can be inspected with any
+// SQLite tool, including the progress_summary and daily_progress views.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS question_states (
+	profile           TEXT    NOT NULL,
+	certification_id  TEXT    NOT NULL,
+	question_id       INTEGER NOT NULL,
+	marked_answers    TEXT    NOT NULL DEFAULT '[]',
+	answered_state    INTEGER NOT NULL DEFAULT 0,
+	important         INTEGER NOT NULL DEFAULT 0,
+	attempts          INTEGER NOT NULL DEFAULT 0,
+	last_answered_at  TEXT,
+	ease_factor       REAL    NOT NULL DEFAULT 0,
+	interval_days     INTEGER NOT NULL DEFAULT 0,
+	repetitions       INTEGER NOT NULL DEFAULT 0,
+	lapses            INTEGER NOT NULL DEFAULT 0,
+	due_at            TEXT,
+	review_session_id TEXT    NOT NULL DEFAULT '',
+	tags              TEXT    NOT NULL DEFAULT '[]',
+	note              TEXT    NOT NULL DEFAULT '',
+	typed_answer      TEXT    NOT NULL DEFAULT '',
+	reset_at          TEXT,
+	PRIMARY KEY (profile, certification_id, question_id)
+);
+
+CREATE TABLE IF NOT EXISTS answer_events (` + sqliteAnswerEventsColumns + `);
+CREATE INDEX IF NOT EXISTS answer_events_namespace ON answer_events (profile, certification_id, occurred_at);
+
+CREATE TABLE IF NOT EXISTS exam_results (
+	id               TEXT    PRIMARY KEY,
+	profile          TEXT    NOT NULL,
+	certification_id TEXT    NOT NULL,
+	started_at       TEXT,
+	finished_at      TEXT,
+	scaled_score     INTEGER NOT NULL,
+	passed           INTEGER NOT NULL,
+	correct          INTEGER NOT NULL,
+	total            INTEGER NOT NULL,
+	data             TEXT    NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS sessions (
+	id               TEXT    PRIMARY KEY,
+	profile          TEXT    NOT NULL,
+	certification_id TEXT    NOT NULL,
+	updated_at       TEXT    NOT NULL,
+	data             TEXT    NOT NULL
+);
+
+CREATE VIEW IF NOT EXISTS progress_summary AS
+SELECT profile, certification_id,
+	COUNT(*)                           AS questions,
+	COALESCE(SUM(answered_state = 1), 0) AS correct,
+	COALESCE(SUM(answered_state = 2), 0) AS incorrect,
+	COALESCE(SUM(important), 0)          AS important,
+	COALESCE(SUM(attempts), 0)           AS attempts
+FROM question_states
+GROUP BY profile, certification_id;
+
+CREATE VIEW IF NOT EXISTS daily_progress AS
+SELECT profile, certification_id,
+	substr(occurred_at, 1, 10) AS day,
+	COUNT(*)                   AS attempts,
+	SUM(result = 1)            AS correct,
+	SUM(result = 2)            AS incorrect,
+	SUM(time_spent_ms)         AS time_spent_ms
+FROM answer_events
+WHERE kind <> 'reset'
+GROUP BY profile, certification_id, day;
+`
+
+// sqliteAnswerEventsColumns define the answer_events table. An event is keyed
+// by its namespace as well, so the same event can be imported into several.
+const sqliteAnswerEventsColumns = `
+	id               TEXT    NOT NULL,
+	profile          TEXT    NOT NULL,
+	certification_id TEXT    NOT NULL,
+	question_id      INTEGER NOT NULL,
+	kind             TEXT    NOT NULL,
+	occurred_at      TEXT    NOT NULL,
+	session_id       TEXT    NOT NULL DEFAULT '',
+	answer_ids       TEXT    NOT NULL DEFAULT '[]',
+	typed_answer     TEXT    NOT NULL DEFAULT '',
+	result           INTEGER NOT NULL DEFAULT 0,
+	time_spent_ms    INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (profile, certification_id, id)
+`
+
+// SQLiteQuestionRepository stores progress in a single SQLite file using a
+// pure-Go driver, so no cgo and no directory lock are involved.
+type SQLiteQuestionRepository struct {
+	db *sql.DB
+	ns Namespace
+}
+
+// NewSQLiteQuestionRepository opens (and creates if needed) the SQLite database
+// at path with progress scoped to ns.
+func NewSQLiteQuestionRepository(path string, ns Namespace) (*SQLiteQuestionRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database at %s: %w", path, err)
+	}
+	// a single connection serializes writers inside the process
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create sqlite schema: %w", err)
+	}
+	if err := migrateSQLiteSchema(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate sqlite schema: %w", err)
+	}
+
+	return &SQLiteQuestionRepository{db: db, ns: ns}, nil
+}
+
+// sqliteAddedColumns are the columns added to tables after their first
+// version. Databases created before get them on open.
+var sqliteAddedColumns = []struct{ table, name, definition string }{
+	{"question_states", "reset_at", "TEXT"},
+	{"question_states", "tags", "TEXT NOT NULL DEFAULT '[]'"},
+	{"question_states", "note", "TEXT NOT NULL DEFAULT ''"},
+	{"question_states", "typed_answer", "TEXT NOT NULL DEFAULT ''"},
+	{"answer_events", "typed_answer", "TEXT NOT NULL DEFAULT ''"},
+}
+
+func migrateSQLiteSchema(db *sql.DB) error {
+	existing := make(map[string]map[string]bool)
+	for _, column := range sqliteAddedColumns {
+		if existing[column.table] == nil {
+			columns, err := sqliteColumns(db, column.table)
+			if err != nil {
+				return err
+			}
+			existing[column.table] = columns
+		}
+		if existing[column.table][column.name] {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE ` + column.table + ` ADD COLUMN ` + column.name + ` ` + column.definition); err != nil {
+			return err
+		}
+	}
+	return rekeySQLiteAnswerEvents(db)
+}
+
+// rekeySQLiteAnswerEvents rebuilds an answer_events table of older versions,
+// whose events are keyed by their ID alone. The daily_progress view and the
+// index are dropped with the old table and created again by sqliteSchema.
+func rekeySQLiteAnswerEvents(db *sql.DB) error {
+	var keyColumns int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('answer_events') WHERE pk > 0`).Scan(&keyColumns); err != nil {
+		return err
+	}
+	if keyColumns != 1 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	const columns = `id, profile, certification_id, question_id, kind, occurred_at, session_id, answer_ids, typed_answer, result, time_spent_ms`
+	for _, statement := range []string{
+		`DROP VIEW IF EXISTS daily_progress`,
+		`CREATE TABLE answer_events_rekeyed (` + sqliteAnswerEventsColumns + `)`,
+		`INSERT INTO answer_events_rekeyed (` + columns + `) SELECT ` + columns + ` FROM answer_events ORDER BY rowid`,
+		`DROP TABLE answer_events`,
+		`ALTER TABLE answer_events_rekeyed RENAME TO answer_events`,
+		sqliteSchema,
+	} {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// sqliteColumns returns the names of the columns of table.
+func sqliteColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+func (s *SQLiteQuestionRepository) Close() error {
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("failed to close sqlite database: %w", err)
+	}
+	return nil
+}
+
+func formatSQLiteTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.UTC().Format(sqliteTimeFormat), Valid: true}
+}
+
+func parseSQLiteTime(value sql.NullString) (time.Time, error) {
+	if !value.Valid || value.String == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(sqliteTimeFormat, value.String, time.UTC)
+}
+
+type sqlQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+const questionStateColumns = `question_id, marked_answers, answered_state, important, attempts, last_answered_at,
+	ease_factor, interval_days, repetitions, lapses, due_at, review_session_id, tags, note, typed_answer, reset_at`
+
+type sqlScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanQuestionState(row sqlScanner) (*types.QuestionStateDB, error) {
+	var (
+		state          types.QuestionStateDB
+		markedAnswers  string
+		tags           string
+		lastAnsweredAt sql.NullString
+		dueAt          sql.NullString
+		resetAt        sql.NullString
+	)
+	err := row.Scan(&state.QuestionID, &markedAnswers, &state.AnsweredState, &state.Important, &state.Attempts, &lastAnsweredAt,
+		&state.Review.EaseFactor, &state.Review.IntervalDays, &state.Review.Repetitions, &state.Review.Lapses, &dueAt, &state.Review.LastSessionID,
+		&tags, &state.Note, &state.TypedAnswer, &resetAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(markedAnswers), &state.MarkedAnswers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal marked answers: %w", err)
+	}
+	if err := json.Unmarshal([]byte(tags), &state.Tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+	if len(state.Tags) == 0 {
+		state.Tags = nil
+	}
+	if state.LastAnsweredAt, err = parseSQLiteTime(lastAnsweredAt); err != nil {
+		return nil, err
+	}
+	if state.Review.Due, err = parseSQLiteTime(dueAt); err != nil {
+		return nil, err
+	}
+	if state.ResetAt, err = parseSQLiteTime(resetAt); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (s *SQLiteQuestionRepository) getQuestionState(ctx context.Context, q sqlQueryer, questionID int) (*types.QuestionStateDB, error) {
+	row := q.QueryRowContext(ctx, `SELECT `+questionStateColumns+` FROM question_states
+		WHERE profile = ? AND certification_id = ? AND question_id = ?`,
+		s.ns.GetProfile(), s.ns.CertificationID, questionID)
+	state, err := scanQuestionState(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return state, err
+}
+
+func (s *SQLiteQuestionRepository) putQuestionState(ctx context.Context, q sqlQueryer, state types.QuestionStateDB) error {
+	markedAnswers := state.MarkedAnswers
+	if markedAnswers == nil {
+		markedAnswers = []string{}
+	}
+	marked, err := json.Marshal(markedAnswers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal marked answers: %w", err)
+	}
+	tags := state.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	_, err = q.ExecContext(ctx, `INSERT INTO question_states (profile, certification_id, `+questionStateColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (profile, certification_id, question_id) DO UPDATE SET
+			marked_answers = excluded.marked_answers,
+			answered_state = excluded.answered_state,
+			important = excluded.important,
+			attempts = excluded.attempts,
+			last_answered_at = excluded.last_answered_at,
+			ease_factor = excluded.ease_factor,
+			interval_days = excluded.interval_days,
+			repetitions = excluded.repetitions,
+			lapses = excluded.lapses,
+			due_at = excluded.due_at,
+			review_session_id = excluded.review_session_id,
+			tags = excluded.tags,
+			note = excluded.note,
+			typed_answer = excluded.typed_answer,
+			reset_at = excluded.reset_at`,
+		s.ns.GetProfile(), s.ns.CertificationID, state.QuestionID, string(marked), state.AnsweredState, state.Important,
+		state.Attempts, formatSQLiteTime(state.LastAnsweredAt),
+		state.Review.EaseFactor, state.Review.IntervalDays, state.Review.Repetitions, state.Review.Lapses,
+		formatSQLiteTime(state.Review.Due), state.Review.LastSessionID, string(tagsJSON), state.Note, state.TypedAnswer,
+		formatSQLiteTime(state.ResetAt))
+	if err != nil {
+		return fmt.Errorf("failed to store question state: %w", err)
+	}
+	return nil
+}
+
+// UpsertQuestion implements QuestionRepository.
+func (s *SQLiteQuestionRepository) UpsertQuestion(ctx context.Context, question *types.Question) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	state := types.QuestionStateDB{QuestionID: question.ID}
+	if current, err := s.getQuestionState(ctx, tx, question.ID); err == nil {
+		state = *current
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	state.MarkedAnswers = make([]string, 0)
+	for _, answer := range question.GetAnsweredOptions() {
+		state.MarkedAnswers = append(state.MarkedAnswers, answer.AnswerID)
+	}
+	state.AnsweredState = question.AnsweredState
+	state.Important = question.GetIsImportant()
+	state.Tags = question.GetTags()
+	state.Note = question.GetNote()
+	state.TypedAnswer = question.GetTypedAnswer()
+
+	if err := s.putQuestionState(ctx, tx, state); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetQuestion implements QuestionRepository. The returned question only carries
+// the stored state: its ID, answered state, important flag, tags, note and
+// marked or typed answer.
+func (s *SQLiteQuestionRepository) GetQuestion(ctx context.Context, id string) (*types.Question, error) {
+	questionID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question id %q: %w", id, err)
+	}
+	state, err := s.getQuestionState(ctx, s.db, questionID)
+	if err != nil {
+		return nil, err
+	}
+	return questionFromState(*state), nil
+}
+
+// DeleteQuestion implements QuestionRepository. The answer history is kept.
+func (s *SQLiteQuestionRepository) DeleteQuestion(ctx context.Context, id string) error {
+	questionID, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid question id %q: %w", id, err)
+	}
+	_, err = s.db.ExecContext(ctx, `DELETE FROM question_states WHERE profile = ? AND certification_id = ? AND question_id = ?`,
+		s.ns.GetProfile(), s.ns.CertificationID, questionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete question state: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteQuestionRepository) GetAnsweredQuestions() ([]types.QuestionStateDB, error) {
+	rows, err := s.db.Query(`SELECT `+questionStateColumns+` FROM question_states
+		WHERE profile = ? AND certification_id = ? ORDER BY question_id`,
+		s.ns.GetProfile(), s.ns.CertificationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve question states: %w", err)
+	}
+	defer rows.Close()
+
+	var questionStates []types.QuestionStateDB
+	for rows.Next() {
+		state, err := scanQuestionState(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read question state: %w", err)
+		}
+		questionStates = append(questionStates, *state)
+	}
+	return questionStates, rows.Err()
+}
+
+// RecordAnswer implements HistoryRepository.
+func (s *SQLiteQuestionRepository) RecordAnswer(ctx context.Context, event types.AnswerEvent) error {
+	if event.ID == "" {
+		event.ID = newEventID()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.insertAnswerEvent(ctx, tx, event, false); err != nil {
+		return err
+	}
+
+	state, err := s.getQuestionState(ctx, tx, event.QuestionID)
+	if errors.Is(err, ErrNotFound) {
+		state = &types.QuestionStateDB{}
+	} else if err != nil {
+		return err
+	}
+	state.Apply(event)
+	if err := s.putQuestionState(ctx, tx, *state); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertAnswerEvent stores event. With skipDuplicate an event whose ID is
+// already stored is left as is instead of failing.
+func (s *SQLiteQuestionRepository) insertAnswerEvent(ctx context.Context, q sqlQueryer, event types.AnswerEvent, skipDuplicate bool) error {
+	answerIDs, err := json.Marshal(event.AnswerIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal answer ids: %w", err)
+	}
+	insert := `INSERT`
+	if skipDuplicate {
+		insert = `INSERT OR IGNORE`
+	}
+	_, err = q.ExecContext(ctx, insert+` INTO answer_events
+		(id, profile, certification_id, question_id, kind, occurred_at, session_id, answer_ids, typed_answer, result, time_spent_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.ID, s.ns.GetProfile(), s.ns.CertificationID, event.QuestionID, string(event.Kind),
+		formatSQLiteTime(event.Timestamp), event.SessionID, string(answerIDs), event.TypedAnswer, event.Result, event.TimeSpent.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to store answer event: %w", err)
+	}
+	return nil
+}
+
+// GetAnswerHistory implements HistoryRepository.
+func (s *SQLiteQuestionRepository) GetAnswerHistory(ctx context.Context) ([]types.AnswerEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, certification_id, question_id, kind, occurred_at, session_id, answer_ids, typed_answer, result, time_spent_ms
+		FROM answer_events WHERE profile = ? AND certification_id = ? ORDER BY occurred_at, rowid`,
+		s.ns.GetProfile(), s.ns.CertificationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve answer history: %w", err)
+	}
+	defer rows.Close()
+
+	var events []types.AnswerEvent
+	for rows.Next() {
+		var (
+			event      types.AnswerEvent
+			kind       string
+			occurredAt sql.NullString
+			answerIDs  string
+			timeSpent  int64
+		)
+		if err := rows.Scan(&event.ID, &event.CertificationID, &event.QuestionID, &kind, &occurredAt, &event.SessionID,
+			&answerIDs, &event.TypedAnswer, &event.Result, &timeSpent); err != nil {
+			return nil, fmt.Errorf("failed to read answer event: %w", err)
+		}
+		event.Kind = types.AnswerEventKind(kind)
+		event.TimeSpent = time.Duration(timeSpent) * time.Millisecond
+		if event.Timestamp, err = parseSQLiteTime(occurredAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(answerIDs), &event.AnswerIDs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal answer ids: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// SaveExamResult implements ExamRepository.
+func (s *SQLiteQuestionRepository) SaveExamResult(ctx context.Context, result *types.ExamResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal exam result: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT OR REPLACE INTO exam_results
+		(id, profile, certification_id, started_at, finished_at, scaled_score, passed, correct, total, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		result.ID, s.ns.GetProfile(), s.ns.CertificationID, formatSQLiteTime(result.StartedAt), formatSQLiteTime(result.FinishedAt),
+		result.ScaledScore, result.Passed, result.Correct, result.Total, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to store exam result: %w", err)
+	}
+	return nil
+}
+
+// GetExamResults implements ExamRepository. Results are ordered by start time.
+func (s *SQLiteQuestionRepository) GetExamResults(ctx context.Context) ([]types.ExamResult, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM exam_results
+		WHERE profile = ? AND certification_id = ? ORDER BY started_at`,
+		s.ns.GetProfile(), s.ns.CertificationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve exam results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []types.ExamResult
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read exam result: %w", err)
+		}
+		var result types.ExamResult
+		if err := json.Unmarshal([]byte(data), &result); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal exam result: %w", err)
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// GetProgressSummary implements ProgressRepository using the progress_summary view.
+func (s *SQLiteQuestionRepository) GetProgressSummary(ctx context.Context) (*types.ProgressSummary, error) {
+	summary := &types.ProgressSummary{}
+	err := s.db.QueryRowContext(ctx, `SELECT questions, correct, incorrect, important, attempts
+		FROM progress_summary WHERE profile = ? AND certification_id = ?`,
+		s.ns.GetProfile(), s.ns.CertificationID).
+		Scan(&summary.Questions, &summary.Correct, &summary.Incorrect, &summary.Important, &summary.Attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return summary, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query progress summary: %w", err)
+	}
+	return summary, nil
+}
+
+// GetDailyProgress implements ProgressRepository using the daily_progress view.
+func (s *SQLiteQuestionRepository) GetDailyProgress(ctx context.Context) ([]types.DailyProgress, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT day, attempts, correct, incorrect, time_spent_ms
+		FROM daily_progress WHERE profile = ? AND certification_id = ? ORDER BY day`,
+		s.ns.GetProfile(), s.ns.CertificationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily progress: %w", err)
+	}
+	defer rows.Close()
+
+	var days []types.DailyProgress
+	for rows.Next() {
+		var (
+			day       types.DailyProgress
+			timeSpent int64
+		)
+		if err := rows.Scan(&day.Day, &day.Attempts, &day.Correct, &day.Incorrect, &timeSpent); err != nil {
+			return nil, fmt.Errorf("failed to read daily progress: %w", err)
+		}
+		day.TimeSpent = time.Duration(timeSpent) * time.Millisecond
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
+
+// SaveSession implements SessionRepository.
+func (s *SQLiteQuestionRepository) SaveSession(ctx context.Context, session types.SessionState) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT OR REPLACE INTO sessions (id, profile, certification_id, updated_at, data)
+		VALUES (?, ?, ?, ?, ?)`,
+		session.ID, s.ns.GetProfile(), s.ns.CertificationID, formatSQLiteTime(session.UpdatedAt).String, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+	return nil
+}
+
+// GetLatestSession implements SessionRepository.
+func (s *SQLiteQuestionRepository) GetLatestSession(ctx context.Context) (*types.SessionState, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM sessions
+		WHERE profile = ? AND certification_id = ? ORDER BY updated_at DESC LIMIT 1`,
+		s.ns.GetProfile(), s.ns.CertificationID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve session: %w", err)
+	}
+	var session types.SessionState
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &session, nil
+}
+
+// GetNamespaces implements TransferRepository.
+func (s *SQLiteQuestionRepository) GetNamespaces(ctx context.Context) ([]Namespace, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT profile, certification_id FROM question_states
+		UNION SELECT profile, certification_id FROM answer_events
+		UNION SELECT profile, certification_id FROM exam_results`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[Namespace]bool)
+	for rows.Next() {
+		var ns Namespace
+		if err := rows.Scan(&ns.Profile, &ns.CertificationID); err != nil {
+			return nil, fmt.Errorf("failed to read namespace: %w", err)
+		}
+		seen[ns] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sortedNamespaces(seen), nil
+}
+
+// PutQuestionState implements TransferRepository.
+func (s *SQLiteQuestionRepository) PutQuestionState(ctx context.Context, state types.QuestionStateDB) error {
+	return s.putQuestionState(ctx, s.db, state)
+}
+
+// AppendAnswerEvents implements TransferRepository.
+func (s *SQLiteQuestionRepository) AppendAnswerEvents(ctx context.Context, events []types.AnswerEvent) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, event := range events {
+		if event.ID == "" {
+			event.ID = newEventID()
+		}
+		if err := s.insertAnswerEvent(ctx, tx, event, true); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
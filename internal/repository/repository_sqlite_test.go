@@ -0,0 +1,73 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/repository"
+)
+
+// TestSQLiteRekeyAnswerEvents opens a database whose answer events are keyed
+// by their ID alone, as older versions created it.
+func TestSQLiteRekeyAnswerEvents(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "progress.sqlite")
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, statement := range []string{
+		`CREATE TABLE answer_events (
+			id               TEXT    PRIMARY KEY,
+			profile          TEXT    NOT NULL,
+			certification_id TEXT    NOT NULL,
+			question_id      INTEGER NOT NULL,
+			kind             TEXT    NOT NULL,
+			occurred_at      TEXT    NOT NULL,
+			session_id       TEXT    NOT NULL DEFAULT '',
+			answer_ids       TEXT    NOT NULL DEFAULT '[]',
+			result           INTEGER NOT NULL DEFAULT 0,
+			time_spent_ms    INTEGER NOT NULL DEFAULT 0
+		)`,
+		`INSERT INTO answer_events (id, profile, certification_id, question_id, kind, occurred_at, answer_ids, result)
+			VALUES ('e1', 'alice', 'lpic1-101-500', 7, 'attempt', '2026-03-02 09:30:00.000000000', '["a"]', 1)`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("creating the old schema: %v", err)
+		}
+	}
+	db.Close()
+
+	alice := repository.Namespace{CertificationID: "lpic1-101-500", Profile: "alice"}
+	bob := repository.Namespace{CertificationID: "lpic1-101-500", Profile: "bob"}
+	store, err := repository.NewSQLiteQuestionRepository(path, alice)
+	if err != nil {
+		t.Fatalf("NewSQLiteQuestionRepository: %v", err)
+	}
+	history, err := store.GetAnswerHistory(ctx)
+	if err != nil {
+		t.Fatalf("GetAnswerHistory: %v", err)
+	}
+	store.Close()
+	if len(history) != 1 || history[0].ID != "e1" || history[0].QuestionID != 7 {
+		t.Fatalf("history = %+v, want the stored event", history)
+	}
+
+	store, err = repository.NewSQLiteQuestionRepository(path, bob)
+	if err != nil {
+		t.Fatalf("NewSQLiteQuestionRepository: %v", err)
+	}
+	defer store.Close()
+	if err := store.AppendAnswerEvents(ctx, history); err != nil {
+		t.Fatalf("AppendAnswerEvents: %v", err)
+	}
+	if history, err := store.GetAnswerHistory(ctx); err != nil || len(history) != 1 {
+		t.Errorf("history of bob = %+v, %v, want the event of alice", history, err)
+	}
+	daily, err := store.GetDailyProgress(ctx)
+	if err != nil || len(daily) != 1 {
+		t.Errorf("GetDailyProgress = %+v, %v, want one day", daily, err)
+	}
+}
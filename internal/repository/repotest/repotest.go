@@ -0,0 +1,949 @@
+// Package repotest is the conformance suite every progress backend of package
+// repository has to pass.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SqiSch/lpic-cli/internal/repository"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// Opener opens the backend under test scoped to ns. All calls of one Opener
+// must address the same storage, like reopening the same state directory.
+// Backends that lock their storage are only opened one at a time.
+type Opener = repository.Opener
+
+var (
+	nsAlice = repository.Namespace{CertificationID: "lpic1-101-500", Profile: "alice"}
+	nsBob   = repository.Namespace{CertificationID: "lpic1-101-500", Profile: "bob"}
+	nsCert  = repository.Namespace{CertificationID: "lpic1-102-500", Profile: "alice"}
+
+	// start is a fixed point in time at millisecond precision, which every
+	// backend stores without loss.
+	start = time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)
+)
+
+// Run runs the conformance suite. newOpener is called once per test and has to
+// return an Opener on fresh, empty storage.
+func Run(t *testing.T, newOpener func(t *testing.T) Opener) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, opener Opener)
+	}{
+		{"UpsertRoundTrip", testUpsertRoundTrip},
+		{"UpsertOverwrites", testUpsertOverwrites},
+		{"ImportantFlag", testImportantFlag},
+		{"TagsAndNote", testTagsAndNote},
+		{"TypedAnswer", testTypedAnswer},
+		{"UpsertKeepsHistoryFields", testUpsertKeepsHistoryFields},
+		{"GetQuestionNotFound", testGetQuestionNotFound},
+		{"DeleteQuestion", testDeleteQuestion},
+		{"RecordAnswer", testRecordAnswer},
+		{"Reset", testReset},
+		{"ReviewSchedule", testReviewSchedule},
+		{"ExamResults", testExamResults},
+		{"Progress", testProgress},
+		{"Reopen", testReopen},
+		{"NamespaceIsolation", testNamespaceIsolation},
+		{"Sessions", testSessions},
+		{"Transfer", testTransfer},
+		{"TransferIntoTwoNamespaces", testTransferIntoTwoNamespaces},
+		{"ConcurrentAccess", testConcurrentAccess},
+		{"ConcurrentAnswersToOneQuestion", testConcurrentAnswersToOneQuestion},
+		{"Close", testClose},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newOpener(t))
+		})
+	}
+}
+
+// open opens a store and closes it when the test ends. Tests that reopen a
+// namespace close the store themselves; the second Close is ignored.
+func open(t *testing.T, opener Opener, ns repository.Namespace) repository.Store {
+	t.Helper()
+	store, err := opener(ns)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", ns, err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func closeStore(t *testing.T, store repository.Store) {
+	t.Helper()
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+// newQuestion returns a question with the answers a-d, a and c being correct,
+// and marks the given answers.
+func newQuestion(id int, marked ...string) *types.Question {
+	question := &types.Question{ID: id, Text: fmt.Sprintf("Question %d", id)}
+	for _, answerID := range []string{"a", "b", "c", "d"} {
+		answer := &types.Answer{AnswerID: answerID, Text: "Answer " + answerID, IsCorrect: answerID == "a" || answerID == "c"}
+		for _, m := range marked {
+			if m == answerID {
+				answer.SetIsMarked(true)
+			}
+		}
+		question.Answers = append(question.Answers, answer)
+	}
+	switch {
+	case len(marked) == 0:
+	case question.IsAnsweredCorrectly():
+		question.SetAnsweredState(types.AnsweredTrue)
+	default:
+		question.SetAnsweredState(types.AnsweredFalse)
+	}
+	return question
+}
+
+func attempt(id int, sessionID string, at time.Time, marked ...string) types.AnswerEvent {
+	event := types.NewAnswerEvent(newQuestion(id, marked...), "", sessionID, 30*time.Second)
+	event.Timestamp = at
+	return event
+}
+
+func markedIDs(question *types.Question) []string {
+	ids := make([]string, 0)
+	for _, answer := range question.GetAnsweredOptions() {
+		ids = append(ids, answer.AnswerID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func sorted(ids []string) []string {
+	ids = append([]string{}, ids...)
+	sort.Strings(ids)
+	return ids
+}
+
+func getQuestion(t *testing.T, store repository.Store, id int) *types.Question {
+	t.Helper()
+	question, err := store.GetQuestion(context.Background(), strconv.Itoa(id))
+	if err != nil {
+		t.Fatalf("GetQuestion(%d): %v", id, err)
+	}
+	return question
+}
+
+func getState(t *testing.T, store repository.Store, id int) types.QuestionStateDB {
+	t.Helper()
+	states, err := store.GetAnsweredQuestions()
+	if err != nil {
+		t.Fatalf("GetAnsweredQuestions: %v", err)
+	}
+	for _, state := range states {
+		if state.QuestionID == id {
+			return state
+		}
+	}
+	t.Fatalf("no stored state for question %d", id)
+	return types.QuestionStateDB{}
+}
+
+func upsert(t *testing.T, store repository.Store, question *types.Question) {
+	t.Helper()
+	if err := store.UpsertQuestion(context.Background(), question); err != nil {
+		t.Fatalf("UpsertQuestion(%d): %v", question.ID, err)
+	}
+}
+
+func record(t *testing.T, store repository.Store, event types.AnswerEvent) {
+	t.Helper()
+	if err := store.RecordAnswer(context.Background(), event); err != nil {
+		t.Fatalf("RecordAnswer(%d): %v", event.QuestionID, err)
+	}
+}
+
+func history(t *testing.T, store repository.Store) []types.AnswerEvent {
+	t.Helper()
+	events, err := store.GetAnswerHistory(context.Background())
+	if err != nil {
+		t.Fatalf("GetAnswerHistory: %v", err)
+	}
+	return events
+}
+
+func testUpsertRoundTrip(t *testing.T, opener Opener) {
+	store := open(t, opener, nsAlice)
+	upsert(t, store, newQuestion(7, "a", "c"))
+	upsert(t, store, newQuestion(12, "b"))
+
+	question := getQuestion(t, store, 7)
+	if question.ID != 7 {
+		t.Errorf("ID = %d, want 7", question.ID)
+	}
+	if question.AnsweredState != types.AnsweredTrue {
+		t.Errorf("AnsweredState = %v, want %v", question.AnsweredState, types.AnsweredTrue)
+	}
+	if got := markedIDs(question); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("marked answers = %v, want [a c]", got)
+	}
+
+	state := getState(t, store, 12)
+	if state.AnsweredState != types.AnsweredFalse {
+		t.Errorf("AnsweredState = %v, want %v", state.AnsweredState, types.AnsweredFalse)
+	}
+	if got := sorted(state.MarkedAnswers); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("MarkedAnswers = %v, want [b]", got)
+	}
+
+	states, err := store.GetAnsweredQuestions()
+	if err != nil {
+		t.Fatalf("GetAnsweredQuestions: %v", err)
+	}
+	if len(states) != 2 {
+		t.Errorf("got %d states, want 2", len(states))
+	}
+}
+
+func testUpsertOverwrites(t *testing.T, opener Opener) {
+	store := open(t, opener, nsAlice)
+	upsert(t, store, newQuestion(3, "a", "c"))
+	upsert(t, store, newQuestion(3, "b"))
+
+	question := getQuestion(t, store, 3)
+	if got := markedIDs(question); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("marked answers = %v, want [b]", got)
+	}
+	if question.AnsweredState != types.AnsweredFalse {
+		t.Errorf("AnsweredState = %v, want %v", question.AnsweredState, types.AnsweredFalse)
+	}
+
+	upsert(t, store, newQuestion(3))
+	question = getQuestion(t, store, 3)
+	if got := markedIDs(question); len(got) != 0 {
+		t.Errorf("marked answers = %v, want none", got)
+	}
+	if question.AnsweredState != types.AnsweredUnknown {
+		t.Errorf("AnsweredState = %v, want %v", question.AnsweredState, types.AnsweredUnknown)
+	}
+}
+
+func testImportantFlag(t *testing.T, opener Opener) {
+	store := open(t, opener, nsAlice)
+	question := newQuestion(5, "a")
+	question.SetIsImportant(true)
+	upsert(t, store, question)
+
+	if !getQuestion(t, store, 5).GetIsImportant() {
+		t.Errorf("important flag was not stored")
+	}
+	if !getState(t, store, 5).Important {
+		t.Errorf("important flag missing from the stored state")
+	}
+
+	question.SetIsImportant(false)
+	upsert(t, store, question)
+	stored := getQuestion(t, store, 5)
+	if stored.GetIsImportant() {
+		t.Errorf("important flag was not cleared")
+	}
+	if got := markedIDs(stored); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("marked answers = %v, want [a]", got)
+	}
+}
+
+func testTagsAndNote(t *testing.T, opener Opener) {
+	store := open(t, opener, nsAlice)
+	question := newQuestion(6, "a")
+	question.SetTags([]string{"grep", "Exam Trap"})
+	question.SetNote("check the -E option\nand -P")
+	upsert(t, store, question)
+	record(t, store, attempt(6, "s1", start, "b"))
+	record(t, store, types.AnswerEvent{Kind: types.AnswerEventReset, Timestamp: start.Add(time.Minute), QuestionID: 6})
+	closeStore(t, store)
+
+	store = open(t, opener, nsAlice)
+	stored := getQuestion(t, store, 6)
+	if got := stored.GetTags(); !reflect.DeepEqual(got, []string{"exam-trap", "grep"}) {
+		t.Errorf("tags = %v, want [exam-trap grep]", got)
+	}
+	if got := stored.GetNote(); got != "check the -E option\nand -P" {
+		t.Errorf("note = %q", got)
+	}
+	if state := getState(t, store, 6); !reflect.DeepEqual(state.Tags, []string{"exam-trap", "grep"}) || state.Note == "" {
+		t.Errorf("tags and note missing from the stored state: %+v", state)
+	}
+
+	question.SetTags(nil)
+	question.SetNote("")
+	upsert(t, store, question)
+	if state := getState(t, store, 6); len(state.Tags) != 0 || state.Note != "" {
+		t.Errorf("tags and note were not cleared: %+v", state)
+	}
+}
+
+func newFillInQuestion(id int, typed string) *types.Question {
+	question := &types.Question{
+		ID:     id,
+		Text:   fmt.Sprintf("Question %d", id),
+		Kind:   types.QuestionKindFillIn,
+		FillIn: &types.FillIn{Accepted: []string{"ls -l"}},
+	}
+	question.AnswerFillIn(typed)
+	return question
+}
+
+func testTypedAnswer(t *testing.T, opener Opener) {
+	store := open(t, opener, nsAlice)
+	question := newFillInQuestion(7, "LS  -l")
+	if question.AnsweredState != types.AnsweredTrue {
+		t.Fatalf("answer %q was not accepted", question.GetTypedAnswer())
+	}
+	upsert(t, store, question)
+
+	wrong := newFillInQuestion(8, "ls -a")
+	event := types.NewAnswerEvent(wrong, "", "s1", time.Second)
+	event.Timestamp = start
+	record(t, store, event)
+	closeStore(t, store)
+
+	store = open(t, opener, nsAlice)
+	if got := getQuestion(t, store, 7).GetTypedAnswer(); got != "LS  -l" {
+		t.Errorf("typed answer = %q, want %q", got, "LS  -l")
+	}
+	if state := getState(t, store, 8); state.TypedAnswer != "ls -a" || state.AnsweredState != types.AnsweredFalse {
+		t.Errorf("state after the attempt = %+v, want typed answer %q answered wrong", state, "ls -a")
+	}
+	if events := history(t, store); len(events) != 1 || events[0].TypedAnswer != "ls -a" {
+		t.Errorf("history = %+v, want one attempt with the typed answer", events)
+	}
+
+	record(t, store, types.AnswerEvent{Kind: types.AnswerEventReset, Timestamp: start.Add(time.Minute), QuestionID: 8})
+	if state := getState(t, store, 8); state.TypedAnswer != "" {
+		t.Errorf("typed answer %q was not reset", state.TypedAnswer)
+	}
+}
+
+func testUpsertKeepsHistoryFields(t *testing.T, opener Opener) {
+	store := open(t, opener, nsAlice)
+	record(t, store, attempt(9, "s1", start, "a", "c"))
+	before := getState(t, store, 9)
+
+	question := newQuestion(9, "a", "c")
+	question.SetIsImportant(true)
+	upsert(t, store, question)
+
+	after := getState(t, store, 9)
+	if after.Attempts != 1 {
+		t.Errorf("Attempts = %d, want 1", after.Attempts)
+	}
+	if !after.LastAnsweredAt.Equal(before.LastAnsweredAt) {
+		t.Errorf("LastAnsweredAt = %v, want %v", after.LastAnsweredAt, before.LastAnsweredAt)
+	}
+	if !after.Review.Due.Equal(before.Review.Due) || after.Review.Repetitions != before.Review.Repetitions {
+		t.Errorf("Review = %+v, want %+v", after.Review, before.Review)
+	}
+	if !after.Important {
+		t.Errorf("important flag was not stored")
+	}
+}
+
+func testGetQuestionNotFound(t *testing.T, opener Opener) {
+	store := open(t, opener, nsAlice)
+	_, err := store.GetQuestion(context.Background(), "42")
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetQuestion of a missing question: err = %v, want ErrNotFound", err)
+	}
+	if _, err := store.GetQuestion(context.Background(), "not-a-number"); err == nil {
+		t.Errorf("GetQuestion with an invalid id: want an error")
+	}
+}
+
+func testDeleteQuestion(t *testing.T, opener Opener) {
+	ctx := context.Background()
+	store := open(t, opener, nsAlice)
+	record(t, store, attempt(4, "s1", start, "a"))
+	upsert(t, store, newQuestion(8, "a", "c"))
+
+	if err := store.DeleteQuestion(ctx, "4"); err != nil {
+		t.Fatalf("DeleteQuestion: %v", err)
+	}
+	if _, err := store.GetQuestion(ctx, "4"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetQuestion after delete: err = %v, want ErrNotFound", err)
+	}
+	if err := store.DeleteQuestion(ctx, "4"); err != nil {
+		t.Errorf("deleting a missing question: %v", err)
+	}
+	getQuestion(t, store, 8)
+
+	if events := history(t, store); len(events) != 1 {
+		t.Errorf("history has %d events after delete, want 1", len(events))
+	}
+}
+
+func testRecordAnswer(t *testing.T, opener Opener) {
+	store := open(t, opener, nsAlice)
+	event := attempt(11, "s1", start, "b", "d")
+	event.CertificationID = nsAlice.CertificationID
+	record(t, store, event)
+
+	state := getState(t, store, 11)
+	if got := sorted(state.MarkedAnswers); !reflect.DeepEqual(got, []string{"b", "d"}) {
+		t.Errorf("MarkedAnswers = %v, want [b d]", got)
+	}
+	if state.AnsweredState != types.AnsweredFalse {
+		t.Errorf("AnsweredState = %v, want %v", state.AnsweredState, types.AnsweredFalse)
+	}
+	if state.Attempts != 1 {
+		t.Errorf("Attempts = %d, want 1", state.Attempts)
+	}
+	if !state.LastAnsweredAt.Equal(start) {
+		t.Errorf("LastAnsweredAt = %v, want %v", state.LastAnsweredAt, start)
+	}
+
+	events := history(t, store)
+	if len(events) != 1 {
+		t.Fatalf("history has %d events, want 1", len(events))
+	}
+	got := events[0]
+	if got.ID == "" {
+		t.Errorf("event was stored without an ID")
+	}
+	if got.Kind != types.AnswerEventAttempt || got.QuestionID != 11 || got.SessionID != "s1" ||
+		got.Result != types.AnsweredFalse || got.TimeSpent != 30*time.Second || !got.Timestamp.Equal(start) {
+		t.Errorf("event = %+v, want %+v", got, event)
+	}
+	if ids := sorted(got.AnswerIDs); !reflect.DeepEqual(ids, []string{"b", "d"}) {
+		t.Errorf("AnswerIDs = %v, want [b d]", ids)
+	}
+}
+
+func testReset(t *testing.T, opener Opener) {
+	store := open(t, opener, nsAlice)
+	record(t, store, attempt(2, "s1", start, "a", "c"))
+	reset := types.NewResetEvent(newQuestion(2), "", "s1")
+	reset.Timestamp = start.Add(time.Minute)
+	record(t, store, reset)
+
+	state := getState(t, store, 2)
+	if len(state.MarkedAnswers) != 0 {
+		t.Errorf("MarkedAnswers = %v, want none", state.MarkedAnswers)
+	}
+	if state.AnsweredState != types.AnsweredUnknown {
+		t.Errorf("AnsweredState = %v, want %v", state.AnsweredState, types.AnsweredUnknown)
+	}
+	if state.Attempts != 1 {
+		t.Errorf("Attempts = %d, want 1", state.Attempts)
+	}
+	if !state.LastAnsweredAt.Equal(start) || !state.ResetAt.Equal(reset.Timestamp) {
+		t.Errorf("LastAnsweredAt = %v, ResetAt = %v, want %v and %v", state.LastAnsweredAt, state.ResetAt, start, reset.Timestamp)
+	}
+	if !state.Review.Due.IsZero() || state.Review.Repetitions != 0 {
+		t.Errorf("Review = %+v, want the zero schedule", state.Review)
+	}
+	if got := markedIDs(getQuestion(t, store, 2)); len(got) != 0 {
+		t.Errorf("marked answers = %v, want none", got)
+	}
+
+	events := history(t, store)
+	if len(events) != 2 {
+		t.Fatalf("history has %d events, want 2", len(events))
+	}
+	if events[0].Kind != types.AnswerEventAttempt || events[1].Kind != types.AnswerEventReset {
+		t.Errorf("history kinds = %s, %s, want attempt, reset", events[0].Kind, events[1].Kind)
+	}
+}
+
+func testReviewSchedule(t *testing.T, opener Opener) {
+	store := open(t, opener, nsAlice)
+	record(t, store, attempt(6, "s1", start, "a", "c"))
+	state := getState(t, store, 6)
+	if want := start.AddDate(0, 0, 1); state.Review.Repetitions != 1 || !state.Review.Due.Equal(want) {
+		t.Errorf("after first answer: Review = %+v, want 1 repetition due %v", state.Review, want)
+	}
+
+	// further answers in the same session do not move the schedule
+	record(t, store, attempt(6, "s1", start.Add(time.Minute), "a", "c"))
+	if got := getState(t, store, 6).Review; got.Repetitions != 1 {
+		t.Errorf("same session: Repetitions = %d, want 1", got.Repetitions)
+	}
+
+	next := start.AddDate(0, 0, 1)
+	record(t, store, attempt(6, "s2", next, "a", "c"))
+	state = getState(t, store, 6)
+	if want := next.AddDate(0, 0, 6); state.Review.Repetitions != 2 || !state.Review.Due.Equal(want) {
+		t.Errorf("after second session: Review = %+v, want 2 repetitions due %v", state.Review, want)
+	}
+	if state.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", state.Attempts)
+	}
+
+	record(t, store, attempt(6, "s3", next.AddDate(0, 0, 6), "b"))
+	state = getState(t, store, 6)
+	if state.Review.Lapses != 1 || state.Review.IntervalDays != 1 {
+		t.Errorf("after a wrong answer: Review = %+v, want 1 lapse and a 1 day interval", state.Review)
+	}
+}
+
+func testExamResults(t *testing.T, opener Opener) {
+	ctx := context.Background()
+	store := open(t, opener, nsAlice)
+
+	results, err := store.GetExamResults(ctx)
+	if err != nil {
+		t.Fatalf("GetExamResults: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("got %d exam results from an empty store", len(results))
+	}
+
+	late := &types.ExamResult{
+		ID:              "late",
+		CertificationID: nsAlice.CertificationID,
+		StartedAt:       start.Add(48 * time.Hour),
+		FinishedAt:      start.Add(49 * time.Hour),
+		TimeLimit:       90 * time.Minute,
+		QuestionIDs:     []int{1, 2},
+		MarkedAnswers:   map[int][]string{1: {"a", "c"}, 2: {"b"}},
+		Correct:         1,
+		Total:           2,
+		ScaledScore:     431,
+		Testsets:        []types.TestsetScore{{TestsetID: "t1", TestsetName: "Testset 1", Correct: 1, Total: 2}},
+	}
+	early := &types.ExamResult{
+		ID:              "early",
+		CertificationID: nsAlice.CertificationID,
+		StartedAt:       start,
+		FinishedAt:      start.Add(time.Hour),
+		TimedOut:        true,
+		Total:           1,
+		ScaledScore:     200,
+	}
+	for _, result := range []*types.ExamResult{late, early} {
+		if err := store.SaveExamResult(ctx, result); err != nil {
+			t.Fatalf("SaveExamResult(%s): %v", result.ID, err)
+		}
+	}
+
+	results, err = store.GetExamResults(ctx)
+	if err != nil {
+		t.Fatalf("GetExamResults: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d exam results, want 2", len(results))
+	}
+	if results[0].ID != "early" || results[1].ID != "late" {
+		t.Errorf("results are not ordered by start time: %s, %s", results[0].ID, results[1].ID)
+	}
+	got := results[1]
+	if !got.StartedAt.Equal(late.StartedAt) || !got.FinishedAt.Equal(late.FinishedAt) {
+		t.Errorf("times = %v - %v, want %v - %v", got.StartedAt, got.FinishedAt, late.StartedAt, late.FinishedAt)
+	}
+	got.StartedAt, got.FinishedAt = late.StartedAt, late.FinishedAt
+	if !reflect.DeepEqual(got, *late) {
+		t.Errorf("exam result = %+v, want %+v", got, *late)
+	}
+	if !results[0].TimedOut {
+		t.Errorf("TimedOut was not stored")
+	}
+
+	// saving a result again replaces it
+	early.ScaledScore = 250
+	if err := store.SaveExamResult(ctx, early); err != nil {
+		t.Fatalf("SaveExamResult: %v", err)
+	}
+	results, err = store.GetExamResults(ctx)
+	if err != nil {
+		t.Fatalf("GetExamResults: %v", err)
+	}
+	if len(results) != 2 || results[0].ScaledScore != 250 {
+		t.Errorf("saving a result again did not replace it: %+v", results)
+	}
+}
+
+func testProgress(t *testing.T, opener Opener) {
+	ctx := context.Background()
+	store := open(t, opener, nsAlice)
+
+	summary, err := store.GetProgressSummary(ctx)
+	if err != nil {
+		t.Fatalf("GetProgressSummary: %v", err)
+	}
+	if *summary != (types.ProgressSummary{}) {
+		t.Errorf("summary of an empty store = %+v", *summary)
+	}
+
+	record(t, store, attempt(1, "s1", start, "a", "c"))
+	record(t, store, attempt(2, "s1", start.Add(time.Minute), "b"))
+	record(t, store, attempt(2, "s2", start.AddDate(0, 0, 1), "a", "c"))
+	important := newQuestion(3)
+	important.SetIsImportant(true)
+	upsert(t, store, important)
+
+	summary, err = store.GetProgressSummary(ctx)
+	if err != nil {
+		t.Fatalf("GetProgressSummary: %v", err)
+	}
+	want := types.ProgressSummary{Questions: 3, Correct: 2, Incorrect: 0, Important: 1, Attempts: 3}
+	if *summary != want {
+		t.Errorf("summary = %+v, want %+v", *summary, want)
+	}
+
+	days, err := store.GetDailyProgress(ctx)
+	if err != nil {
+		t.Fatalf("GetDailyProgress: %v", err)
+	}
+	wantDays := []types.DailyProgress{
+		{Day: "2026-03-02", Attempts: 2, Correct: 1, Incorrect: 1, TimeSpent: time.Minute},
+		{Day: "2026-03-03", Attempts: 1, Correct: 1, TimeSpent: 30 * time.Second},
+	}
+	if !reflect.DeepEqual(days, wantDays) {
+		t.Errorf("daily progress = %+v, want %+v", days, wantDays)
+	}
+}
+
+func testReopen(t *testing.T, opener Opener) {
+	store := open(t, opener, nsAlice)
+	question := newQuestion(21, "a", "c")
+	question.SetIsImportant(true)
+	upsert(t, store, question)
+	record(t, store, attempt(22, "s1", start, "b"))
+	closeStore(t, store)
+
+	store = open(t, opener, nsAlice)
+	stored := getQuestion(t, store, 21)
+	if got := markedIDs(stored); !reflect.DeepEqual(got, []string{"a", "c"}) || !stored.GetIsImportant() {
+		t.Errorf("after reopen: marked answers = %v, important = %v", got, stored.GetIsImportant())
+	}
+	if state := getState(t, store, 22); state.Attempts != 1 || state.AnsweredState != types.AnsweredFalse {
+		t.Errorf("after reopen: state = %+v", state)
+	}
+	if events := history(t, store); len(events) != 1 {
+		t.Errorf("after reopen: history has %d events, want 1", len(events))
+	}
+}
+
+func testNamespaceIsolation(t *testing.T, opener Opener) {
+	ctx := context.Background()
+	store := open(t, opener, nsAlice)
+	upsert(t, store, newQuestion(1, "a", "c"))
+	record(t, store, attempt(2, "s1", start, "b"))
+	if err := store.SaveExamResult(ctx, &types.ExamResult{ID: "exam", CertificationID: nsAlice.CertificationID, StartedAt: start}); err != nil {
+		t.Fatalf("SaveExamResult: %v", err)
+	}
+	closeStore(t, store)
+
+	for _, ns := range []repository.Namespace{nsBob, nsCert} {
+		store := open(t, opener, ns)
+		if _, err := store.GetQuestion(ctx, "1"); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("%s: GetQuestion of another namespace's question: err = %v, want ErrNotFound", ns, err)
+		}
+		states, err := store.GetAnsweredQuestions()
+		if err != nil {
+			t.Fatalf("GetAnsweredQuestions: %v", err)
+		}
+		if len(states) != 0 {
+			t.Errorf("%s: sees %d question states of another namespace", ns, len(states))
+		}
+		if events := history(t, store); len(events) != 0 {
+			t.Errorf("%s: sees %d answer events of another namespace", ns, len(events))
+		}
+		results, err := store.GetExamResults(ctx)
+		if err != nil {
+			t.Fatalf("GetExamResults: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("%s: sees %d exam results of another namespace", ns, len(results))
+		}
+		upsert(t, store, newQuestion(1, "b"))
+		closeStore(t, store)
+	}
+
+	store = open(t, opener, nsAlice)
+	if got := markedIDs(getQuestion(t, store, 1)); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("progress was overwritten by another namespace: marked answers = %v", got)
+	}
+}
+
+func testSessions(t *testing.T, opener Opener) {
+	ctx := context.Background()
+	store := open(t, opener, nsAlice)
+	if _, err := store.GetLatestSession(ctx); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetLatestSession of an empty store: err = %v, want ErrNotFound", err)
+	}
+
+	first := types.SessionState{
+		ID:                   "first",
+		CertificationID:      nsAlice.CertificationID,
+		Mode:                 types.SessionModeTestset,
+		TestsetID:            "admin_1",
+		FilterCorrect:        true,
+		QuestionIDs:          []int{3, 1, 2},
+		CurrentQuestionIndex: 1,
+		StartedAt:            start,
+		UpdatedAt:            start.Add(time.Hour),
+	}
+	second := types.SessionState{
+		ID:              "second",
+		CertificationID: nsAlice.CertificationID,
+		Mode:            types.SessionModeRandom,
+		QuestionIDs:     []int{9, 8},
+		StartedAt:       start.Add(2 * time.Hour),
+		UpdatedAt:       start.Add(2 * time.Hour),
+	}
+	for _, session := range []types.SessionState{first, second} {
+		if err := store.SaveSession(ctx, session); err != nil {
+			t.Fatalf("SaveSession(%s): %v", session.ID, err)
+		}
+	}
+
+	latest, err := store.GetLatestSession(ctx)
+	if err != nil {
+		t.Fatalf("GetLatestSession: %v", err)
+	}
+	if latest.ID != "second" {
+		t.Errorf("latest session = %s, want second", latest.ID)
+	}
+
+	// continuing the first session makes it the latest one again
+	first.CurrentQuestionIndex = 2
+	first.UpdatedAt = start.Add(3 * time.Hour)
+	if err := store.SaveSession(ctx, first); err != nil {
+		t.Fatalf("SaveSession: %v", err)
+	}
+	latest, err = store.GetLatestSession(ctx)
+	if err != nil {
+		t.Fatalf("GetLatestSession: %v", err)
+	}
+	if !latest.StartedAt.Equal(first.StartedAt) || !latest.UpdatedAt.Equal(first.UpdatedAt) {
+		t.Errorf("times = %v, %v, want %v, %v", latest.StartedAt, latest.UpdatedAt, first.StartedAt, first.UpdatedAt)
+	}
+	latest.StartedAt, latest.UpdatedAt = first.StartedAt, first.UpdatedAt
+	if !reflect.DeepEqual(*latest, first) {
+		t.Errorf("latest session = %+v, want %+v", *latest, first)
+	}
+	closeStore(t, store)
+
+	store = open(t, opener, nsBob)
+	if _, err := store.GetLatestSession(ctx); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetLatestSession of another namespace: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testTransfer(t *testing.T, opener Opener) {
+	ctx := context.Background()
+	for _, ns := range []repository.Namespace{nsAlice, nsCert} {
+		store := open(t, opener, ns)
+		upsert(t, store, newQuestion(1, "a"))
+		closeStore(t, store)
+	}
+	store := open(t, opener, nsBob)
+	if err := store.SaveExamResult(ctx, &types.ExamResult{ID: "exam", StartedAt: start}); err != nil {
+		t.Fatalf("SaveExamResult: %v", err)
+	}
+
+	namespaces, err := store.GetNamespaces(ctx)
+	if err != nil {
+		t.Fatalf("GetNamespaces: %v", err)
+	}
+	if want := []repository.Namespace{nsAlice, nsCert, nsBob}; !reflect.DeepEqual(namespaces, want) {
+		t.Errorf("namespaces = %v, want %v", namespaces, want)
+	}
+
+	state := types.QuestionStateDB{
+		QuestionID:     4,
+		MarkedAnswers:  []string{"b", "c"},
+		AnsweredState:  types.AnsweredFalse,
+		Important:      true,
+		Attempts:       3,
+		LastAnsweredAt: start,
+		ResetAt:        start.Add(-time.Hour),
+		Review:         types.ReviewState{EaseFactor: 2.18, IntervalDays: 1, Lapses: 1, Due: start.AddDate(0, 0, 1), LastSessionID: "s3"},
+	}
+	if err := store.PutQuestionState(ctx, state); err != nil {
+		t.Fatalf("PutQuestionState: %v", err)
+	}
+	got := getState(t, store, 4)
+	if !got.LastAnsweredAt.Equal(state.LastAnsweredAt) || !got.ResetAt.Equal(state.ResetAt) || !got.Review.Due.Equal(state.Review.Due) {
+		t.Errorf("times = %v, %v, %v, want %v, %v, %v", got.LastAnsweredAt, got.ResetAt, got.Review.Due,
+			state.LastAnsweredAt, state.ResetAt, state.Review.Due)
+	}
+	got.LastAnsweredAt, got.ResetAt, got.Review.Due = state.LastAnsweredAt, state.ResetAt, state.Review.Due
+	got.MarkedAnswers = sorted(got.MarkedAnswers)
+	if !reflect.DeepEqual(got, state) {
+		t.Errorf("state = %+v, want %+v", got, state)
+	}
+
+	// appended events are history only, they do not change the state
+	events := []types.AnswerEvent{attempt(4, "s4", start.Add(time.Hour), "a", "c"), attempt(5, "s4", start.Add(2*time.Hour), "a")}
+	if err := store.AppendAnswerEvents(ctx, events); err != nil {
+		t.Fatalf("AppendAnswerEvents: %v", err)
+	}
+	if got := getState(t, store, 4); got.Attempts != 3 || got.AnsweredState != types.AnsweredFalse {
+		t.Errorf("AppendAnswerEvents changed the state: %+v", got)
+	}
+	if _, err := store.GetQuestion(ctx, "5"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("AppendAnswerEvents created a state: err = %v, want ErrNotFound", err)
+	}
+	stored := history(t, store)
+	if len(stored) != 2 || stored[0].QuestionID != 4 || stored[1].QuestionID != 5 || stored[0].ID == "" {
+		t.Fatalf("history = %+v, want the appended events", stored)
+	}
+
+	// events that are already stored are skipped, the others still appended
+	again := append(stored, attempt(5, "s5", start.Add(3*time.Hour), "b"))
+	if err := store.AppendAnswerEvents(ctx, again); err != nil {
+		t.Fatalf("AppendAnswerEvents with stored events: %v", err)
+	}
+	if stored := history(t, store); len(stored) != 3 || stored[2].SessionID != "s5" {
+		t.Errorf("history = %+v, want the stored events once and the new one", stored)
+	}
+}
+
+// testTransferIntoTwoNamespaces appends the same events to two namespaces,
+// as importing one progress file as two profiles does.
+func testTransferIntoTwoNamespaces(t *testing.T, opener Opener) {
+	ctx := context.Background()
+	events := []types.AnswerEvent{attempt(1, "s1", start, "a"), attempt(2, "s1", start.Add(time.Minute), "b")}
+	events[0].ID, events[1].ID = "e1", "e2"
+	for _, ns := range []repository.Namespace{nsAlice, nsBob, nsCert} {
+		store := open(t, opener, ns)
+		if err := store.AppendAnswerEvents(ctx, events); err != nil {
+			t.Fatalf("AppendAnswerEvents to %s: %v", ns, err)
+		}
+		closeStore(t, store)
+	}
+	for _, ns := range []repository.Namespace{nsAlice, nsBob, nsCert} {
+		store := open(t, opener, ns)
+		stored := history(t, store)
+		if len(stored) != 2 || stored[0].ID != "e1" || stored[1].ID != "e2" {
+			t.Errorf("history of %s = %+v, want the events e1 and e2", ns, stored)
+		}
+		closeStore(t, store)
+	}
+}
+
+func testConcurrentAccess(t *testing.T, opener Opener) {
+	const (
+		workers  = 8
+		attempts = 10
+	)
+	ctx := context.Background()
+	store := open(t, opener, nsAlice)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*attempts*3)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(questionID int) {
+			defer wg.Done()
+			for i := 0; i < attempts; i++ {
+				question := newQuestion(questionID, "a", "c")
+				question.SetIsImportant(i%2 == 0)
+				if err := store.UpsertQuestion(ctx, question); err != nil {
+					errs <- err
+				}
+				event := attempt(questionID, fmt.Sprintf("s%d", i), start.Add(time.Duration(i)*time.Second), "a", "c")
+				if err := store.RecordAnswer(ctx, event); err != nil {
+					errs <- err
+				}
+				if _, err := store.GetAnsweredQuestions(); err != nil {
+					errs <- err
+				}
+			}
+		}(w + 1)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent access: %v", err)
+	}
+
+	states, err := store.GetAnsweredQuestions()
+	if err != nil {
+		t.Fatalf("GetAnsweredQuestions: %v", err)
+	}
+	if len(states) != workers {
+		t.Errorf("got %d question states, want %d", len(states), workers)
+	}
+	for _, state := range states {
+		if state.Attempts != attempts {
+			t.Errorf("question %d: Attempts = %d, want %d", state.QuestionID, state.Attempts, attempts)
+		}
+	}
+	if events := history(t, store); len(events) != workers*attempts {
+		t.Errorf("history has %d events, want %d", len(events), workers*attempts)
+	}
+}
+
+// testConcurrentAnswersToOneQuestion records answers to the same question
+// from several goroutines, like clients sharing a namespace. No answer may be
+// lost from the derived state.
+func testConcurrentAnswersToOneQuestion(t *testing.T, opener Opener) {
+	const (
+		workers  = 4
+		attempts = 5
+	)
+	ctx := context.Background()
+	store := open(t, opener, nsAlice)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*attempts)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < attempts; i++ {
+				event := attempt(1, "s1", start.Add(time.Duration(worker*attempts+i)*time.Second), "a", "c")
+				if err := store.RecordAnswer(ctx, event); err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent answers: %v", err)
+	}
+
+	state := getState(t, store, 1)
+	if state.Attempts != workers*attempts {
+		t.Errorf("Attempts = %d, want %d", state.Attempts, workers*attempts)
+	}
+	if events := history(t, store); len(events) != workers*attempts {
+		t.Errorf("history has %d events, want %d", len(events), workers*attempts)
+	}
+}
+
+func testClose(t *testing.T, opener Opener) {
+	ctx := context.Background()
+	store, err := opener(nsAlice)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", nsAlice, err)
+	}
+	upsert(t, store, newQuestion(1, "a"))
+	closeStore(t, store)
+
+	if err := store.UpsertQuestion(ctx, newQuestion(2, "a")); err == nil {
+		t.Errorf("UpsertQuestion after Close: want an error")
+	}
+	if err := store.RecordAnswer(ctx, attempt(2, "s1", start, "a")); err == nil {
+		t.Errorf("RecordAnswer after Close: want an error")
+	}
+	if _, err := store.GetAnsweredQuestions(); err == nil {
+		t.Errorf("GetAnsweredQuestions after Close: want an error")
+	}
+	if _, err := store.GetQuestion(ctx, "1"); err == nil {
+		t.Errorf("GetQuestion after Close: want an error")
+	}
+
+	// a second Close may fail but must not panic
+	store.Close()
+}
@@ -0,0 +1,19 @@
+package repository
+
+import "github.com/SqiSch/lpic-cli/internal/types"
+
+// questionFromState rebuilds the stored part of a question: its ID, answered
+// state, important flag, tags, note and the marked or typed answer.
+func questionFromState(state types.QuestionStateDB) *types.Question {
+	question := &types.Question{ID: state.QuestionID, AnsweredState: state.AnsweredState}
+	question.SetIsImportant(state.Important)
+	question.SetTags(state.Tags)
+	question.SetNote(state.Note)
+	question.SetTypedAnswer(state.TypedAnswer)
+	for _, answerID := range state.MarkedAnswers {
+		answer := &types.Answer{AnswerID: answerID}
+		answer.SetIsMarked(true)
+		question.Answers = append(question.Answers, answer)
+	}
+	return question
+}
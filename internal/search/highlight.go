@@ -0,0 +1,84 @@
+package search
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Highlight returns text with every word matching one of terms passed through
+// mark and the text between them through plain, e.g. to escape it for the
+// terminal. A nil plain keeps the text as it is.
+func Highlight(text string, terms []string, mark, plain func(string) string) string {
+	if plain == nil {
+		plain = func(s string) string { return s }
+	}
+	var b strings.Builder
+	last := 0
+	for _, token := range tokenize(text) {
+		if !matches(token.word, terms) {
+			continue
+		}
+		b.WriteString(plain(text[last:token.start]))
+		b.WriteString(mark(text[token.start:token.end]))
+		last = token.end
+	}
+	b.WriteString(plain(text[last:]))
+	return b.String()
+}
+
+// Excerpt returns about width characters of text around the first word
+// matching one of terms on a single line. Cut off ends are marked with "…".
+func Excerpt(text string, terms []string, width int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+
+	center := 0
+	for _, token := range tokenize(text) {
+		if matches(token.word, terms) {
+			center = token.start
+			break
+		}
+	}
+	start := center
+	for n := 0; start > 0 && n < width/3; n++ {
+		_, size := utf8.DecodeLastRuneInString(text[:start])
+		start -= size
+	}
+	end := start
+	for n := 0; end < len(text) && n < width; n++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+
+	excerpt := text[start:end]
+	if start > 0 {
+		excerpt = "…" + excerpt
+	}
+	if end < len(text) {
+		excerpt += "…"
+	}
+	return excerpt
+}
+
+// Contains reports whether text has a word matching one of terms.
+func Contains(text string, terms []string) bool {
+	for _, token := range tokenize(text) {
+		if matches(token.word, terms) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether word is equal to or starts with one of terms, the
+// way Search matches them.
+func matches(word string, terms []string) bool {
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
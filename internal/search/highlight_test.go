@@ -0,0 +1,52 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	mark := func(s string) string { return "[" + s + "]" }
+	tests := []struct {
+		text  string
+		terms []string
+		plain func(string) string
+		want  string
+	}{
+		{"Use chmod or CHMOD.", []string{"chmod"}, nil, "Use [chmod] or [CHMOD]."},
+		{"chmodding and chown", []string{"chmod", "chown"}, nil, "[chmodding] and [chown]"},
+		{"no match", []string{"chmod"}, nil, "no match"},
+		{"a <b> chmod", []string{"chmod"}, strings.ToUpper, "A <B> [chmod]"},
+	}
+	for _, tt := range tests {
+		if got := Highlight(tt.text, tt.terms, mark, tt.plain); got != tt.want {
+			t.Errorf("Highlight(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	text := "The first words fill the line before the word chmod shows up\nand more words follow it."
+	tests := []struct {
+		terms []string
+		width int
+		want  string
+	}{
+		{[]string{"chmod"}, 200, "The first words fill the line before the word chmod shows up and more words follow it."},
+		{[]string{"chmod"}, 30, "… the word chmod shows up and m…"},
+		{[]string{"first"}, 20, "The first words fill…"},
+		{[]string{"missing"}, 20, "The first words fill…"},
+		{[]string{"follow"}, 30, "…ore words follow it."},
+	}
+	for _, tt := range tests {
+		if got := Excerpt(text, tt.terms, tt.width); got != tt.want {
+			t.Errorf("Excerpt(%v, %d) = %q, want %q", tt.terms, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	if !Contains("Run CHMODDING now", []string{"chmod"}) || Contains("Run chown", []string{"chmod"}) {
+		t.Error("Contains does not match words by prefix")
+	}
+}
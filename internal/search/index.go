@@ -0,0 +1,249 @@
+// Package search implements the full-text search over the question text,
+// answers and explanations of a certification.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+// Field is the part of a question a word was found in.
+type Field int
+
+const (
+	FieldQuestion Field = iota
+	FieldAnswer
+	FieldExplanation
+)
+
+func (f Field) String() string {
+	switch f {
+	case FieldQuestion:
+		return "question"
+	case FieldAnswer:
+		return "answer"
+	case FieldExplanation:
+		return "explanation"
+	}
+	return "unknown"
+}
+
+// MarshalText makes fields readable in the JSON output of "client search".
+func (f Field) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// fieldWeights rank a match in the question text above one in the answers
+// and that above one in the explanation.
+var fieldWeights = [...]float64{
+	FieldQuestion:    3,
+	FieldAnswer:      2,
+	FieldExplanation: 1,
+}
+
+// prefixWeight scales the score of words that only start with a query term,
+// so "chmod" ranks an exact match above "chmodding".
+const prefixWeight = 0.5
+
+type posting struct {
+	questionID int
+	field      Field
+	count      int
+}
+
+// Index is an inverted index from the words of a certification's questions
+// to the questions and fields they occur in.
+type Index struct {
+	certSet  *types.CertificationSet
+	postings map[string][]posting
+	terms    []string         // sorted keys of postings for prefix lookups
+	testsets map[int][]string // question ID to the testsets containing it
+}
+
+// Result is a question matching every term of a query.
+type Result struct {
+	Question *types.Question
+	Score    float64
+	// Fields are the fields containing at least one of the terms.
+	Fields []Field
+	// Testsets are the IDs of the testsets containing the question.
+	Testsets []string
+}
+
+// NewIndex indexes the questions of certSet.
+func NewIndex(certSet *types.CertificationSet) *Index {
+	idx := &Index{
+		certSet:  certSet,
+		postings: make(map[string][]posting),
+		testsets: make(map[int][]string),
+	}
+	for _, question := range certSet.Questions {
+		idx.add(question.ID, FieldQuestion, question.Text)
+		idx.add(question.ID, FieldAnswer, strings.Join(question.SolutionTexts(), "\n"))
+		idx.add(question.ID, FieldExplanation, question.Explanation)
+	}
+	for term := range idx.postings {
+		idx.terms = append(idx.terms, term)
+	}
+	sort.Strings(idx.terms)
+
+	for id, testset := range certSet.Testsets {
+		for _, questionID := range testset.QuestionsIds {
+			idx.testsets[questionID] = append(idx.testsets[questionID], id)
+		}
+	}
+	for _, ids := range idx.testsets {
+		sort.Strings(ids)
+	}
+	return idx
+}
+
+func (idx *Index) add(questionID int, field Field, text string) {
+	counts := make(map[string]int)
+	for _, token := range tokenize(text) {
+		counts[token.word]++
+	}
+	for word, count := range counts {
+		idx.postings[word] = append(idx.postings[word], posting{questionID: questionID, field: field, count: count})
+	}
+}
+
+// Len returns the number of indexed words.
+func (idx *Index) Len() int {
+	return len(idx.terms)
+}
+
+// Search returns the questions containing every term of query, best matches
+// first. A term also matches words starting with it. The score of a match
+// grows with the number of occurrences and the field it is found in and
+// shrinks with the number of questions containing the word.
+func (idx *Index) Search(query string) []Result {
+	terms := Terms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	total := float64(len(idx.certSet.Questions))
+	scores := make(map[int]float64)
+	fields := make(map[int]map[Field]bool)
+	for i, term := range terms {
+		termScores := make(map[int]float64)
+		for _, word := range idx.matchingWords(term) {
+			postings := idx.postings[word]
+			idf := math.Log(1 + total/float64(questionCount(postings)))
+			weight := 1.0
+			if word != term {
+				weight = prefixWeight
+			}
+			for _, p := range postings {
+				// only questions matching all previous terms can still match
+				if i > 0 {
+					if _, ok := scores[p.questionID]; !ok {
+						continue
+					}
+				}
+				termScores[p.questionID] += weight * fieldWeights[p.field] * (1 + math.Log(float64(p.count))) * idf
+				if fields[p.questionID] == nil {
+					fields[p.questionID] = make(map[Field]bool)
+				}
+				fields[p.questionID][p.field] = true
+			}
+		}
+		for id, score := range termScores {
+			termScores[id] = score + scores[id]
+		}
+		scores = termScores
+		if len(scores) == 0 {
+			return nil
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		question, ok := idx.certSet.Questions[id]
+		if !ok {
+			continue
+		}
+		result := Result{Question: question, Score: score, Testsets: idx.testsets[id]}
+		for _, field := range []Field{FieldQuestion, FieldAnswer, FieldExplanation} {
+			if fields[id][field] {
+				result.Fields = append(result.Fields, field)
+			}
+		}
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Question.ID < results[j].Question.ID
+	})
+	return results
+}
+
+// matchingWords returns the indexed words equal to or starting with term.
+func (idx *Index) matchingWords(term string) []string {
+	start := sort.SearchStrings(idx.terms, term)
+	var words []string
+	for _, word := range idx.terms[start:] {
+		if !strings.HasPrefix(word, term) {
+			break
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// questionCount returns the number of distinct questions in postings.
+func questionCount(postings []posting) int {
+	seen := make(map[int]bool, len(postings))
+	for _, p := range postings {
+		seen[p.questionID] = true
+	}
+	return len(seen)
+}
+
+// Terms returns the distinct lowercased words of query in their order.
+func Terms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, token := range tokenize(query) {
+		if !seen[token.word] {
+			seen[token.word] = true
+			terms = append(terms, token.word)
+		}
+	}
+	return terms
+}
+
+type token struct {
+	word       string // lowercased
+	start, end int    // byte offsets in the text
+}
+
+// tokenize splits text into words of letters, digits and underscores, so
+// "/etc/passwd" yields etc and passwd and "--recursive" yields recursive.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{word: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{word: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
@@ -0,0 +1,136 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+func testIndex() *Index {
+	return NewIndex(&types.CertificationSet{
+		Questions: map[int]*types.Question{
+			1: {ID: 1, Text: "Which command changes the permissions of a file?", Answers: []*types.Answer{
+				{Text: "chmod", IsCorrect: true}, {Text: "chown"},
+			}},
+			2: {ID: 2, Text: "Which command changes the owner of a file?", Answers: []*types.Answer{
+				{Text: "chmod"}, {Text: "chown", IsCorrect: true},
+			}, Explanation: "chmod changes the permissions, not the owner."},
+			3: {ID: 3, Text: "Which file lists the users?", Kind: types.QuestionKindFillIn,
+				FillIn: &types.FillIn{Accepted: []string{"/etc/passwd"}}},
+			4: {ID: 4, Text: "What does chmodding a directory to 755 allow?", Answers: []*types.Answer{
+				{Text: "Everyone may list it", IsCorrect: true}, {Text: "Only root may list it"},
+			}},
+		},
+		Testsets: map[string]types.Testset{
+			"permissions": {QuestionsIds: []int{1, 2, 4}},
+			"basics":      {QuestionsIds: []int{1, 3}},
+		},
+	})
+}
+
+func resultIDs(results []Result) []int {
+	ids := make([]int, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.Question.ID)
+	}
+	return ids
+}
+
+func TestSearch(t *testing.T) {
+	idx := testIndex()
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		// question 2 has chmod in two fields; the rare prefix match in the
+		// text of question 4 outweighs the answer of question 1
+		{"chmod", []int{2, 4, 1}},
+		{"owner", []int{2}},
+		// equal scores are ordered by ID
+		{"CHOWN", []int{1, 2}},
+		{"passwd", []int{3}},
+		{"/etc/passwd", []int{3}},
+		{"ch", []int{2, 1, 4}},
+		// every term has to match
+		{"chmod owner", []int{2}},
+		{"chmod passwd", nil},
+		{"nothing", nil},
+		{"", nil},
+		{"  --  ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results := idx.Search(tt.query)
+			if got := resultIDs(results); len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			for i := 1; i < len(results); i++ {
+				if results[i].Score > results[i-1].Score {
+					t.Errorf("result %d scores %v, more than the one before", i, results[i].Score)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchResult(t *testing.T) {
+	results := testIndex().Search("chmod")
+	if len(results) == 0 {
+		t.Fatal("no results")
+	}
+	byID := make(map[int]Result)
+	for _, result := range results {
+		byID[result.Question.ID] = result
+	}
+	if got := byID[2].Fields; !reflect.DeepEqual(got, []Field{FieldAnswer, FieldExplanation}) {
+		t.Errorf("fields of question 2 = %v, want answer and explanation", got)
+	}
+	if got := byID[1].Testsets; !reflect.DeepEqual(got, []string{"basics", "permissions"}) {
+		t.Errorf("testsets of question 1 = %v, want basics and permissions", got)
+	}
+	if got := byID[4].Fields; !reflect.DeepEqual(got, []Field{FieldQuestion}) {
+		t.Errorf("fields of question 4 = %v, want question", got)
+	}
+}
+
+func TestTerms(t *testing.T) {
+	if got := Terms("Chmod  -R u+x /etc/passwd chmod"); !reflect.DeepEqual(got, []string{"chmod", "r", "u", "x", "etc", "passwd"}) {
+		t.Errorf("Terms() = %q", got)
+	}
+	if got := Terms("--"); got != nil {
+		t.Errorf("Terms(\"--\") = %q, want none", got)
+	}
+}
+
+func TestFieldMarshalText(t *testing.T) {
+	for field, want := range map[Field]string{FieldQuestion: "question", FieldAnswer: "answer", FieldExplanation: "explanation", Field(9): "unknown"} {
+		if text, _ := field.MarshalText(); string(text) != want {
+			t.Errorf("MarshalText() = %q, want %q", text, want)
+		}
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	question := func(id int, text, answer, explanation string) *types.Question {
+		return &types.Question{ID: id, Text: text, Answers: []*types.Answer{{Text: answer, IsCorrect: true}}, Explanation: explanation}
+	}
+	// an exact word ranks above a word starting with the term
+	idx := NewIndex(&types.CertificationSet{Questions: map[int]*types.Question{
+		1: question(1, "Is chmodding a word?", "yes", ""),
+		2: question(2, "Is chmod a command?", "yes", ""),
+	}})
+	if got := resultIDs(idx.Search("chmod")); !reflect.DeepEqual(got, []int{2, 1}) {
+		t.Errorf("Search(\"chmod\") = %v, want [2 1]", got)
+	}
+
+	// the text ranks above the answers and those above the explanation
+	idx = NewIndex(&types.CertificationSet{Questions: map[int]*types.Question{
+		1: question(1, "Which command?", "yes", "chmod"),
+		2: question(2, "Which command?", "chmod", ""),
+		3: question(3, "Is chmod a command?", "yes", ""),
+	}})
+	if got := resultIDs(idx.Search("chmod")); !reflect.DeepEqual(got, []int{3, 2, 1}) {
+		t.Errorf("Search(\"chmod\") = %v, want [3 2 1]", got)
+	}
+}
@@ -0,0 +1,145 @@
+package shellcmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Diff lists how a typed command line differs from an accepted one.
+type Diff struct {
+	// Missing are the options, assignments and redirections of the accepted
+	// command line that were not typed.
+	Missing []string
+	// Extra are the ones typed but not accepted.
+	Extra []string
+	// Mismatches describe other differences, e.g. of the command name or
+	// the operands.
+	Mismatches []string
+}
+
+// Empty reports whether the command lines are equivalent.
+func (d Diff) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.Mismatches) == 0
+}
+
+func (d Diff) size() int {
+	return len(d.Missing) + len(d.Extra) + 2*len(d.Mismatches)
+}
+
+func (d Diff) String() string {
+	var parts []string
+	if len(d.Missing) > 0 {
+		parts = append(parts, "missing "+strings.Join(d.Missing, ", "))
+	}
+	if len(d.Extra) > 0 {
+		parts = append(parts, "extra "+strings.Join(d.Extra, ", "))
+	}
+	parts = append(parts, d.Mismatches...)
+	return strings.Join(parts, "; ")
+}
+
+// Compare returns the differences of typed to accepted.
+func Compare(typed, accepted *Line) Diff {
+	var d Diff
+	if len(typed.Commands) != len(accepted.Commands) || strings.Join(typed.Operators, " ") != strings.Join(accepted.Operators, " ") {
+		d.Mismatches = append(d.Mismatches, fmt.Sprintf("expected %s", shape(accepted)))
+		return d
+	}
+	for i, want := range accepted.Commands {
+		got := typed.Commands[i]
+		if got.Raw != "" || want.Raw != "" {
+			if got.Raw != want.Raw {
+				d.Mismatches = append(d.Mismatches, fmt.Sprintf("%s instead of %s", got, want))
+			}
+			continue
+		}
+		if got.Name != want.Name {
+			d.Mismatches = append(d.Mismatches, fmt.Sprintf("command %s instead of %s", quote(got.Name), quote(want.Name)))
+			continue
+		}
+		gotOptions, wantOptions := optionStrings(got.Options), optionStrings(want.Options)
+		d.Missing = append(d.Missing, subtract(wantOptions, gotOptions)...)
+		d.Extra = append(d.Extra, subtract(gotOptions, wantOptions)...)
+		d.Missing = append(d.Missing, subtract(want.Assigns, got.Assigns)...)
+		d.Extra = append(d.Extra, subtract(got.Assigns, want.Assigns)...)
+		d.Missing = append(d.Missing, subtract(want.Redirects, got.Redirects)...)
+		d.Extra = append(d.Extra, subtract(got.Redirects, want.Redirects)...)
+		if strings.Join(got.Operands, "\x00") != strings.Join(want.Operands, "\x00") {
+			d.Mismatches = append(d.Mismatches, fmt.Sprintf("%s arguments %s instead of %s",
+				want.Name, operandList(got.Operands), operandList(want.Operands)))
+		}
+	}
+	return d
+}
+
+// Judge parses answer and compares it with each of the accepted command
+// lines. It reports whether one of them is equivalent and otherwise returns
+// the differences to the closest one. Accepted command lines that do not
+// parse are skipped.
+func Judge(answer string, accepted []string) (bool, Diff, error) {
+	typed, err := Parse(answer)
+	if err != nil {
+		return false, Diff{}, err
+	}
+	var closest Diff
+	found := false
+	for _, solution := range accepted {
+		line, err := Parse(solution)
+		if err != nil {
+			continue
+		}
+		d := Compare(typed, line)
+		if d.Empty() {
+			return true, d, nil
+		}
+		if !found || d.size() < closest.size() {
+			closest, found = d, true
+		}
+	}
+	return false, closest, nil
+}
+
+// shape describes the commands and operators of l, e.g. "2 commands joined
+// by |".
+func shape(l *Line) string {
+	if len(l.Commands) == 1 {
+		return "a single command"
+	}
+	return fmt.Sprintf("%d commands joined by %s", len(l.Commands), strings.Join(l.Operators, " "))
+}
+
+func optionStrings(options []Option) []string {
+	strs := make([]string, 0, len(options))
+	for _, option := range options {
+		strs = append(strs, option.String())
+	}
+	return strs
+}
+
+// subtract returns the elements of a not in b, counting duplicates.
+func subtract(a, b []string) []string {
+	left := make(map[string]int, len(b))
+	for _, s := range b {
+		left[s]++
+	}
+	var rest []string
+	for _, s := range a {
+		if left[s] > 0 {
+			left[s]--
+			continue
+		}
+		rest = append(rest, s)
+	}
+	return rest
+}
+
+func operandList(operands []string) string {
+	if len(operands) == 0 {
+		return "none"
+	}
+	quoted := make([]string, 0, len(operands))
+	for _, operand := range operands {
+		quoted = append(quoted, quote(operand))
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,90 @@
+package shellcmd
+
+import (
+	"slices"
+	"strings"
+)
+
+// optionSpec describes the options of a command that take a value, so the
+// value is not mistaken for an operand.
+type optionSpec struct {
+	// short lists the letters of the short options in getopt syntax: a
+	// letter followed by ':' takes a value, by '::' an optional value that
+	// has to be attached, e.g. sed -i.bak.
+	short string
+	// long are the long options taking a value also given as the next word,
+	// e.g. tar --file a.tgz. --name=value always works.
+	long []string
+	// bundled commands take their options as first word without a dash,
+	// e.g. tar xzf a.tgz or ps aux.
+	bundled bool
+	// bundleFlags makes the letters of the bundled form take no value even
+	// if short says so, like the BSD options of ps: ps aux is -a -u -x, but
+	// ps -u root selects the processes of root.
+	bundleFlags bool
+	// verbatim commands take expressions instead of options, e.g. find
+	// -name '*.txt'. Their words are compared in order.
+	verbatim bool
+}
+
+// specs are the commands whose option values are known. Other commands are
+// assumed to have only flags.
+var specs = map[string]optionSpec{
+	"ar":         {bundled: true},
+	"at":         {short: "f:q:t:"},
+	"awk":        {short: "F:v:f:"},
+	"chage":      {short: "d:E:I:m:M:W:"},
+	"cp":         {short: "S:t:", long: []string{"target-directory", "suffix"}},
+	"cpio":       {short: "F:H:I:O:", long: []string{"format", "file"}},
+	"crontab":    {short: "u:"},
+	"cut":        {short: "b:c:d:f:", long: []string{"bytes", "characters", "delimiter", "fields"}},
+	"date":       {short: "d:f:r:s:", long: []string{"date", "file", "reference", "set"}},
+	"find":       {verbatim: true},
+	"grep":       {short: "A:B:C:e:f:m:", long: []string{"regexp", "file", "max-count"}},
+	"egrep":      {short: "A:B:C:e:f:m:", long: []string{"regexp", "file", "max-count"}},
+	"fgrep":      {short: "A:B:C:e:f:m:", long: []string{"regexp", "file", "max-count"}},
+	"groupadd":   {short: "g:K:"},
+	"groupmod":   {short: "g:n:"},
+	"head":       {short: "c:n:", long: []string{"bytes", "lines"}},
+	"join":       {short: "1:2:j:o:t:"},
+	"journalctl": {short: "n:o:p:S:t:u:U:", long: []string{"unit", "lines", "priority", "since", "until", "output"}},
+	"kill":       {short: "n:s:"},
+	"ln":         {short: "S:t:", long: []string{"target-directory", "suffix"}},
+	"mkdir":      {short: "m:", long: []string{"mode"}},
+	"mkfs":       {short: "t:"},
+	"mount":      {short: "L:o:O:t:U:", long: []string{"types", "options"}},
+	"mv":         {short: "S:t:", long: []string{"target-directory", "suffix"}},
+	"nice":       {short: "n:", long: []string{"adjustment"}},
+	"nl":         {short: "b:i:n:s:v:w:"},
+	"od":         {short: "A:j:N:t:w::"},
+	"paste":      {short: "d:", long: []string{"delimiters"}},
+	"ps":         {short: "C:G:o:p:t:u:U:", bundled: true, bundleFlags: true},
+	"renice":     {short: "n:"},
+	"scp":        {short: "F:i:o:P:"},
+	"sed":        {short: "e:f:i::l:", long: []string{"expression", "file"}},
+	"sort":       {short: "k:o:S:t:T:", long: []string{"key", "output", "field-separator"}},
+	"split":      {short: "a:b:C:l:n:"},
+	"ssh":        {short: "D:F:i:J:l:L:o:p:R:"},
+	"ssh-keygen": {short: "b:C:f:N:t:"},
+	"tail":       {short: "c:n:s:", long: []string{"bytes", "lines"}},
+	"tar":        {short: "b:C:f:g:K:L:N:T:V:X:", long: []string{"file", "directory", "files-from", "exclude-from"}, bundled: true},
+	"uniq":       {short: "f:s:w:", long: []string{"skip-fields", "skip-chars", "check-chars"}},
+	"useradd":    {short: "c:d:e:f:g:G:k:K:p:s:u:", long: []string{"comment", "home-dir", "expiredate", "gid", "groups", "shell", "uid"}},
+	"usermod":    {short: "c:d:e:f:g:G:l:p:s:u:", long: []string{"comment", "home", "expiredate", "gid", "groups", "login", "shell", "uid"}},
+	"xargs":      {short: "a:d:E:I:L:n:P:s:"},
+}
+
+// value reports whether the short option letter takes a value and whether
+// the value is optional.
+func (s optionSpec) value(letter rune) (takes, optional bool) {
+	i := strings.IndexRune(s.short, letter)
+	if i < 0 || letter == ':' {
+		return false, false
+	}
+	rest := s.short[i+1:]
+	return strings.HasPrefix(rest, ":"), strings.HasPrefix(rest, "::")
+}
+
+func (s optionSpec) longValue(name string) bool {
+	return slices.Contains(s.long, name)
+}
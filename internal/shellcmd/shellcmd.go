@@ -0,0 +1,287 @@
+// Package shellcmd judges typed command lines by their meaning instead of
+// their text, so "tar -xzf a.tgz" is accepted for "tar xfz a.tgz".
+//
+// A command line is parsed with a shell parser and every simple command is
+// normalized: quoting is removed, option clusters are split into single
+// options and options are sorted, while operands keep their order. The values
+// of options are only known for the commands listed in specs, e.g. tar -f.
+package shellcmd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// Option is a normalized option of a command.
+type Option struct {
+	// Name is "-x" for short and "--name" for long options.
+	Name  string
+	Value string
+	// HasValue distinguishes an empty value from no value.
+	HasValue bool
+}
+
+func (o Option) String() string {
+	switch {
+	case !o.HasValue:
+		return o.Name
+	case strings.HasPrefix(o.Name, "--"):
+		return o.Name + "=" + quote(o.Value)
+	}
+	return o.Name + " " + quote(o.Value)
+}
+
+// Command is a normalized simple command.
+type Command struct {
+	// Assigns are the variable assignments before the command, e.g. LANG=C.
+	Assigns  []string
+	Name     string
+	Options  []Option
+	Operands []string
+	// Redirects are the redirections, e.g. "2>/dev/null".
+	Redirects []string
+	// Raw is set instead of the other fields for constructs that are not
+	// normalized, like subshells and loops, and compared as printed.
+	Raw string
+}
+
+func (c *Command) String() string {
+	if c.Raw != "" {
+		return c.Raw
+	}
+	words := slices.Clone(c.Assigns)
+	words = append(words, quote(c.Name))
+	for _, option := range c.Options {
+		words = append(words, option.String())
+	}
+	for _, operand := range c.Operands {
+		words = append(words, quote(operand))
+	}
+	words = append(words, c.Redirects...)
+	return strings.Join(words, " ")
+}
+
+// Line is a parsed command line: Commands joined by Operators like "|" and
+// "&&", with one operator less than commands.
+type Line struct {
+	Commands  []*Command
+	Operators []string
+}
+
+// String returns the normalized command line. Equivalent command lines have
+// the same String.
+func (l *Line) String() string {
+	var b strings.Builder
+	for i, command := range l.Commands {
+		if i > 0 {
+			fmt.Fprintf(&b, " %s ", l.Operators[i-1])
+		}
+		b.WriteString(command.String())
+	}
+	return b.String()
+}
+
+// Parse parses and normalizes the command line line.
+func Parse(line string) (*Line, error) {
+	file, err := syntax.NewParser(syntax.Variant(syntax.LangBash)).Parse(strings.NewReader(line), "")
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Stmts) == 0 {
+		return nil, fmt.Errorf("no command")
+	}
+	l := &Line{}
+	for i, stmt := range file.Stmts {
+		if i > 0 {
+			l.Operators = append(l.Operators, ";")
+		}
+		l.addStmt(stmt)
+	}
+	return l, nil
+}
+
+func (l *Line) addStmt(stmt *syntax.Stmt) {
+	if binary, ok := stmt.Cmd.(*syntax.BinaryCmd); ok && !stmt.Negated && !stmt.Background && len(stmt.Redirs) == 0 {
+		l.addStmt(binary.X)
+		l.Operators = append(l.Operators, binary.Op.String())
+		l.addStmt(binary.Y)
+		return
+	}
+	call, ok := stmt.Cmd.(*syntax.CallExpr)
+	if !ok || len(call.Args) == 0 || stmt.Negated || stmt.Background {
+		l.Commands = append(l.Commands, &Command{Raw: printed(stmt)})
+		return
+	}
+
+	command := &Command{}
+	for _, assign := range call.Assigns {
+		command.Assigns = append(command.Assigns, printed(assign))
+	}
+	sort.Strings(command.Assigns)
+	for _, redirect := range stmt.Redirs {
+		command.Redirects = append(command.Redirects, redirectText(redirect))
+	}
+	sort.Strings(command.Redirects)
+
+	words := make([]string, 0, len(call.Args))
+	for _, arg := range call.Args {
+		words = append(words, wordText(arg))
+	}
+	command.Name = words[0]
+	command.parseArgs(words[1:], specs[command.Name])
+	l.Commands = append(l.Commands, command)
+}
+
+// parseArgs splits args into options and operands like getopt does.
+func (c *Command) parseArgs(args []string, spec optionSpec) {
+	if spec.verbatim {
+		c.Operands = args
+		return
+	}
+	if spec.bundled && len(args) > 0 && isBundle(args[0]) {
+		args = c.parseBundle(args, spec)
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			c.Operands = append(c.Operands, args[i+1:]...)
+			i = len(args)
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue := strings.Cut(arg[2:], "=")
+			if !hasValue && spec.longValue(name) && i+1 < len(args) {
+				i++
+				value, hasValue = args[i], true
+			}
+			c.Options = append(c.Options, Option{Name: "--" + name, Value: value, HasValue: hasValue})
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			letters := []rune(arg[1:])
+			for j, letter := range letters {
+				option := Option{Name: "-" + string(letter)}
+				takes, optional := spec.value(letter)
+				if takes {
+					option.Value, option.HasValue = string(letters[j+1:]), j+1 < len(letters)
+					if !option.HasValue && !optional && i+1 < len(args) {
+						i++
+						option.Value, option.HasValue = args[i], true
+					}
+				}
+				c.Options = append(c.Options, option)
+				if takes {
+					break
+				}
+			}
+		default:
+			c.Operands = append(c.Operands, arg)
+		}
+	}
+	sort.SliceStable(c.Options, func(i, j int) bool {
+		a, b := c.Options[i], c.Options[j]
+		return a.Name < b.Name || a.Name == b.Name && a.Value < b.Value
+	})
+}
+
+// parseBundle parses the old style options of tar and ps given as first word
+// without a dash. Their values follow as the next words in the order of the
+// letters, e.g. tar cfb a.tar 20. It returns the remaining args.
+func (c *Command) parseBundle(args []string, spec optionSpec) []string {
+	rest := args[1:]
+	for _, letter := range args[0] {
+		option := Option{Name: "-" + string(letter)}
+		if takes, optional := spec.value(letter); takes && !optional && !spec.bundleFlags && len(rest) > 0 {
+			option.Value, option.HasValue = rest[0], true
+			rest = rest[1:]
+		}
+		c.Options = append(c.Options, option)
+	}
+	return rest
+}
+
+func isBundle(arg string) bool {
+	if arg == "" {
+		return false
+	}
+	for _, r := range arg {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// wordText returns the value of word with the quotes removed. Expansions like
+// $HOME are kept as written.
+func wordText(word *syntax.Word) string {
+	var b strings.Builder
+	for _, part := range word.Parts {
+		partText(&b, part, false)
+	}
+	return b.String()
+}
+
+func partText(b *strings.Builder, part syntax.WordPart, inDouble bool) {
+	switch part := part.(type) {
+	case *syntax.Lit:
+		b.WriteString(unescape(part.Value, inDouble))
+	case *syntax.SglQuoted:
+		if part.Dollar {
+			b.WriteString(printed(part))
+			return
+		}
+		b.WriteString(part.Value)
+	case *syntax.DblQuoted:
+		for _, inner := range part.Parts {
+			partText(b, inner, true)
+		}
+	default:
+		b.WriteString(printed(part))
+	}
+}
+
+// unescape removes the backslashes of a literal. Within double quotes only
+// the characters special there are escaped.
+func unescape(lit string, inDouble bool) string {
+	if !strings.Contains(lit, `\`) {
+		return lit
+	}
+	var b strings.Builder
+	for i := 0; i < len(lit); i++ {
+		if lit[i] == '\\' && i+1 < len(lit) && (!inDouble || strings.IndexByte("$`\"\\\n", lit[i+1]) >= 0) {
+			i++
+		}
+		b.WriteByte(lit[i])
+	}
+	return b.String()
+}
+
+func redirectText(redirect *syntax.Redirect) string {
+	var b strings.Builder
+	if redirect.N != nil {
+		b.WriteString(redirect.N.Value)
+	}
+	b.WriteString(redirect.Op.String())
+	if redirect.Word != nil {
+		b.WriteString(quote(wordText(redirect.Word)))
+	}
+	return b.String()
+}
+
+func printed(node syntax.Node) string {
+	var b strings.Builder
+	if err := syntax.NewPrinter(syntax.SingleLine(true)).Print(&b, node); err != nil {
+		return fmt.Sprintf("%v", node)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// quote quotes s if it is not a plain word.
+func quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"\\$`|&;<>()*?[]#~") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
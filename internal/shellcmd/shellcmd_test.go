@@ -0,0 +1,96 @@
+package shellcmd
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"tar xzf a.tgz", "tar -f a.tgz -x -z"},
+		{"ls -la /tmp", "ls -a -l /tmp"},
+		{"tar cfb a.tar 20 dir", "tar -b 20 -c -f a.tar dir"},
+		{"ps aux", "ps -a -u -x"},
+		{"ps aux --forest", "ps --forest -a -u -x"},
+		{"ps -u root -o pid,cmd", "ps -o pid,cmd -u root"},
+		{"sed -i.bak s/a/b/ f", "sed -i .bak s/a/b/ f"},
+		{"head --lines 5 f", "head --lines=5 f"},
+		{`grep -e "a b" -- -f`, `grep -e 'a b' -f`},
+		{"find . -name '*.txt' -type f", "find . -name '*.txt' -type f"},
+		{"LANG=C sort 2>/dev/null -t: -k2 f", "LANG=C sort -k 2 -t : f 2>/dev/null"},
+		{"ls | wc -l && echo done", "ls | wc -l && echo done"},
+		{"(cd /tmp; ls)", "(cd /tmp; ls)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			line, err := Parse(tt.line)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := line.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, line := range []string{"", "   ", "tar 'xzf", "ls |"} {
+		if _, err := Parse(line); err == nil {
+			t.Errorf("Parse(%q) succeeded", line)
+		}
+	}
+}
+
+func TestJudge(t *testing.T) {
+	tests := []struct {
+		name     string
+		typed    string
+		accepted []string
+		ok       bool
+		diff     string
+	}{
+		{"bundled tar flags", "tar xzf a.tgz", []string{"tar -xzf a.tgz"}, true, ""},
+		{"split tar flags", "tar -x -z -f 'a.tgz'", []string{"tar xfz a.tgz"}, true, ""},
+		{"reordered tar flags", "tar -zxf a.tgz", []string{"tar xzf a.tgz"}, true, ""},
+		{"tar value in the bundle order", "tar -c -f a.tar -b 20 dir", []string{"tar cfb a.tar 20 dir"}, true, ""},
+		{"reordered options", "ls -l -a /tmp", []string{"ls -al /tmp"}, true, ""},
+		{"attached option value", "sort -t: -k2 /etc/passwd", []string{"sort -k 2 -t : /etc/passwd"}, true, ""},
+		{"long option value", "head --lines=5 f", []string{"head --lines 5 f"}, true, ""},
+		{"quoting", `grep -i foo\ bar "file"`, []string{"grep -i 'foo bar' file"}, true, ""},
+		{"bsd ps options", "ps xau | grep ssh", []string{"ps aux | grep ssh"}, true, ""},
+		{"redirections", "LANG=C ls 2> /dev/null", []string{"LANG=C ls 2>/dev/null"}, true, ""},
+		{"find quoting", `find . -name "*.txt"`, []string{"find . -name '*.txt'"}, true, ""},
+		{"any accepted", "ls -l", []string{"ls -a", "ls -l"}, true, ""},
+
+		{"f takes the next letter", "tar -xfz a.tgz", []string{"tar -xzf a.tgz"}, false,
+			"missing -f a.tgz, -z; extra -f z; tar arguments a.tgz instead of none"},
+		{"missing and extra flags", "tar -xvf a.tgz", []string{"tar -xzf a.tgz"}, false, "missing -z; extra -v"},
+		{"operand order", "cp b a", []string{"cp a b"}, false, "cp arguments b a instead of a b"},
+		{"find is compared word by word", "find . -type f -name '*.txt'", []string{"find . -name '*.txt' -type f"}, false,
+			"find arguments . -type f -name '*.txt' instead of . -name '*.txt' -type f"},
+		{"other command", "dir -l", []string{"ls -l"}, false, "command dir instead of ls"},
+		{"other operator", "ls && wc -l", []string{"ls | wc -l"}, false, "expected 2 commands joined by |"},
+		{"other number of commands", "ls", []string{"ls | wc -l"}, false, "expected 2 commands joined by |"},
+		{"bsd and unix ps options differ", "ps -aux", []string{"ps aux"}, false, "missing -u, -x; extra -u x"},
+		{"closest accepted", "tar -xf a.tgz", []string{"tar -czvf a.tgz dir", "tar -xzf a.tgz"}, false, "missing -z"},
+		{"invalid accepted are skipped", "ls -l", []string{"ls '", "ls -a"}, false, "missing -a; extra -l"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, diff, err := Judge(tt.typed, tt.accepted)
+			if err != nil {
+				t.Fatalf("Judge: %v", err)
+			}
+			if ok != tt.ok || diff.String() != tt.diff {
+				t.Errorf("Judge(%q, %q) = %v, %q; want %v, %q", tt.typed, tt.accepted, ok, diff, tt.ok, tt.diff)
+			}
+		})
+	}
+}
+
+func TestJudgeInvalidAnswer(t *testing.T) {
+	if ok, _, err := Judge("tar 'xzf a.tgz", []string{"tar xzf a.tgz"}); ok || err == nil {
+		t.Errorf("Judge accepted an unterminated quote: ok %v, err %v", ok, err)
+	}
+}
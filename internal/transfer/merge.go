@@ -0,0 +1,147 @@
+package transfer
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/SqiSch/lpic-cli/internal/repository"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+type ChangeKind string
+
+const (
+	// ChangeAdded is a question that had no local state.
+	ChangeAdded ChangeKind = "added"
+	// ChangeUpdated is a question whose imported state replaced or extended the local one.
+	ChangeUpdated ChangeKind = "updated"
+	// ChangeKept is a conflicting question whose local state is more recent.
+	ChangeKept ChangeKind = "kept"
+)
+
+// Change describes how the state of a single question was merged.
+type Change struct {
+	Kind     ChangeKind
+	Local    *types.QuestionStateDB
+	Imported types.QuestionStateDB
+	Merged   types.QuestionStateDB
+}
+
+// NamespaceReport is the outcome of importing one namespace.
+type NamespaceReport struct {
+	Namespace      repository.Namespace
+	Changes        []Change
+	Unchanged      int
+	NewEvents      int
+	NewExamResults int
+}
+
+// Count returns the number of changes of kind.
+func (r NamespaceReport) Count(kind ChangeKind) int {
+	count := 0
+	for _, change := range r.Changes {
+		if change.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// Report is the outcome of an import.
+type Report struct {
+	DryRun     bool
+	Namespaces []NamespaceReport
+}
+
+// Merge merges imported question states into the local ones. Conflicts are
+// resolved by the most recent change: the state answered or reset last wins
+// as a whole. If both changed at the same time (or never, e.g. only marked as
+// important), the local answers are kept and the important flag is set if
+// either side has it. Tags are always combined, and a note is only replaced
+// by a note of the winning side. Identical states are only counted.
+func Merge(local, imported []types.QuestionStateDB) (changes []Change, unchanged int) {
+	byID := make(map[int]types.QuestionStateDB, len(local))
+	for _, state := range local {
+		byID[state.QuestionID] = state
+	}
+
+	for _, incoming := range imported {
+		current, ok := byID[incoming.QuestionID]
+		if !ok {
+			changes = append(changes, Change{Kind: ChangeAdded, Imported: incoming, Merged: incoming})
+			continue
+		}
+		if sameState(current, incoming) {
+			unchanged++
+			continue
+		}
+
+		change := Change{Local: &current, Imported: incoming}
+		switch {
+		case incoming.ChangedAt().After(current.ChangedAt()):
+			change.Kind = ChangeUpdated
+			change.Merged = incoming
+		case current.ChangedAt().After(incoming.ChangedAt()):
+			change.Kind = ChangeKept
+			change.Merged = current
+		default:
+			change.Merged = current
+			change.Merged.Important = current.Important || incoming.Important
+			change.Kind = ChangeKept
+			if change.Merged.Important != current.Important {
+				change.Kind = ChangeUpdated
+			}
+		}
+		mergeAnnotations(&change.Merged, current, incoming)
+		if change.Kind == ChangeKept && !sameAnnotations(change.Merged, current) {
+			change.Kind = ChangeUpdated
+		}
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Imported.QuestionID < changes[j].Imported.QuestionID
+	})
+	return changes, unchanged
+}
+
+// mergeAnnotations sets the tags of merged to the tags of both sides and
+// keeps the other side's note if merged has none.
+func mergeAnnotations(merged *types.QuestionStateDB, current, incoming types.QuestionStateDB) {
+	merged.Tags = types.NormalizeTags(append(slices.Clone(current.Tags), incoming.Tags...))
+	if merged.Note == "" {
+		merged.Note = current.Note
+	}
+	if merged.Note == "" {
+		merged.Note = incoming.Note
+	}
+}
+
+func sameAnnotations(a, b types.QuestionStateDB) bool {
+	return slices.Equal(a.Tags, b.Tags) && a.Note == b.Note
+}
+
+func sameState(a, b types.QuestionStateDB) bool {
+	if !sameAnnotations(a, b) {
+		return false
+	}
+	if a.QuestionID != b.QuestionID || a.AnsweredState != b.AnsweredState || a.Important != b.Important ||
+		a.Attempts != b.Attempts || !a.LastAnsweredAt.Equal(b.LastAnsweredAt) || !a.ResetAt.Equal(b.ResetAt) || a.TypedAnswer != b.TypedAnswer {
+		return false
+	}
+	if len(a.MarkedAnswers) != len(b.MarkedAnswers) {
+		return false
+	}
+	marked := make(map[string]bool, len(a.MarkedAnswers))
+	for _, answerID := range a.MarkedAnswers {
+		marked[answerID] = true
+	}
+	for _, answerID := range b.MarkedAnswers {
+		if !marked[answerID] {
+			return false
+		}
+	}
+	ra, rb := a.Review, b.Review
+	return ra.EaseFactor == rb.EaseFactor && ra.IntervalDays == rb.IntervalDays && ra.Repetitions == rb.Repetitions &&
+		ra.Lapses == rb.Lapses && ra.Due.Equal(rb.Due) && ra.LastSessionID == rb.LastSessionID
+}
@@ -0,0 +1,310 @@
+// Package transfer exports the stored progress to a versioned JSON file and
+// merges such a file back into a state backend, e.g. to move between laptops.
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/SqiSch/lpic-cli/internal/repository"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+const (
+	// Format identifies progress files.
+	Format = "lpic-cli-progress"
+	// Version is the version of the file format written by Export. Newer
+	// versions are rejected by Import.
+	Version = 1
+)
+
+// File is the content of a progress file.
+type File struct {
+	Format     string      `json:"format"`
+	Version    int         `json:"version"`
+	ExportedAt time.Time   `json:"exportedAt"`
+	Namespaces []Namespace `json:"namespaces"`
+}
+
+// Namespace holds the progress of one certification and profile.
+type Namespace struct {
+	CertificationID string                  `json:"certificationId"`
+	Profile         string                  `json:"profile"`
+	Questions       []types.QuestionStateDB `json:"questions"`
+	History         []types.AnswerEvent     `json:"history,omitempty"`
+	ExamResults     []types.ExamResult      `json:"examResults,omitempty"`
+}
+
+func (ns Namespace) namespace() repository.Namespace {
+	return repository.Namespace{CertificationID: ns.CertificationID, Profile: ns.Profile}
+}
+
+// Filter selects the namespaces to export or import. Empty fields match all.
+type Filter struct {
+	CertificationID string
+	Profile         string
+}
+
+func (f Filter) match(ns repository.Namespace) bool {
+	return (f.CertificationID == "" || f.CertificationID == ns.CertificationID) &&
+		(f.Profile == "" || f.Profile == ns.GetProfile())
+}
+
+// ErrLegacyProgress is returned by Export when the state holds progress of a
+// version without namespaces and no namespace to export it as was given.
+var ErrLegacyProgress = errors.New("the state holds progress of a version without certifications and profiles")
+
+// Export reads the progress of every namespace matched by filter. The stores
+// are opened one after another, so directory-locked backends work as well.
+// Legacy progress that was never migrated is exported as the progress of
+// legacy, see exportLegacy; if legacy is the zero Namespace Export fails with
+// ErrLegacyProgress instead of leaving it out.
+func Export(ctx context.Context, open repository.Opener, filter Filter, legacy repository.Namespace) (*File, error) {
+	namespaces, hasLegacy, err := listNamespaces(ctx, open)
+	if err != nil {
+		return nil, err
+	}
+	if hasLegacy && legacy.IsZero() {
+		return nil, ErrLegacyProgress
+	}
+
+	file := &File{Format: Format, Version: Version, ExportedAt: time.Now().UTC()}
+	for _, ns := range namespaces {
+		if !filter.match(ns) {
+			continue
+		}
+		exported, err := exportNamespace(ctx, open, ns)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", ns, err)
+		}
+		file.Namespaces = append(file.Namespaces, *exported)
+	}
+	if hasLegacy {
+		if err := exportLegacy(ctx, open, file, filter, legacy); err != nil {
+			return nil, fmt.Errorf("failed to export the legacy progress: %w", err)
+		}
+	}
+	return file, nil
+}
+
+// listNamespaces returns the namespaces with progress and whether the store
+// holds legacy progress that was never migrated.
+func listNamespaces(ctx context.Context, open repository.Opener) ([]repository.Namespace, bool, error) {
+	store, err := open(repository.Namespace{})
+	if err != nil {
+		return nil, false, err
+	}
+	defer store.Close()
+
+	namespaces, err := store.GetNamespaces(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	legacy, ok := store.(repository.LegacyRepository)
+	if !ok {
+		return namespaces, false, nil
+	}
+	hasLegacy, err := legacy.HasLegacyProgress(ctx)
+	return namespaces, hasLegacy, err
+}
+
+// exportLegacy adds the legacy progress to file the way the client migrates
+// it: the question states go to legacy unless the namespace has a state of
+// the question already, the exam results to the namespace of their
+// certification with the profile of legacy.
+func exportLegacy(ctx context.Context, open repository.Opener, file *File, filter Filter, legacy repository.Namespace) error {
+	exported, err := exportNamespace(ctx, open, repository.Namespace{})
+	if err != nil {
+		return err
+	}
+	legacy.Profile = legacy.GetProfile()
+
+	if filter.match(legacy) {
+		target := file.namespace(legacy)
+		stored := make(map[int]bool, len(target.Questions))
+		for _, state := range target.Questions {
+			stored[state.QuestionID] = true
+		}
+		for _, state := range exported.Questions {
+			if !stored[state.QuestionID] {
+				target.Questions = append(target.Questions, state)
+			}
+		}
+	}
+	for _, result := range exported.ExamResults {
+		ns := repository.Namespace{CertificationID: result.CertificationID, Profile: legacy.Profile}
+		if ns.CertificationID == "" || !filter.match(ns) {
+			continue
+		}
+		target := file.namespace(ns)
+		target.ExamResults = append(target.ExamResults, newExamResults(target.ExamResults, []types.ExamResult{result})...)
+	}
+
+	sort.Slice(file.Namespaces, func(i, j int) bool {
+		return file.Namespaces[i].namespace().String() < file.Namespaces[j].namespace().String()
+	})
+	return nil
+}
+
+// namespace returns the entry of ns in file, adding an empty one if needed.
+func (file *File) namespace(ns repository.Namespace) *Namespace {
+	for i := range file.Namespaces {
+		if file.Namespaces[i].namespace() == ns {
+			return &file.Namespaces[i]
+		}
+	}
+	file.Namespaces = append(file.Namespaces, Namespace{
+		CertificationID: ns.CertificationID, Profile: ns.Profile, Questions: []types.QuestionStateDB{},
+	})
+	return &file.Namespaces[len(file.Namespaces)-1]
+}
+
+func exportNamespace(ctx context.Context, open repository.Opener, ns repository.Namespace) (*Namespace, error) {
+	store, err := open(ns)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	exported := &Namespace{CertificationID: ns.CertificationID, Profile: ns.GetProfile()}
+	if exported.Questions, err = store.GetAnsweredQuestions(); err != nil {
+		return nil, err
+	}
+	if exported.Questions == nil {
+		exported.Questions = []types.QuestionStateDB{}
+	}
+	if exported.History, err = store.GetAnswerHistory(ctx); err != nil {
+		return nil, err
+	}
+	if exported.ExamResults, err = store.GetExamResults(ctx); err != nil {
+		return nil, err
+	}
+	return exported, nil
+}
+
+// Write encodes file as indented JSON.
+func Write(w io.Writer, file *File) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(file)
+}
+
+// Read decodes a progress file and checks its format and version.
+func Read(r io.Reader) (*File, error) {
+	var file File
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to decode progress file: %w", err)
+	}
+	if file.Format != Format {
+		return nil, fmt.Errorf("not a progress file: format is %q, want %q", file.Format, Format)
+	}
+	if file.Version < 1 || file.Version > Version {
+		return nil, fmt.Errorf("unsupported progress file version %d, this client supports up to %d", file.Version, Version)
+	}
+	return &file, nil
+}
+
+// Import merges the namespaces of file matched by filter into the backend,
+// see Merge. With dryRun nothing is written and the report shows what an
+// import would change.
+func Import(ctx context.Context, open repository.Opener, file *File, filter Filter, dryRun bool) (*Report, error) {
+	report := &Report{DryRun: dryRun}
+	for _, imported := range file.Namespaces {
+		ns := imported.namespace()
+		if ns.CertificationID == "" || !filter.match(ns) {
+			continue
+		}
+		nsReport, err := importNamespace(ctx, open, imported, dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import %s: %w", ns, err)
+		}
+		report.Namespaces = append(report.Namespaces, *nsReport)
+	}
+	return report, nil
+}
+
+func importNamespace(ctx context.Context, open repository.Opener, imported Namespace, dryRun bool) (*NamespaceReport, error) {
+	store, err := open(imported.namespace())
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	local, err := store.GetAnsweredQuestions()
+	if err != nil {
+		return nil, err
+	}
+	history, err := store.GetAnswerHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	examResults, err := store.GetExamResults(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &NamespaceReport{Namespace: imported.namespace()}
+	report.Changes, report.Unchanged = Merge(local, imported.Questions)
+	events := newEvents(history, imported.History)
+	report.NewEvents = len(events)
+	results := newExamResults(examResults, imported.ExamResults)
+	report.NewExamResults = len(results)
+	if dryRun {
+		return report, nil
+	}
+
+	for _, change := range report.Changes {
+		if change.Kind == ChangeKept {
+			continue
+		}
+		if err := store.PutQuestionState(ctx, change.Merged); err != nil {
+			return nil, err
+		}
+	}
+	if err := store.AppendAnswerEvents(ctx, events); err != nil {
+		return nil, err
+	}
+	for i := range results {
+		if err := store.SaveExamResult(ctx, &results[i]); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// newEvents returns the imported events that are not in the local history.
+func newEvents(local, imported []types.AnswerEvent) []types.AnswerEvent {
+	known := make(map[string]bool, len(local))
+	for _, event := range local {
+		known[event.ID] = true
+	}
+	var events []types.AnswerEvent
+	for _, event := range imported {
+		if event.ID != "" && known[event.ID] {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// newExamResults returns the imported exam results that are not stored locally.
+func newExamResults(local, imported []types.ExamResult) []types.ExamResult {
+	known := make(map[string]bool, len(local))
+	for _, result := range local {
+		known[result.ID] = true
+	}
+	var results []types.ExamResult
+	for _, result := range imported {
+		if known[result.ID] {
+			continue
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,264 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SqiSch/lpic-cli/internal/repository"
+	"github.com/SqiSch/lpic-cli/internal/types"
+)
+
+var (
+	transferStart = time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)
+	transferNS    = repository.Namespace{CertificationID: "lpic1-101-500", Profile: "alice"}
+)
+
+func answered(id int, result types.AnsweredState, at time.Time) types.AnswerEvent {
+	return types.AnswerEvent{Kind: types.AnswerEventAttempt, QuestionID: id, SessionID: "s1", AnswerIDs: []string{"a"}, Result: result, Timestamp: at}
+}
+
+func sqliteOpener(t *testing.T) repository.Opener {
+	t.Helper()
+	cfg := repository.Config{Backend: repository.BackendSQLite, Dir: t.TempDir()}
+	return cfg.Opener(context.Background())
+}
+
+// exportFile records a few answers and an exam result and returns them as
+// an encoded progress file.
+func exportFile(t *testing.T) []byte {
+	t.Helper()
+	ctx := context.Background()
+	open := sqliteOpener(t)
+	store, err := open(transferNS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range []types.AnswerEvent{
+		answered(1, types.AnsweredTrue, transferStart),
+		answered(2, types.AnsweredFalse, transferStart.Add(time.Minute)),
+		{Kind: types.AnswerEventReset, QuestionID: 2, SessionID: "s1", Timestamp: transferStart.Add(2 * time.Minute)},
+	} {
+		if err := store.RecordAnswer(ctx, event); err != nil {
+			t.Fatalf("RecordAnswer: %v", err)
+		}
+	}
+	if err := store.SaveExamResult(ctx, &types.ExamResult{ID: "exam", CertificationID: transferNS.CertificationID, StartedAt: transferStart}); err != nil {
+		t.Fatalf("SaveExamResult: %v", err)
+	}
+	store.Close()
+
+	file, err := Export(ctx, open, Filter{}, repository.Namespace{})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, file); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestImportSameFileTwice(t *testing.T) {
+	ctx := context.Background()
+	data := exportFile(t)
+	open := sqliteOpener(t)
+
+	for i, want := range []struct{ added, events, results, unchanged int }{
+		{added: 2, events: 3, results: 1},
+		{unchanged: 2},
+	} {
+		file, err := Read(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		report, err := Import(ctx, open, file, Filter{}, false)
+		if err != nil {
+			t.Fatalf("import %d: %v", i+1, err)
+		}
+		if len(report.Namespaces) != 1 {
+			t.Fatalf("import %d: %d namespaces, want 1", i+1, len(report.Namespaces))
+		}
+		ns := report.Namespaces[0]
+		if ns.Count(ChangeAdded) != want.added || ns.Count(ChangeUpdated) != 0 || ns.Unchanged != want.unchanged ||
+			ns.NewEvents != want.events || ns.NewExamResults != want.results {
+			t.Errorf("import %d: %d added, %d updated, %d unchanged, %d events, %d exam results; want %d, 0, %d, %d, %d",
+				i+1, ns.Count(ChangeAdded), ns.Count(ChangeUpdated), ns.Unchanged, ns.NewEvents, ns.NewExamResults,
+				want.added, want.unchanged, want.events, want.results)
+		}
+	}
+
+	store, err := open(transferNS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	history, err := store.GetAnswerHistory(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Errorf("history has %d events after two imports, want 3", len(history))
+	}
+	state, err := store.GetAnsweredQuestions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range state {
+		if s.QuestionID == 2 && (s.AnsweredState != types.AnsweredUnknown || !s.ResetAt.Equal(transferStart.Add(2*time.Minute))) {
+			t.Errorf("imported reset state = %+v", s)
+		}
+	}
+}
+
+// TestExportLegacy exports a state directory written by a version without
+// namespaces, which has legacy progress but no namespaced buckets yet.
+func TestExportLegacy(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	legacyStore := repository.NewNutsQuestionRepositoryWithDir(dir)
+	question := &types.Question{ID: 7, Answers: []*types.Answer{{AnswerID: "a", IsCorrect: true}}}
+	question.Answers[0].SetIsMarked(true)
+	question.SetAnsweredState(types.AnsweredTrue)
+	if err := legacyStore.UpsertQuestion(ctx, question); err != nil {
+		t.Fatalf("UpsertQuestion: %v", err)
+	}
+	for _, result := range []*types.ExamResult{
+		{ID: "exam-101", CertificationID: "lpic1-101-500"},
+		{ID: "exam-102", CertificationID: "lpic1-102-500"},
+	} {
+		if err := legacyStore.SaveExamResult(ctx, result); err != nil {
+			t.Fatalf("SaveExamResult: %v", err)
+		}
+	}
+	legacyStore.Close()
+	cfg := repository.Config{Backend: repository.BackendNuts, Dir: dir}
+	open := cfg.Opener(ctx)
+
+	if _, err := Export(ctx, open, Filter{}, repository.Namespace{}); !errors.Is(err, ErrLegacyProgress) {
+		t.Fatalf("Export without a legacy namespace: got %v, want %v", err, ErrLegacyProgress)
+	}
+
+	file, err := Export(ctx, open, Filter{}, repository.Namespace{CertificationID: "lpic1-101-500"})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	var got []string
+	for _, ns := range file.Namespaces {
+		var exams []string
+		for _, result := range ns.ExamResults {
+			exams = append(exams, result.ID)
+		}
+		got = append(got, fmt.Sprintf("%s/%s: %d questions, exams %v", ns.Profile, ns.CertificationID, len(ns.Questions), exams))
+	}
+	want := []string{
+		"default/lpic1-101-500: 1 questions, exams [exam-101]",
+		"default/lpic1-102-500: 0 questions, exams [exam-102]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("exported %q, want %q", got, want)
+	}
+
+	// once the trainer migrated the legacy progress it is exported as is
+	cfg.MigrateLegacy = true
+	cfg.Namespace = repository.Namespace{CertificationID: "lpic1-102-500", Profile: "alice"}
+	store, err := repository.Open(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+	file, err = Export(ctx, open, Filter{}, repository.Namespace{})
+	if err != nil {
+		t.Fatalf("Export after the migration: %v", err)
+	}
+	// the exam result of lpic1-101-500 went to alice/lpic1-101-500
+	if len(file.Namespaces) != 2 || file.Namespaces[1].namespace() != cfg.Namespace || len(file.Namespaces[1].Questions) != 1 {
+		t.Errorf("exported %+v after the migration, want the legacy progress in alice/lpic1-102-500", file.Namespaces)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	state := func(events ...types.AnswerEvent) types.QuestionStateDB {
+		var s types.QuestionStateDB
+		for _, event := range events {
+			s.Apply(event)
+		}
+		return s
+	}
+	reset := func(at time.Time) types.AnswerEvent {
+		return types.AnswerEvent{Kind: types.AnswerEventReset, QuestionID: 1, Timestamp: at}
+	}
+	older := answered(1, types.AnsweredFalse, transferStart)
+	newer := answered(1, types.AnsweredTrue, transferStart.Add(time.Hour))
+
+	tests := []struct {
+		name            string
+		local, imported types.QuestionStateDB
+		kind            ChangeKind
+		want            types.AnsweredState
+	}{
+		{"newer answer wins", state(older), state(newer), ChangeUpdated, types.AnsweredTrue},
+		{"older answer is kept", state(newer), state(older), ChangeKept, types.AnsweredTrue},
+		{"newer reset wins", state(older), state(older, reset(transferStart.Add(time.Hour))), ChangeUpdated, types.AnsweredUnknown},
+		{"local reset wins over an older answer", state(older, reset(transferStart.Add(time.Hour))), state(older), ChangeKept, types.AnsweredUnknown},
+		{"answer after a reset wins", state(older, reset(transferStart.Add(time.Minute))), state(newer), ChangeUpdated, types.AnsweredTrue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, unchanged := Merge([]types.QuestionStateDB{tt.local}, []types.QuestionStateDB{tt.imported})
+			if len(changes) != 1 || unchanged != 0 {
+				t.Fatalf("got %d changes and %d unchanged, want 1 change", len(changes), unchanged)
+			}
+			if changes[0].Kind != tt.kind || changes[0].Merged.AnsweredState != tt.want {
+				t.Errorf("got %s with %v, want %s with %v", changes[0].Kind, changes[0].Merged.AnsweredState, tt.kind, tt.want)
+			}
+		})
+	}
+
+	if changes, unchanged := Merge([]types.QuestionStateDB{state(older)}, []types.QuestionStateDB{state(older)}); len(changes) != 0 || unchanged != 1 {
+		t.Errorf("identical states: %d changes and %d unchanged, want only unchanged", len(changes), unchanged)
+	}
+}
+
+func TestMergeTagsAndNote(t *testing.T) {
+	annotated := func(result types.AnsweredState, at time.Time, note string, tags ...string) types.QuestionStateDB {
+		var s types.QuestionStateDB
+		s.Apply(answered(1, result, at))
+		s.Tags, s.Note = tags, note
+		return s
+	}
+	older, newer := transferStart, transferStart.Add(time.Hour)
+
+	tests := []struct {
+		name            string
+		local, imported types.QuestionStateDB
+		kind            ChangeKind
+		tags            []string
+		note            string
+	}{
+		{"tags are combined", annotated(types.AnsweredTrue, newer, "", "grep"), annotated(types.AnsweredFalse, older, "", "exam-trap", "grep"),
+			ChangeUpdated, []string{"exam-trap", "grep"}, ""},
+		{"note of the newer side", annotated(types.AnsweredFalse, older, "local"), annotated(types.AnsweredTrue, newer, "imported"),
+			ChangeUpdated, nil, "imported"},
+		{"local note is kept", annotated(types.AnsweredTrue, newer, "local"), annotated(types.AnsweredFalse, older, "imported"),
+			ChangeKept, nil, "local"},
+		{"note of the older side if the newer has none", annotated(types.AnsweredTrue, newer, ""), annotated(types.AnsweredFalse, older, "imported"),
+			ChangeUpdated, nil, "imported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, _ := Merge([]types.QuestionStateDB{tt.local}, []types.QuestionStateDB{tt.imported})
+			if len(changes) != 1 {
+				t.Fatalf("got %d changes, want 1", len(changes))
+			}
+			merged := changes[0].Merged
+			if changes[0].Kind != tt.kind || !reflect.DeepEqual(merged.Tags, tt.tags) || merged.Note != tt.note {
+				t.Errorf("got %s with tags %q and note %q, want %s with %q and %q", changes[0].Kind, merged.Tags, merged.Note, tt.kind, tt.tags, tt.note)
+			}
+		})
+	}
+}
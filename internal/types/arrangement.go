@@ -0,0 +1,193 @@
+package types
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// QuestionKindOrdering is the Kind of questions answered by putting the
+	// Ordering items in order, e.g. the stages of the boot process.
+	QuestionKindOrdering = "ordering"
+	// QuestionKindMatching is the Kind of questions answered by pairing every
+	// Left of Matching with a Right, e.g. runlevels with systemd targets.
+	QuestionKindMatching = "matching"
+)
+
+// Pair is a correct pair of a matching question. Several Lefts may share
+// a Right.
+type Pair struct {
+	Left  string `bson:"left"`
+	Right string `bson:"right"`
+}
+
+// IsArranged reports whether the question is answered by ordering or
+// matching items.
+func (q *Question) IsArranged() bool {
+	switch q.Kind {
+	case QuestionKindOrdering:
+		return len(q.Ordering) > 0
+	case QuestionKindMatching:
+		return len(q.Matching) > 0
+	}
+	return false
+}
+
+// ValidateArrangement reports ordering questions with less than two items,
+// matching questions with less than two pairs or Rights and empty or repeated
+// items, whose position would be ambiguous.
+func (q *Question) ValidateArrangement() error {
+	items := q.Ordering
+	if q.Kind == QuestionKindMatching {
+		items = make([]string, 0, len(q.Matching))
+		for _, pair := range q.Matching {
+			if pair.Right == "" {
+				return fmt.Errorf("pair %q has no right", pair.Left)
+			}
+			items = append(items, pair.Left)
+		}
+		if len(items) >= 2 && len(q.MatchOptions()) < 2 {
+			return fmt.Errorf("all pairs have the same right")
+		}
+	}
+	if len(items) < 2 {
+		return fmt.Errorf("at least two items are needed")
+	}
+	for i, item := range items {
+		if item == "" {
+			return fmt.Errorf("item %d is empty", i+1)
+		}
+		if slices.Contains(items[:i], item) {
+			return fmt.Errorf("item %q is listed twice", item)
+		}
+	}
+	return nil
+}
+
+// MatchOptions returns the distinct Rights of a matching question in
+// alphabetical order, so their order does not give the pairs away.
+func (q *Question) MatchOptions() []string {
+	var options []string
+	for _, pair := range q.Matching {
+		if !slices.Contains(options, pair.Right) {
+			options = append(options, pair.Right)
+		}
+	}
+	sort.Strings(options)
+	return options
+}
+
+// GetArrangement returns the answer of an ordering or matching question: for
+// ordering questions the indexes of the Ordering items in the arranged order,
+// for matching questions the index of the MatchOptions picked for every pair,
+// -1 if none was. It is stored as typed answer, e.g. "2,0,1". GetArrangement
+// returns nil if the question was not answered or the stored arrangement does
+// not fit the question any more.
+func (q *Question) GetArrangement() []int {
+	if !q.IsArranged() || q.typedAnswer == "" {
+		return nil
+	}
+	size, limit := len(q.Ordering), len(q.Ordering)
+	if q.Kind == QuestionKindMatching {
+		size, limit = len(q.Matching), len(q.MatchOptions())
+	}
+	fields := strings.Split(q.typedAnswer, ",")
+	if len(fields) != size {
+		return nil
+	}
+	arrangement := make([]int, 0, size)
+	for _, field := range fields {
+		i, err := strconv.Atoi(field)
+		if err != nil || i < -1 || i >= limit {
+			return nil
+		}
+		arrangement = append(arrangement, i)
+	}
+	return arrangement
+}
+
+// Arrange stores arrangement as answer and sets the answered state, true
+// only if every item is in place.
+func (q *Question) Arrange(arrangement []int) AnsweredState {
+	fields := make([]string, 0, len(arrangement))
+	for _, i := range arrangement {
+		fields = append(fields, strconv.Itoa(i))
+	}
+	q.SetTypedAnswer(strings.Join(fields, ","))
+	if q.IsAnsweredCorrectly() {
+		q.AnsweredState = AnsweredTrue
+	} else {
+		q.AnsweredState = AnsweredFalse
+	}
+	return q.AnsweredState
+}
+
+// InitialArrangement returns the arrangement shown before the question is
+// answered: the Ordering items shuffled, but never in order, or no match
+// picked. The shuffle only depends on the question ID, so it does not change
+// between sessions.
+func (q *Question) InitialArrangement() []int {
+	if q.Kind == QuestionKindMatching {
+		arrangement := make([]int, len(q.Matching))
+		for i := range arrangement {
+			arrangement[i] = -1
+		}
+		return arrangement
+	}
+	arrangement := make([]int, len(q.Ordering))
+	for i := range arrangement {
+		arrangement[i] = i
+	}
+	if len(arrangement) < 2 {
+		return arrangement
+	}
+	rng := rand.New(rand.NewSource(int64(q.ID)))
+	for q.arrangedCorrectly(arrangement) == len(arrangement) {
+		rng.Shuffle(len(arrangement), func(i, j int) {
+			arrangement[i], arrangement[j] = arrangement[j], arrangement[i]
+		})
+	}
+	return arrangement
+}
+
+// Credit returns the share of the question answered correctly, between 0 and
+// 1. Ordering and matching questions earn partial credit for every item in
+// place, other questions are either right or wrong.
+func (q *Question) Credit() float64 {
+	if !q.IsArranged() {
+		if q.IsAnsweredCorrectly() {
+			return 1
+		}
+		return 0
+	}
+	arrangement := q.GetArrangement()
+	if arrangement == nil {
+		return 0
+	}
+	return float64(q.arrangedCorrectly(arrangement)) / float64(len(arrangement))
+}
+
+// arrangedCorrectly returns the number of items of arrangement in place. An
+// ordering item is in place at its index, a pair if its Right is picked.
+func (q *Question) arrangedCorrectly(arrangement []int) int {
+	var options []string
+	if q.Kind == QuestionKindMatching {
+		options = q.MatchOptions()
+	}
+	correct := 0
+	for i, picked := range arrangement {
+		switch {
+		case q.Kind == QuestionKindMatching:
+			if picked >= 0 && picked < len(options) && i < len(q.Matching) && options[picked] == q.Matching[i].Right {
+				correct++
+			}
+		case picked == i:
+			correct++
+		}
+	}
+	return correct
+}
@@ -0,0 +1,127 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func orderingQuestion() *Question {
+	return &Question{ID: 7, Kind: QuestionKindOrdering, Ordering: []string{"BIOS", "boot loader", "kernel", "init"}}
+}
+
+func matchingQuestion() *Question {
+	return &Question{ID: 8, Kind: QuestionKindMatching, Matching: []Pair{
+		{Left: "runlevel 3", Right: "multi-user.target"},
+		{Left: "runlevel 5", Right: "graphical.target"},
+		{Left: "runlevel 2", Right: "multi-user.target"},
+	}}
+}
+
+func TestCredit(t *testing.T) {
+	// MatchOptions are graphical.target, multi-user.target
+	tests := []struct {
+		name        string
+		question    *Question
+		arrangement []int
+		credit      float64
+		state       AnsweredState
+	}{
+		{"ordering in order", orderingQuestion(), []int{0, 1, 2, 3}, 1, AnsweredTrue},
+		{"ordering swapped pair", orderingQuestion(), []int{0, 2, 1, 3}, 0.5, AnsweredFalse},
+		{"ordering reversed", orderingQuestion(), []int{3, 2, 1, 0}, 0, AnsweredFalse},
+		{"ordering one in place", orderingQuestion(), []int{1, 2, 0, 3}, 0.25, AnsweredFalse},
+		{"matching all pairs", matchingQuestion(), []int{1, 0, 1}, 1, AnsweredTrue},
+		{"matching one pair wrong", matchingQuestion(), []int{1, 0, 0}, 2.0 / 3, AnsweredFalse},
+		{"matching pairs not picked", matchingQuestion(), []int{1, -1, -1}, 1.0 / 3, AnsweredFalse},
+		{"matching nothing picked", matchingQuestion(), []int{-1, -1, -1}, 0, AnsweredFalse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if state := tt.question.Arrange(tt.arrangement); state != tt.state {
+				t.Errorf("Arrange(%v) = %v, want %v", tt.arrangement, state, tt.state)
+			}
+			if got := tt.question.Credit(); got != tt.credit {
+				t.Errorf("Credit() = %v, want %v", got, tt.credit)
+			}
+			if got := tt.question.IsAnsweredCorrectly(); got != (tt.credit == 1) {
+				t.Errorf("IsAnsweredCorrectly() = %v with credit %v", got, tt.credit)
+			}
+		})
+	}
+
+	choice := &Question{Answers: []*Answer{{AnswerID: "a", IsCorrect: true}, {AnswerID: "b", IsCorrect: true}}}
+	choice.Answers[0].SetIsMarked(true)
+	if got := choice.Credit(); got != 0 {
+		t.Errorf("choice question with half the answers: Credit() = %v, want no partial credit", got)
+	}
+	choice.Answers[1].SetIsMarked(true)
+	if got := choice.Credit(); got != 1 {
+		t.Errorf("choice question answered correctly: Credit() = %v, want 1", got)
+	}
+}
+
+func TestGetArrangement(t *testing.T) {
+	tests := []struct {
+		name     string
+		question *Question
+		typed    string
+		want     []int
+	}{
+		{"ordering", orderingQuestion(), "3,1,0,2", []int{3, 1, 0, 2}},
+		{"matching with open pairs", matchingQuestion(), "1,-1,0", []int{1, -1, 0}},
+		{"not answered", orderingQuestion(), "", nil},
+		{"too few items", orderingQuestion(), "0,1,2", nil},
+		{"index out of range", orderingQuestion(), "0,1,2,4", nil},
+		{"match option out of range", matchingQuestion(), "0,1,2", nil},
+		{"not a number", orderingQuestion(), "0,1,x,3", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.question.SetTypedAnswer(tt.typed)
+			if got := tt.question.GetArrangement(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetArrangement() = %v, want %v", got, tt.want)
+			}
+			if tt.want == nil && tt.question.Credit() != 0 {
+				t.Errorf("Credit() = %v for an unusable arrangement", tt.question.Credit())
+			}
+		})
+	}
+}
+
+func TestInitialArrangement(t *testing.T) {
+	question := orderingQuestion()
+	initial := question.InitialArrangement()
+	if question.Arrange(initial) == AnsweredTrue {
+		t.Errorf("initial arrangement %v is already in order", initial)
+	}
+	if again := orderingQuestion().InitialArrangement(); !reflect.DeepEqual(initial, again) {
+		t.Errorf("initial arrangement changed from %v to %v", initial, again)
+	}
+	if got := matchingQuestion().InitialArrangement(); !reflect.DeepEqual(got, []int{-1, -1, -1}) {
+		t.Errorf("matching InitialArrangement() = %v, want no match picked", got)
+	}
+}
+
+func TestValidateArrangement(t *testing.T) {
+	tests := []struct {
+		name     string
+		question *Question
+		valid    bool
+	}{
+		{"ordering", orderingQuestion(), true},
+		{"matching", matchingQuestion(), true},
+		{"one item", &Question{Kind: QuestionKindOrdering, Ordering: []string{"BIOS"}}, false},
+		{"empty item", &Question{Kind: QuestionKindOrdering, Ordering: []string{"BIOS", ""}}, false},
+		{"repeated item", &Question{Kind: QuestionKindOrdering, Ordering: []string{"BIOS", "kernel", "BIOS"}}, false},
+		{"pair without right", &Question{Kind: QuestionKindMatching, Matching: []Pair{{Left: "a", Right: "x"}, {Left: "b"}}}, false},
+		{"same right for all", &Question{Kind: QuestionKindMatching, Matching: []Pair{{Left: "a", Right: "x"}, {Left: "b", Right: "x"}}}, false},
+		{"repeated left", &Question{Kind: QuestionKindMatching, Matching: []Pair{{Left: "a", Right: "x"}, {Left: "a", Right: "y"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.question.ValidateArrangement(); (err == nil) != tt.valid {
+				t.Errorf("ValidateArrangement() = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
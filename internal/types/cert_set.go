@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"maps"
 	"slices"
+	"sort"
+	"time"
 )
 
 type CertificationSet struct {
@@ -29,8 +31,7 @@ func (c *CertificationSet) GetQuestionsForTestset(id string, filterCorrect bool,
 	markedAnswer := func(question *Question, markedAnswers []QuestionStateDB) {
 		for _, questionState := range stateDB {
 			if question.ID == questionState.QuestionID {
-				question.AnsweredState = questionState.AnsweredState
-				question.SetIsImportant(questionState.Important)
+				question.applyState(questionState)
 				for _, answer := range question.Answers {
 					for _, markedAnswer := range questionState.MarkedAnswers {
 						if answer.AnswerID == markedAnswer {
@@ -68,7 +69,7 @@ func (c *CertificationSet) GetQuestionsForTestset(id string, filterCorrect bool,
 	for _, questionID := range testset.QuestionsIds {
 		question, ok := c.Questions[questionID]
 		if !ok {
-			return nil, fmt.Errorf("questionid not found%s", questionID)
+			return nil, fmt.Errorf("questionid not found %d", questionID)
 		}
 
 		markedAnswer(question, stateDB)
@@ -104,3 +105,75 @@ func (c *CertificationSet) GetQuestionsForTestset(id string, filterCorrect bool,
 
 	return questions, nil
 }
+
+// GetQuestionsByIDs returns the questions with the given IDs in that order with
+// their stored state applied. IDs no longer in the certification set are
+// skipped and returned as missing.
+func (c *CertificationSet) GetQuestionsByIDs(ids []int, stateDB []QuestionStateDB) (questions []*Question, missing []int) {
+	states := make(map[int]QuestionStateDB, len(stateDB))
+	for _, questionState := range stateDB {
+		states[questionState.QuestionID] = questionState
+	}
+
+	for _, id := range ids {
+		question, ok := c.Questions[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		question.ResetAnsweredState()
+		if questionState, ok := states[id]; ok {
+			question.applyState(questionState)
+			for _, answer := range question.Answers {
+				answer.SetIsMarked(slices.Contains(questionState.MarkedAnswers, answer.AnswerID))
+			}
+		}
+		questions = append(questions, question)
+	}
+	return questions, missing
+}
+
+// GetDueQuestions returns the answered questions whose spaced-repetition review
+// is due at now, most overdue first and harder questions (lower ease) before
+// easier ones. The questions are returned unmarked so they can be answered again.
+func (c *CertificationSet) GetDueQuestions(stateDB []QuestionStateDB, now time.Time) []*Question {
+	type dueQuestion struct {
+		question *Question
+		review   ReviewState
+	}
+
+	var due []dueQuestion
+	for _, questionState := range stateDB {
+		if questionState.Attempts == 0 && questionState.AnsweredState == AnsweredUnknown {
+			continue
+		}
+		if !questionState.Review.IsDue(now) {
+			continue
+		}
+		question, ok := c.Questions[questionState.QuestionID]
+		if !ok {
+			continue
+		}
+		question.ResetAnsweredState()
+		question.SetIsImportant(questionState.Important)
+		question.SetTags(questionState.Tags)
+		question.SetNote(questionState.Note)
+		due = append(due, dueQuestion{question: question, review: questionState.Review})
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		if !due[i].review.Due.Equal(due[j].review.Due) {
+			return due[i].review.Due.Before(due[j].review.Due)
+		}
+		if due[i].review.EaseFactor != due[j].review.EaseFactor {
+			return due[i].review.EaseFactor < due[j].review.EaseFactor
+		}
+		return due[i].question.ID < due[j].question.ID
+	})
+
+	questions := make([]*Question, 0, len(due))
+	for _, d := range due {
+		questions = append(questions, d.question)
+	}
+	return questions
+}
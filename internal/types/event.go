@@ -0,0 +1,58 @@
+package types
+
+import "time"
+
+type AnswerEventKind string
+
+const (
+	AnswerEventAttempt AnswerEventKind = "attempt"
+	AnswerEventReset   AnswerEventKind = "reset"
+)
+
+// AnswerEvent is a single entry of the append-only answer history.
+type AnswerEvent struct {
+	ID              string
+	Kind            AnswerEventKind
+	Timestamp       time.Time
+	SessionID       string
+	CertificationID string
+	QuestionID      int
+	AnswerIDs       []string
+	// TypedAnswer is the answer typed for a fill-in-the-blank question.
+	TypedAnswer string `json:",omitempty"`
+	Result      AnsweredState
+	TimeSpent   time.Duration
+}
+
+// NewAnswerEvent records the currently marked or typed answer and state of
+// question.
+func NewAnswerEvent(question *Question, certificationID, sessionID string, timeSpent time.Duration) AnswerEvent {
+	answerIDs := make([]string, 0)
+	for _, answer := range question.GetAnsweredOptions() {
+		answerIDs = append(answerIDs, answer.AnswerID)
+	}
+	return AnswerEvent{
+		Kind:            AnswerEventAttempt,
+		Timestamp:       time.Now(),
+		SessionID:       sessionID,
+		CertificationID: certificationID,
+		QuestionID:      question.ID,
+		AnswerIDs:       answerIDs,
+		TypedAnswer:     question.GetTypedAnswer(),
+		Result:          question.AnsweredState,
+		TimeSpent:       timeSpent,
+	}
+}
+
+// NewResetEvent records that the progress of question was reset.
+func NewResetEvent(question *Question, certificationID, sessionID string) AnswerEvent {
+	return AnswerEvent{
+		Kind:            AnswerEventReset,
+		Timestamp:       time.Now(),
+		SessionID:       sessionID,
+		CertificationID: certificationID,
+		QuestionID:      question.ID,
+		AnswerIDs:       []string{},
+		Result:          AnsweredUnknown,
+	}
+}
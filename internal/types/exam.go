@@ -0,0 +1,31 @@
+package types
+
+import "time"
+
+// ExamResult is a finished (submitted or timed out) exam attempt.
+type ExamResult struct {
+	ID              string
+	CertificationID string
+	StartedAt       time.Time
+	FinishedAt      time.Time
+	TimeLimit       time.Duration
+	TimedOut        bool
+	QuestionIDs     []int
+	MarkedAnswers   map[int][]string
+	Correct         int
+	// Points is Correct plus the partial credit of ordering and matching
+	// questions, see Question.Credit. The ScaledScore is based on it.
+	Points      float64
+	Total       int
+	ScaledScore int
+	Passed      bool
+	Testsets    []TestsetScore
+}
+
+// TestsetScore is the per-testset breakdown of an exam attempt.
+type TestsetScore struct {
+	TestsetID   string
+	TestsetName string
+	Correct     int
+	Total       int
+}
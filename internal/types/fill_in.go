@@ -0,0 +1,136 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/shellcmd"
+)
+
+// QuestionKindFillIn is the Kind of questions answered by typing the answer,
+// like the fill-in-the-blank items of the LPIC exams. Questions without a
+// Kind are multiple choice questions answered by marking Answers.
+const QuestionKindFillIn = "fill-in"
+
+// FillIn describes the answers accepted for a fill-in-the-blank question.
+// By default case is ignored, leading and trailing whitespace is dropped and
+// runs of whitespace count as one space, so "LS  -l " is accepted for "ls -l".
+type FillIn struct {
+	// Accepted are the accepted answers. The first one is shown as solution.
+	Accepted []string `bson:"accepted"`
+	// Patterns are regular expressions for alternatives that are hard to
+	// list, e.g. `ls( -[la]+)+`. They have to match the whole normalized
+	// answer.
+	Patterns      []string `bson:"patterns,omitempty" json:",omitempty"`
+	CaseSensitive bool     `bson:"caseSensitive,omitempty" json:",omitempty"`
+	// ExactWhitespace compares whitespace as typed.
+	ExactWhitespace bool `bson:"exactWhitespace,omitempty" json:",omitempty"`
+	// Command compares answers with the Accepted command lines by their
+	// syntax, so option order, clustering and quoting do not matter, see
+	// package shellcmd. Case and whitespace rules then only apply to Patterns.
+	Command bool `bson:"command,omitempty" json:",omitempty"`
+}
+
+// Normalize applies the case and whitespace rules to answer.
+func (f *FillIn) Normalize(answer string) string {
+	if !f.ExactWhitespace {
+		answer = strings.Join(strings.Fields(answer), " ")
+	}
+	if !f.CaseSensitive {
+		answer = strings.ToLower(answer)
+	}
+	return answer
+}
+
+// Accepts reports whether answer is one of the accepted answers or matches
+// one of the patterns after normalization. Invalid patterns never match, see
+// Validate.
+func (f *FillIn) Accepts(answer string) bool {
+	if f.Command && strings.TrimSpace(answer) != "" {
+		if ok, _, err := shellcmd.Judge(answer, f.Accepted); err == nil && ok {
+			return true
+		}
+	}
+	answer = f.Normalize(answer)
+	if answer == "" {
+		return false
+	}
+	for _, accepted := range f.Accepted {
+		if !f.Command && f.Normalize(accepted) == answer {
+			return true
+		}
+	}
+	for _, pattern := range f.Patterns {
+		re, err := f.compile(pattern)
+		if err != nil {
+			continue
+		}
+		if re.MatchString(answer) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate reports a FillIn accepting no answer and patterns that do not
+// compile.
+func (f *FillIn) Validate() error {
+	if len(f.Accepted) == 0 && len(f.Patterns) == 0 {
+		return fmt.Errorf("no accepted answer or pattern")
+	}
+	for _, accepted := range f.Accepted {
+		if f.Normalize(accepted) == "" {
+			return fmt.Errorf("empty accepted answer")
+		}
+		if f.Command {
+			if _, err := shellcmd.Parse(accepted); err != nil {
+				return fmt.Errorf("accepted command %q: %w", accepted, err)
+			}
+		}
+	}
+	for _, pattern := range f.Patterns {
+		// the pattern alone, so the error does not show the added anchors
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		if _, err := f.compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Feedback explains why answer is not accepted by a Command question, e.g.
+// "missing -z". It is empty if answer is accepted or Command is not set.
+func (f *FillIn) Feedback(answer string) string {
+	if !f.Command || strings.TrimSpace(answer) == "" || f.Accepts(answer) {
+		return ""
+	}
+	_, diff, err := shellcmd.Judge(answer, f.Accepted)
+	if err != nil {
+		return fmt.Sprintf("not a valid command line: %v", err)
+	}
+	return diff.String()
+}
+
+// Solution returns the answer shown as solution.
+func (f *FillIn) Solution() string {
+	if len(f.Accepted) > 0 {
+		return f.Accepted[0]
+	}
+	if len(f.Patterns) > 0 {
+		return f.Patterns[0]
+	}
+	return ""
+}
+
+// compile anchors pattern to the whole answer and makes it ignore case
+// unless CaseSensitive is set.
+func (f *FillIn) compile(pattern string) (*regexp.Regexp, error) {
+	flags := ""
+	if !f.CaseSensitive {
+		flags = "(?i)"
+	}
+	return regexp.Compile(flags + "^(?:" + pattern + ")$")
+}
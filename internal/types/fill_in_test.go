@@ -0,0 +1,114 @@
+package types
+
+import "testing"
+
+func TestFillInAccepts(t *testing.T) {
+	tests := []struct {
+		name    string
+		fillIn  FillIn
+		answer  string
+		accepts bool
+	}{
+		{"exact", FillIn{Accepted: []string{"ls -l"}}, "ls -l", true},
+		{"case is ignored", FillIn{Accepted: []string{"ls -l"}}, "LS -L", true},
+		{"whitespace is collapsed", FillIn{Accepted: []string{"ls -l"}}, "  ls \t -l ", true},
+		{"accepted answers are normalized", FillIn{Accepted: []string{" LS  -l"}}, "ls -l", true},
+		{"any accepted answer", FillIn{Accepted: []string{"ls -l", "ls --format=long"}}, "ls --format=long", true},
+		{"other answer", FillIn{Accepted: []string{"ls -l"}}, "ls -a", false},
+		{"spaces are not dropped", FillIn{Accepted: []string{"ls -l"}}, "ls-l", false},
+		{"blank answer", FillIn{Accepted: []string{"ls -l"}}, "   ", false},
+		{"case sensitive", FillIn{Accepted: []string{"ls -l"}, CaseSensitive: true}, "ls -L", false},
+		{"case sensitive match", FillIn{Accepted: []string{"ls -L"}, CaseSensitive: true}, "ls  -L", true},
+		{"exact whitespace", FillIn{Accepted: []string{"ls -l"}, ExactWhitespace: true}, "ls  -l", false},
+		{"exact whitespace match", FillIn{Accepted: []string{"ls  -l"}, ExactWhitespace: true}, "LS  -l", true},
+		{"pattern", FillIn{Patterns: []string{`ls( -[la]+)+`}}, "ls -la -l", true},
+		{"pattern after normalization", FillIn{Patterns: []string{`ls( -[la]+)+`}}, " LS   -AL", true},
+		{"pattern matches the whole answer", FillIn{Patterns: []string{`ls -l`}}, "ls -la", false},
+		{"pattern alternatives are anchored", FillIn{Patterns: []string{`ls|dir`}}, "ls -l", false},
+		{"case sensitive pattern", FillIn{Patterns: []string{`ls -l`}, CaseSensitive: true}, "ls -L", false},
+		{"invalid pattern never matches", FillIn{Patterns: []string{`ls (`}}, "ls (", false},
+		{"accepted or pattern", FillIn{Accepted: []string{"dmesg"}, Patterns: []string{`journalctl( -k| --dmesg)`}}, "journalctl -k", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fillIn.Accepts(tt.answer); got != tt.accepts {
+				t.Errorf("Accepts(%q) = %v, want %v", tt.answer, got, tt.accepts)
+			}
+		})
+	}
+}
+
+func TestFillInValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		fillIn FillIn
+		valid  bool
+	}{
+		{"accepted answer", FillIn{Accepted: []string{"ls -l"}}, true},
+		{"pattern", FillIn{Patterns: []string{`ls( -l)?`}}, true},
+		{"nothing accepted", FillIn{}, false},
+		{"blank accepted answer", FillIn{Accepted: []string{"ls -l", "  "}}, false},
+		{"invalid pattern", FillIn{Accepted: []string{"ls -l"}, Patterns: []string{`ls (`}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fillIn.Validate(); (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestAnswerFillIn(t *testing.T) {
+	question := &Question{ID: 1, Kind: QuestionKindFillIn, FillIn: &FillIn{Accepted: []string{"uname -r"}}}
+	for _, tt := range []struct {
+		answer string
+		want   AnsweredState
+	}{
+		{"uname -a", AnsweredFalse},
+		{"Uname  -r", AnsweredTrue},
+		{" ", AnsweredUnknown},
+	} {
+		if got := question.AnswerFillIn(tt.answer); got != tt.want || question.AnsweredState != tt.want {
+			t.Errorf("AnswerFillIn(%q) = %v, want %v", tt.answer, got, tt.want)
+		}
+		if question.GetTypedAnswer() != tt.answer {
+			t.Errorf("typed answer = %q, want %q", question.GetTypedAnswer(), tt.answer)
+		}
+	}
+	if got := question.FillIn.Solution(); got != "uname -r" {
+		t.Errorf("Solution() = %q, want the first accepted answer", got)
+	}
+}
+
+func TestFillInCommand(t *testing.T) {
+	fillIn := FillIn{Accepted: []string{"tar -xzf backup.tgz"}, Patterns: []string{`gunzip -c backup\.tgz \| tar x(f -)?`}, Command: true}
+	tests := []struct {
+		answer   string
+		accepts  bool
+		feedback string
+	}{
+		{"tar xzf backup.tgz", true, ""},
+		{"tar -z -x -f 'backup.tgz'", true, ""},
+		{"GUNZIP -c backup.tgz | tar x", true, ""},
+		{"tar -xfz backup.tgz", false, "missing -f backup.tgz, -z; extra -f z; tar arguments backup.tgz instead of none"},
+		{"tar -xvzf backup.tgz", false, "extra -v"},
+		{"TAR -xzf backup.tgz", false, "command TAR instead of tar"},
+		{"tar 'xzf", false, "not a valid command line: 1:5: reached EOF without closing quote '"},
+		{"", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.answer, func(t *testing.T) {
+			if got := fillIn.Accepts(tt.answer); got != tt.accepts {
+				t.Errorf("Accepts(%q) = %v, want %v", tt.answer, got, tt.accepts)
+			}
+			if got := fillIn.Feedback(tt.answer); got != tt.feedback {
+				t.Errorf("Feedback(%q) = %q, want %q", tt.answer, got, tt.feedback)
+			}
+		})
+	}
+
+	if err := (&FillIn{Accepted: []string{"tar 'xzf"}, Command: true}).Validate(); err == nil {
+		t.Error("Validate accepted a command line that does not parse")
+	}
+}
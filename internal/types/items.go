@@ -0,0 +1,34 @@
+package types
+
+// ItemStates returns the answered state of every item of questions, keyed by
+// ItemID. Near-duplicates are one item: it is answered wrong if one of them
+// was answered wrong and correct if the others answered are correct.
+func ItemStates(questions []*Question) map[int]AnsweredState {
+	items := make(map[int]AnsweredState, len(questions))
+	for _, question := range questions {
+		id := question.ItemID()
+		state, ok := items[id]
+		switch {
+		case !ok, state == AnsweredUnknown:
+			items[id] = question.AnsweredState
+		case question.AnsweredState == AnsweredFalse:
+			items[id] = AnsweredFalse
+		}
+	}
+	return items
+}
+
+// CountItemStates counts the items of ItemStates per answered state.
+func CountItemStates(questions []*Question) (correct, incorrect, unknown int) {
+	for _, state := range ItemStates(questions) {
+		switch state {
+		case AnsweredTrue:
+			correct++
+		case AnsweredFalse:
+			incorrect++
+		default:
+			unknown++
+		}
+	}
+	return correct, incorrect, unknown
+}
@@ -0,0 +1,21 @@
+package types
+
+import "time"
+
+// ProgressSummary aggregates the stored question states of a namespace.
+type ProgressSummary struct {
+	Questions int
+	Correct   int
+	Incorrect int
+	Important int
+	Attempts  int
+}
+
+// DailyProgress aggregates the answer attempts of one day (YYYY-MM-DD, UTC).
+type DailyProgress struct {
+	Day       string
+	Attempts  int
+	Correct   int
+	Incorrect int
+	TimeSpent time.Duration
+}
@@ -1,5 +1,10 @@
 package types
 
+import (
+	"slices"
+	"strings"
+)
+
 type Question struct {
 	ID            int           `bson:"_id" json:"ID,string"`
 	Text          string        `bson:"questionText"`
@@ -7,6 +12,52 @@ type Question struct {
 	Explanation   string        `bson:"explanation,omitempty"`
 	AnsweredState AnsweredState `bson:"answeredState,omitempty"`
 	isImportant   bool          `bson:"important,omitempty"`
+	tags          []string
+	note          string
+	duplicateOf   int
+	typedAnswer   string
+	// Objectives are the IDs of the exam objectives the question covers,
+	// e.g. 103.7. Questions without them are classified when loaded.
+	Objectives []string `bson:"objectives,omitempty" json:",omitempty"`
+	// Kind is empty for multiple choice questions, or QuestionKindFillIn,
+	// QuestionKindOrdering or QuestionKindMatching.
+	Kind string `bson:"kind,omitempty" json:",omitempty"`
+	// FillIn holds the accepted answers of fill-in-the-blank questions, which
+	// have no Answers.
+	FillIn *FillIn `bson:"fillIn,omitempty" json:",omitempty"`
+	// Ordering holds the items of ordering questions in the correct order.
+	Ordering []string `bson:"ordering,omitempty" json:",omitempty"`
+	// Matching holds the correct pairs of matching questions.
+	Matching []Pair `bson:"matching,omitempty" json:",omitempty"`
+}
+
+// IsFillIn reports whether the question is answered by typing the answer.
+func (q *Question) IsFillIn() bool {
+	return q.Kind == QuestionKindFillIn && q.FillIn != nil
+}
+
+// SolutionTexts returns the texts of the answers, the accepted answers of a
+// fill-in-the-blank question, the items of an ordering question in order or
+// the pairs of a matching question.
+func (q *Question) SolutionTexts() []string {
+	if q.IsFillIn() {
+		return q.FillIn.Accepted
+	}
+	if q.Kind == QuestionKindOrdering {
+		return q.Ordering
+	}
+	if q.Kind == QuestionKindMatching {
+		texts := make([]string, 0, len(q.Matching))
+		for _, pair := range q.Matching {
+			texts = append(texts, pair.Left+" → "+pair.Right)
+		}
+		return texts
+	}
+	texts := make([]string, 0, len(q.Answers))
+	for _, answer := range q.Answers {
+		texts = append(texts, answer.Text)
+	}
+	return texts
 }
 
 func (q *Question) GetAnsweredOptions() []*Answer {
@@ -46,9 +97,129 @@ func (question *Question) GetIsImportant() bool {
 	return question.isImportant
 }
 
+// SetTags sets the personal tags of the question, see NormalizeTags.
+func (question *Question) SetTags(tags []string) *Question {
+	question.tags = NormalizeTags(tags)
+	return question
+}
+
+func (question *Question) GetTags() []string {
+	return question.tags
+}
+
+// HasTag reports whether the question is tagged with one of tags.
+func (question *Question) HasTag(tags ...string) bool {
+	for _, tag := range NormalizeTags(tags) {
+		if slices.Contains(question.tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetNote sets the personal note of the question. It may span several lines.
+func (question *Question) SetNote(note string) *Question {
+	question.note = strings.TrimSpace(note)
+	return question
+}
+
+func (question *Question) GetNote() string {
+	return question.note
+}
+
+// SetDuplicateOf marks the question as a near-duplicate of the question with
+// id, the first question of its cluster. 0 removes the mark.
+func (question *Question) SetDuplicateOf(id int) *Question {
+	question.duplicateOf = id
+	return question
+}
+
+func (question *Question) GetDuplicateOf() int {
+	return question.duplicateOf
+}
+
+// ItemID returns the ID the question is counted under in statistics: the ID
+// of the first question of its cluster of near-duplicates or its own ID.
+func (question *Question) ItemID() int {
+	if question.duplicateOf != 0 {
+		return question.duplicateOf
+	}
+	return question.ID
+}
+
+// SetTypedAnswer sets the answer typed for a fill-in-the-blank question.
+func (question *Question) SetTypedAnswer(answer string) *Question {
+	question.typedAnswer = answer
+	return question
+}
+
+func (question *Question) GetTypedAnswer() string {
+	return question.typedAnswer
+}
+
+// HasAnswer reports whether an answer was marked or, for fill-in-the-blank
+// questions, typed or, for ordering and matching questions, arranged.
+func (q *Question) HasAnswer() bool {
+	if q.IsFillIn() {
+		return strings.TrimSpace(q.typedAnswer) != ""
+	}
+	if q.IsArranged() {
+		return q.GetArrangement() != nil
+	}
+	return len(q.GetAnsweredOptions()) > 0
+}
+
+// AnswerFillIn sets the typed answer of a fill-in-the-blank question and its
+// answered state: unknown if answer is blank, else whether it is accepted.
+func (question *Question) AnswerFillIn(answer string) AnsweredState {
+	question.SetTypedAnswer(answer)
+	switch {
+	case strings.TrimSpace(answer) == "":
+		question.AnsweredState = AnsweredUnknown
+	case question.IsAnsweredCorrectly():
+		question.AnsweredState = AnsweredTrue
+	default:
+		question.AnsweredState = AnsweredFalse
+	}
+	return question.AnsweredState
+}
+
+// applyState sets the stored state of the question apart from its marked
+// answers.
+func (question *Question) applyState(state QuestionStateDB) {
+	question.AnsweredState = state.AnsweredState
+	question.SetIsImportant(state.Important)
+	question.SetTags(state.Tags)
+	question.SetNote(state.Note)
+	question.SetTypedAnswer(state.TypedAnswer)
+}
+
 func (question *Question) ResetAnsweredState() {
 	question.AnsweredState = AnsweredUnknown
+	question.typedAnswer = ""
 	for _, answer := range question.Answers {
 		answer.SetIsMarked(false)
 	}
 }
+
+// IsAnsweredCorrectly reports whether exactly the correct answers are marked,
+// for fill-in-the-blank questions whether the typed answer is accepted and
+// for ordering and matching questions whether every item is in place.
+func (q *Question) IsAnsweredCorrectly() bool {
+	if q.IsFillIn() {
+		return q.FillIn.Accepts(q.typedAnswer)
+	}
+	if q.IsArranged() {
+		arrangement := q.GetArrangement()
+		return arrangement != nil && q.arrangedCorrectly(arrangement) == len(arrangement)
+	}
+	if len(q.Answers) == 0 {
+		return false
+	}
+	for _, answer := range q.Answers {
+		if answer.IsCorrect != answer.GetIsMarked() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// DefaultEaseFactor is the SM-2 ease factor of a question never reviewed.
+	DefaultEaseFactor = 2.5
+	minEaseFactor     = 1.3
+
+	qualityCorrect   = 4
+	qualityIncorrect = 1
+)
+
+// ReviewState holds the spaced-repetition (SM-2) parameters of a question.
+type ReviewState struct {
+	EaseFactor    float64   `json:",omitempty"`
+	IntervalDays  int       `json:",omitempty"`
+	Repetitions   int       `json:",omitempty"`
+	Lapses        int       `json:",omitempty"`
+	Due           time.Time `json:",omitzero"`
+	LastSessionID string    `json:",omitempty"`
+}
+
+// IsDue reports whether the question should be reviewed at now. Questions
+// answered before scheduling existed have no due date and are due at once.
+func (r ReviewState) IsDue(now time.Time) bool {
+	return r.Due.IsZero() || !r.Due.After(now)
+}
+
+// Next schedules the following review with SM-2. A correct answer grows the
+// interval by the ease factor, a wrong answer resets it to one day.
+func (r ReviewState) Next(correct bool, now time.Time) ReviewState {
+	if r.EaseFactor == 0 {
+		r.EaseFactor = DefaultEaseFactor
+	}
+
+	quality := qualityIncorrect
+	if correct {
+		quality = qualityCorrect
+	}
+
+	if quality >= 3 {
+		switch r.Repetitions {
+		case 0:
+			r.IntervalDays = 1
+		case 1:
+			r.IntervalDays = 6
+		default:
+			r.IntervalDays = int(math.Round(float64(r.IntervalDays) * r.EaseFactor))
+		}
+		r.Repetitions++
+	} else {
+		r.Repetitions = 0
+		r.IntervalDays = 1
+		r.Lapses++
+	}
+
+	q := float64(5 - quality)
+	r.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if r.EaseFactor < minEaseFactor {
+		r.EaseFactor = minEaseFactor
+	}
+
+	r.Due = now.AddDate(0, 0, r.IntervalDays)
+	return r
+}
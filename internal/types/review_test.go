@@ -0,0 +1,67 @@
+package types
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var reviewStart = time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)
+
+func TestReviewStateNext(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers []bool
+		want    ReviewState
+	}{
+		{"first correct", []bool{true},
+			ReviewState{EaseFactor: 2.5, IntervalDays: 1, Repetitions: 1}},
+		{"second correct", []bool{true, true},
+			ReviewState{EaseFactor: 2.5, IntervalDays: 6, Repetitions: 2}},
+		{"interval grows by the ease", []bool{true, true, true},
+			ReviewState{EaseFactor: 2.5, IntervalDays: 15, Repetitions: 3}},
+		{"first wrong", []bool{false},
+			ReviewState{EaseFactor: 1.96, IntervalDays: 1, Lapses: 1}},
+		{"lapse resets the interval", []bool{true, true, true, false},
+			ReviewState{EaseFactor: 1.96, IntervalDays: 1, Lapses: 1}},
+		{"relearning uses the lowered ease", []bool{false, true, true, true},
+			ReviewState{EaseFactor: 1.96, IntervalDays: 12, Repetitions: 3, Lapses: 1}},
+		{"ease floor", []bool{false, false, false, false},
+			ReviewState{EaseFactor: minEaseFactor, IntervalDays: 1, Lapses: 4}},
+		{"correct keeps the floor", []bool{false, false, false, true, true, true},
+			ReviewState{EaseFactor: minEaseFactor, IntervalDays: 8, Repetitions: 3, Lapses: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r ReviewState
+			now := reviewStart
+			for _, correct := range tt.answers {
+				r = r.Next(correct, now)
+				now = r.Due
+			}
+			if math.Abs(r.EaseFactor-tt.want.EaseFactor) > 1e-9 || r.IntervalDays != tt.want.IntervalDays ||
+				r.Repetitions != tt.want.Repetitions || r.Lapses != tt.want.Lapses {
+				t.Errorf("got ease %.2f, interval %d, repetitions %d, lapses %d; want %.2f, %d, %d, %d",
+					r.EaseFactor, r.IntervalDays, r.Repetitions, r.Lapses,
+					tt.want.EaseFactor, tt.want.IntervalDays, tt.want.Repetitions, tt.want.Lapses)
+			}
+		})
+	}
+}
+
+func TestReviewStateDue(t *testing.T) {
+	var r ReviewState
+	if !r.IsDue(reviewStart) {
+		t.Error("a question never reviewed is not due")
+	}
+	r = r.Next(true, reviewStart)
+	if want := reviewStart.AddDate(0, 0, 1); !r.Due.Equal(want) {
+		t.Errorf("Due = %v, want %v", r.Due, want)
+	}
+	if r.IsDue(reviewStart.Add(23 * time.Hour)) {
+		t.Error("due before the interval passed")
+	}
+	if !r.IsDue(reviewStart.AddDate(0, 0, 1)) {
+		t.Error("not due when the interval passed")
+	}
+}
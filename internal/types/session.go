@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 type CertificationSession struct {
 	QuestionIndex        map[string]int `bson:"currentQuestionIndex"`
 	CurrentQuestionIndex int            `bson:"currentQuestionIndex"`
@@ -50,3 +52,41 @@ func (s *CertificationSession) GetAndDecIndex() int {
 	s.CurrentQuestionIndex--
 	return s.CurrentQuestionIndex
 }
+
+// SetCurrentQuestionIndex moves the session to index, clamped to the testset.
+func (s *CertificationSession) SetCurrentQuestionIndex(index int) int {
+	if index < 0 || len(s.Testset.QuestionsIds) == 0 {
+		index = 0
+	} else if index >= len(s.Testset.QuestionsIds) {
+		index = len(s.Testset.QuestionsIds) - 1
+	}
+	s.CurrentQuestionIndex = index
+	return s.CurrentQuestionIndex
+}
+
+const (
+	SessionModeTestset = "testset"
+	SessionModeRandom  = "random"
+	SessionModeReview  = "review"
+	// SessionModeObjective sessions serve the questions of one exam objective
+	// or topic across all testsets.
+	SessionModeObjective = "objective"
+)
+
+// SessionState is the stored part of a CertificationSession. It keeps the
+// question order of the session, so resuming it shows the same questions in
+// the same order even if the filters would select others by now.
+type SessionState struct {
+	ID                   string
+	CertificationID      string
+	Mode                 string
+	TestsetID            string
+	FilterCorrect        bool     `json:",omitempty"`
+	OnlyImportant        bool     `json:",omitempty"`
+	Objective            string   `json:",omitempty"`
+	Tags                 []string `json:",omitempty"`
+	QuestionIDs          []int
+	CurrentQuestionIndex int
+	StartedAt            time.Time
+	UpdatedAt            time.Time
+}
@@ -0,0 +1,56 @@
+package types
+
+import "testing"
+
+func TestCertificationSessionIndex(t *testing.T) {
+	session := NewCertificationSession(&Testset{QuestionsIds: []int{4, 8, 15}})
+	steps := []struct {
+		name string
+		move func() int
+		want int
+	}{
+		{"back from the first wraps", session.GetAndDecIndex, 2},
+		{"forward from the last wraps", session.GetAndIncIndex, 0},
+		{"forward", session.GetAndIncIndex, 1},
+		{"back", session.GetAndDecIndex, 0},
+		{"resume past the end", func() int { return session.SetCurrentQuestionIndex(7) }, 2},
+		{"resume before the start", func() int { return session.SetCurrentQuestionIndex(-1) }, 0},
+		{"resume", func() int { return session.SetCurrentQuestionIndex(1) }, 1},
+	}
+	for _, step := range steps {
+		if got := step.move(); got != step.want || session.GetCurrentQuestionIndex() != step.want {
+			t.Errorf("%s: index %d, want %d", step.name, got, step.want)
+		}
+	}
+
+	empty := NewCertificationSession(&Testset{})
+	if empty.GetAndIncIndex() != 0 || empty.GetAndDecIndex() != 0 || empty.SetCurrentQuestionIndex(3) != 0 {
+		t.Errorf("a session without questions moved to %d", empty.GetCurrentQuestionIndex())
+	}
+}
+
+func TestGetQuestionsByIDs(t *testing.T) {
+	certSet := &CertificationSet{Questions: map[int]*Question{}}
+	for _, id := range []int{1, 2, 3} {
+		certSet.Questions[id] = &Question{ID: id, Answers: []*Answer{{AnswerID: "a", IsCorrect: true}, {AnswerID: "b"}}}
+	}
+	// marked before, e.g. by another session
+	certSet.Questions[2].Answers[1].SetIsMarked(true)
+	certSet.Questions[2].AnsweredState = AnsweredFalse
+
+	stateDB := []QuestionStateDB{{QuestionID: 3, MarkedAnswers: []string{"a"}, AnsweredState: AnsweredTrue, Important: true}}
+	questions, missing := certSet.GetQuestionsByIDs([]int{3, 9, 2}, stateDB)
+
+	if len(questions) != 2 || questions[0].ID != 3 || questions[1].ID != 2 {
+		t.Fatalf("got %d questions, want questions 3 and 2 in that order", len(questions))
+	}
+	if len(missing) != 1 || missing[0] != 9 {
+		t.Errorf("missing = %v, want [9]", missing)
+	}
+	if q := questions[0]; q.AnsweredState != AnsweredTrue || !q.GetIsImportant() || !q.Answers[0].GetIsMarked() || q.Answers[1].GetIsMarked() {
+		t.Errorf("stored state of question 3 was not applied: %+v", q)
+	}
+	if q := questions[1]; q.AnsweredState != AnsweredUnknown || len(q.GetAnsweredOptions()) != 0 {
+		t.Errorf("question 2 without stored state is not unanswered: %+v", q)
+	}
+}
@@ -1,8 +1,50 @@
 package types
 
+import "time"
+
 type QuestionStateDB struct {
-	QuestionID    int
-	MarkedAnswers []string
-	AnsweredState AnsweredState
-	Important     bool
+	QuestionID     int
+	MarkedAnswers  []string
+	AnsweredState  AnsweredState
+	Important      bool
+	Tags           []string  `json:",omitempty"`
+	Note           string    `json:",omitempty"`
+	TypedAnswer    string    `json:",omitempty"`
+	Attempts       int       `json:",omitempty"`
+	LastAnsweredAt time.Time `json:",omitzero"`
+	ResetAt        time.Time `json:",omitzero"`
+	Review         ReviewState
+}
+
+// ChangedAt returns when the question was last answered or reset.
+func (s QuestionStateDB) ChangedAt() time.Time {
+	if s.ResetAt.After(s.LastAnsweredAt) {
+		return s.ResetAt
+	}
+	return s.LastAnsweredAt
+}
+
+// Apply folds an answer event into the state. The current state of a question
+// is the result of applying all of its events in order. Only the first graded
+// answer of a session moves the spaced-repetition schedule.
+func (s *QuestionStateDB) Apply(event AnswerEvent) {
+	s.QuestionID = event.QuestionID
+	switch event.Kind {
+	case AnswerEventReset:
+		s.MarkedAnswers = []string{}
+		s.TypedAnswer = ""
+		s.AnsweredState = AnsweredUnknown
+		s.Review = ReviewState{}
+		s.ResetAt = event.Timestamp
+	default:
+		s.MarkedAnswers = append([]string{}, event.AnswerIDs...)
+		s.TypedAnswer = event.TypedAnswer
+		s.AnsweredState = event.Result
+		s.Attempts++
+		s.LastAnsweredAt = event.Timestamp
+		if event.Result != AnsweredUnknown && (event.SessionID == "" || event.SessionID != s.Review.LastSessionID) {
+			s.Review = s.Review.Next(event.Result == AnsweredTrue, event.Timestamp)
+			s.Review.LastSessionID = event.SessionID
+		}
+	}
 }
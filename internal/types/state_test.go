@@ -0,0 +1,125 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuestionStateDBOmitsZeroTimes(t *testing.T) {
+	data, err := json.Marshal(QuestionStateDB{QuestionID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"LastAnsweredAt", "Due"} {
+		if strings.Contains(string(data), field) {
+			t.Errorf("unanswered state contains %s: %s", field, data)
+		}
+	}
+
+	answeredAt := time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)
+	data, err = json.Marshal(QuestionStateDB{QuestionID: 1, LastAnsweredAt: answeredAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state QuestionStateDB
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+	if !state.LastAnsweredAt.Equal(answeredAt) {
+		t.Errorf("LastAnsweredAt = %v, want %v", state.LastAnsweredAt, answeredAt)
+	}
+}
+
+func answerEvent(sessionID string, result AnsweredState, at time.Time) AnswerEvent {
+	return AnswerEvent{Kind: AnswerEventAttempt, QuestionID: 1, SessionID: sessionID, Result: result, Timestamp: at}
+}
+
+func TestQuestionStateDBApply(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		name         string
+		events       []AnswerEvent
+		attempts     int
+		repetitions  int
+		lapses       int
+		lastAnswered time.Time
+	}{
+		{
+			name: "only the first graded answer of a session schedules",
+			events: []AnswerEvent{
+				answerEvent("s1", AnsweredFalse, reviewStart),
+				answerEvent("s1", AnsweredTrue, reviewStart.Add(time.Minute)),
+				answerEvent("s1", AnsweredTrue, reviewStart.Add(2*time.Minute)),
+			},
+			attempts: 3, lapses: 1, lastAnswered: reviewStart.Add(2 * time.Minute),
+		},
+		{
+			name: "ungraded answers do not count as the first",
+			events: []AnswerEvent{
+				answerEvent("s1", AnsweredUnknown, reviewStart),
+				answerEvent("s1", AnsweredTrue, reviewStart.Add(time.Minute)),
+			},
+			attempts: 2, repetitions: 1, lastAnswered: reviewStart.Add(time.Minute),
+		},
+		{
+			name: "every session schedules once",
+			events: []AnswerEvent{
+				answerEvent("s1", AnsweredTrue, reviewStart),
+				answerEvent("s2", AnsweredFalse, reviewStart.Add(day)),
+				answerEvent("s2", AnsweredTrue, reviewStart.Add(day+time.Minute)),
+				answerEvent("s3", AnsweredTrue, reviewStart.Add(2*day)),
+			},
+			attempts: 4, repetitions: 1, lapses: 1, lastAnswered: reviewStart.Add(2 * day),
+		},
+		{
+			name: "answers without a session always schedule",
+			events: []AnswerEvent{
+				answerEvent("", AnsweredTrue, reviewStart),
+				answerEvent("", AnsweredTrue, reviewStart.Add(time.Minute)),
+			},
+			attempts: 2, repetitions: 2, lastAnswered: reviewStart.Add(time.Minute),
+		},
+		{
+			name: "a reset clears the schedule",
+			events: []AnswerEvent{
+				answerEvent("s1", AnsweredFalse, reviewStart),
+				{Kind: AnswerEventReset, QuestionID: 1, SessionID: "s1", Timestamp: reviewStart.Add(time.Minute)},
+				answerEvent("s1", AnsweredTrue, reviewStart.Add(2*time.Minute)),
+			},
+			attempts: 2, repetitions: 1, lastAnswered: reviewStart.Add(2 * time.Minute),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var state QuestionStateDB
+			for _, event := range tt.events {
+				state.Apply(event)
+			}
+			if state.Attempts != tt.attempts || state.Review.Repetitions != tt.repetitions || state.Review.Lapses != tt.lapses {
+				t.Errorf("got %d attempts, %d repetitions, %d lapses; want %d, %d, %d",
+					state.Attempts, state.Review.Repetitions, state.Review.Lapses, tt.attempts, tt.repetitions, tt.lapses)
+			}
+			if !state.LastAnsweredAt.Equal(tt.lastAnswered) {
+				t.Errorf("LastAnsweredAt = %v, want %v", state.LastAnsweredAt, tt.lastAnswered)
+			}
+			if last := tt.events[len(tt.events)-1]; state.AnsweredState != last.Result {
+				t.Errorf("AnsweredState = %v, want %v", state.AnsweredState, last.Result)
+			}
+		})
+	}
+}
+
+func TestQuestionStateDBChangedAt(t *testing.T) {
+	var state QuestionStateDB
+	state.Apply(answerEvent("s1", AnsweredFalse, reviewStart))
+	if !state.ChangedAt().Equal(reviewStart) {
+		t.Errorf("after an answer: ChangedAt = %v, want %v", state.ChangedAt(), reviewStart)
+	}
+	resetAt := reviewStart.Add(time.Hour)
+	state.Apply(AnswerEvent{Kind: AnswerEventReset, QuestionID: 1, Timestamp: resetAt})
+	if !state.ResetAt.Equal(resetAt) || !state.LastAnsweredAt.Equal(reviewStart) || !state.ChangedAt().Equal(resetAt) {
+		t.Errorf("after a reset: ResetAt = %v, LastAnsweredAt = %v, ChangedAt = %v", state.ResetAt, state.LastAnsweredAt, state.ChangedAt())
+	}
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"slices"
+	"strings"
+)
+
+// ParseTags splits a comma separated list of tags, e.g. as entered in the
+// client or given with -tag.
+func ParseTags(s string) []string {
+	return NormalizeTags(strings.Split(s, ","))
+}
+
+// NormalizeTags returns the tags in lower case with dashes instead of spaces,
+// sorted and without duplicates, so tags can be compared and stored as is.
+// It returns nil if no tag is left.
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), "-"))
+		if tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
@@ -0,0 +1,73 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"lower case", []string{"Grep"}, []string{"grep"}},
+		{"spaces become dashes", []string{"  exam   Trap "}, []string{"exam-trap"}},
+		{"sorted without duplicates", []string{"sed", "grep", "GREP", "sed"}, []string{"grep", "sed"}},
+		{"blank tags are dropped", []string{"", "  ", "grep"}, []string{"grep"}},
+		{"nothing left", []string{" ", ""}, nil},
+		{"no tags", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTags(tt.tags); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeTags(%q) = %q, want %q", tt.tags, got, tt.want)
+			}
+		})
+	}
+
+	if got := ParseTags("grep, Exam Trap,,grep"); !reflect.DeepEqual(got, []string{"exam-trap", "grep"}) {
+		t.Errorf("ParseTags() = %q, want [exam-trap grep]", got)
+	}
+}
+
+func TestQuestionTags(t *testing.T) {
+	question := (&Question{ID: 1}).SetTags([]string{"grep", "Exam Trap"})
+	if got := question.GetTags(); !reflect.DeepEqual(got, []string{"exam-trap", "grep"}) {
+		t.Errorf("GetTags() = %q", got)
+	}
+	tests := []struct {
+		tags []string
+		has  bool
+	}{
+		{[]string{"grep"}, true},
+		{[]string{"EXAM trap"}, true},
+		{[]string{"sed", "grep"}, true},
+		{[]string{"sed"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := question.HasTag(tt.tags...); got != tt.has {
+			t.Errorf("HasTag(%q) = %v, want %v", tt.tags, got, tt.has)
+		}
+	}
+
+	if got := question.SetNote("\n  check the -E option\nand -P \n").GetNote(); got != "check the -E option\nand -P" {
+		t.Errorf("GetNote() = %q, want the trimmed note", got)
+	}
+}
+
+func TestStoredTagsAndNote(t *testing.T) {
+	certSet := &CertificationSet{Questions: map[int]*Question{
+		1: {ID: 1, Answers: []*Answer{{AnswerID: "a", IsCorrect: true}}},
+	}}
+	states := []QuestionStateDB{{QuestionID: 1, Important: true, Tags: []string{"grep"}, Note: "a note"}}
+	questions, _ := certSet.GetQuestionsByIDs([]int{1}, states)
+	if len(questions) != 1 {
+		t.Fatalf("GetQuestionsByIDs returned %d questions, want 1", len(questions))
+	}
+	question := questions[0]
+	if !question.GetIsImportant() || !question.HasTag("grep") || question.GetNote() != "a note" {
+		t.Errorf("question = important %v, tags %q, note %q; want the stored state", question.GetIsImportant(), question.GetTags(), question.GetNote())
+	}
+}
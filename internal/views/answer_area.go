@@ -0,0 +1,93 @@
+package views
+
+import (
+	"github.com/SqiSch/lpic-cli/internal/types"
+	"github.com/rivo/tview"
+)
+
+// AnswerArea shows the view answering the current question: the
+// QuestionsView of multiple choice questions, the FillInView of
+// fill-in-the-blank questions and the ArrangeView of ordering and matching
+// questions.
+type AnswerArea struct {
+	*tview.Pages
+	choice   *QuestionsView
+	fillIn   *FillInView
+	arrange  *ArrangeView
+	question *types.Question
+}
+
+func NewAnswerArea(choice *QuestionsView, fillIn *FillInView, arrange *ArrangeView) *AnswerArea {
+	a := &AnswerArea{Pages: tview.NewPages(), choice: choice, fillIn: fillIn, arrange: arrange}
+	a.AddPage("choice", choice, true, true).
+		AddPage("fillIn", fillIn, true, false).
+		AddPage("arrange", arrange, true, false)
+	return a
+}
+
+// SetQuestion shows question in the view matching its kind. The question
+// text is always set through the QuestionsView.
+func (a *AnswerArea) SetQuestion(question *types.Question) {
+	a.question = question
+	a.choice.SetQuestion(question)
+	switch {
+	case question.IsFillIn():
+		a.fillIn.SetQuestion(question)
+		a.SwitchToPage("fillIn")
+	case question.IsArranged():
+		a.arrange.SetQuestion(question)
+		a.SwitchToPage("arrange")
+	default:
+		a.SwitchToPage("choice")
+	}
+}
+
+// SetHideCorrectness suppresses all correct/wrong feedback of the views.
+func (a *AnswerArea) SetHideCorrectness(hide bool) *AnswerArea {
+	a.choice.SetHideCorrectness(hide)
+	a.fillIn.SetHideCorrectness(hide)
+	a.arrange.SetHideCorrectness(hide)
+	return a
+}
+
+// SetDoneFunc sets the handler called when typing or arranging ends, with
+// answered set if the answer was submitted.
+func (a *AnswerArea) SetDoneFunc(handler func(answered bool)) *AnswerArea {
+	a.fillIn.SetDoneFunc(handler)
+	a.arrange.SetDoneFunc(handler)
+	return a
+}
+
+// Editor returns the view to focus for answering the current question with
+// the keyboard, or nil for multiple choice questions, whose answers are
+// marked through the QuestionsView.
+func (a *AnswerArea) Editor() tview.Primitive {
+	switch {
+	case a.question == nil:
+		return nil
+	case a.question.IsFillIn():
+		return a.fillIn
+	case a.question.IsArranged():
+		return a.arrange
+	}
+	return nil
+}
+
+// Editing reports whether an answer is being typed or arranged, so keys are
+// not used as shortcuts.
+func (a *AnswerArea) Editing() bool {
+	return a.fillIn.Typing() || a.arrange.Arranging()
+}
+
+// ShowExplanation shows the explanation and the solution of fill-in, ordering
+// and matching questions.
+func (a *AnswerArea) ShowExplanation() {
+	a.choice.ShowExplanation()
+	switch {
+	case a.question == nil:
+	case a.question.IsFillIn():
+		a.fillIn.ShowSolution()
+	case a.question.IsArranged():
+		a.arrange.ShowSolution()
+	}
+}
@@ -0,0 +1,250 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ArrangeView lets ordering and matching questions be answered with the
+// keyboard. It takes the place of the QuestionsView checkboxes, see
+// AnswerArea. Focusing it starts arranging: Up/Down select an item, Space
+// picks an ordering item up so Up/Down move it, Left/Right pick the match of
+// a pair, Enter submits and Esc restores the last submitted arrangement.
+type ArrangeView struct {
+	*tview.Flex
+	list             *tview.TextView
+	result           *tview.TextView
+	explainationView *tview.TextView
+	question         *types.Question
+	arrangement      []int
+	options          []string
+	cursor           int
+	grabbed          bool
+	hideCorrectness  bool
+	onDone           func(answered bool)
+}
+
+func NewArrangeView(explainationView *tview.TextView) *ArrangeView {
+	a := &ArrangeView{
+		Flex:             tview.NewFlex().SetDirection(tview.FlexRow),
+		list:             tview.NewTextView().SetDynamicColors(true).SetWrap(true),
+		result:           tview.NewTextView().SetDynamicColors(true).SetWrap(true),
+		explainationView: explainationView,
+	}
+	a.list.SetInputCapture(a.handleKey)
+	a.SetBorder(true).SetTitle("Answer")
+	a.AddItem(a.list, 0, 2, true).
+		AddItem(a.result, 0, 1, false)
+	return a
+}
+
+// SetHideCorrectness suppresses all correct/wrong feedback, e.g. during an exam.
+func (a *ArrangeView) SetHideCorrectness(hide bool) *ArrangeView {
+	a.hideCorrectness = hide
+	a.showResult()
+	return a
+}
+
+// SetDoneFunc sets the handler called when arranging ends, with answered set
+// if the arrangement was submitted with Enter.
+func (a *ArrangeView) SetDoneFunc(handler func(answered bool)) *ArrangeView {
+	a.onDone = handler
+	return a
+}
+
+// Arranging reports whether the view has the focus, so keys arrange items
+// instead of being used as shortcuts.
+func (a *ArrangeView) Arranging() bool {
+	return a.list.HasFocus()
+}
+
+func (a *ArrangeView) SetQuestion(question *types.Question) {
+	a.question = question
+	a.options = question.MatchOptions()
+	a.cursor = 0
+	a.grabbed = false
+	a.reset()
+	a.showResult()
+}
+
+// reset restores the submitted arrangement or the initial one.
+func (a *ArrangeView) reset() {
+	a.arrangement = a.question.GetArrangement()
+	if a.arrangement == nil {
+		a.arrangement = a.question.InitialArrangement()
+	}
+	a.draw()
+}
+
+func (a *ArrangeView) matching() bool {
+	return a.question.Kind == types.QuestionKindMatching
+}
+
+func (a *ArrangeView) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyUp:
+		a.move(-1)
+	case tcell.KeyDown:
+		a.move(1)
+	case tcell.KeyLeft:
+		a.pick(-1)
+	case tcell.KeyRight:
+		a.pick(1)
+	case tcell.KeyEnter:
+		a.submit()
+		a.done(true)
+	case tcell.KeyEscape:
+		a.grabbed = false
+		a.reset()
+		a.done(false)
+	case tcell.KeyRune:
+		if event.Rune() == ' ' {
+			if a.matching() {
+				a.pick(1)
+			} else {
+				a.grabbed = !a.grabbed
+			}
+		}
+	}
+	a.draw()
+	return nil
+}
+
+// move moves the cursor, and the picked up ordering item with it.
+func (a *ArrangeView) move(delta int) {
+	next := a.cursor + delta
+	if next < 0 || next >= len(a.arrangement) {
+		return
+	}
+	if a.grabbed {
+		a.arrangement[a.cursor], a.arrangement[next] = a.arrangement[next], a.arrangement[a.cursor]
+	}
+	a.cursor = next
+}
+
+// pick cycles the match of the selected pair through the options and none.
+func (a *ArrangeView) pick(delta int) {
+	if !a.matching() || len(a.options) == 0 {
+		return
+	}
+	n := len(a.options) + 1
+	a.arrangement[a.cursor] = (a.arrangement[a.cursor]+1+delta+n)%n - 1
+}
+
+func (a *ArrangeView) done(answered bool) {
+	a.grabbed = false
+	if a.onDone != nil {
+		a.onDone(answered)
+	}
+}
+
+func (a *ArrangeView) submit() {
+	state := a.question.Arrange(append([]int{}, a.arrangement...))
+	a.showResult()
+	if a.hideCorrectness {
+		return
+	}
+	switch state {
+	case types.AnsweredTrue:
+		a.explainationView.SetText(fmt.Sprintf("[green]Correct![-]\n%s", a.question.Explanation))
+	case types.AnsweredFalse:
+		a.explainationView.SetText(fmt.Sprintf("[red]Wrong![-]\n%s", a.question.Explanation))
+	}
+}
+
+// draw renders the arrangement. Once submitted the items in place are green
+// and the others red, unless correctness is hidden.
+func (a *ArrangeView) draw() {
+	if a.question == nil {
+		return
+	}
+	submitted := a.question.GetArrangement()
+	var b strings.Builder
+	for i, picked := range a.arrangement {
+		marker := "  "
+		if i == a.cursor {
+			marker = "» "
+			if a.grabbed {
+				marker = "[yellow]↕[-] "
+			}
+		}
+		color := "white"
+		if submitted != nil && !a.hideCorrectness {
+			color = "red"
+			if a.inPlace(i, submitted[i]) {
+				color = "green"
+			}
+		}
+		if a.matching() {
+			match := "[::d]?[::-]"
+			if picked >= 0 {
+				match = tview.Escape(a.options[picked])
+			}
+			fmt.Fprintf(&b, "%s[%s]%s[-]  →  %s\n", marker, color, tview.Escape(a.question.Matching[i].Left), match)
+			continue
+		}
+		fmt.Fprintf(&b, "%s%d. [%s]%s[-]\n", marker, i+1, color, tview.Escape(a.question.Ordering[picked]))
+	}
+	if a.matching() {
+		fmt.Fprintf(&b, "\n[::d]Choose from: %s[::-]", tview.Escape(strings.Join(a.options, "  |  ")))
+	}
+	a.list.SetText(b.String())
+}
+
+// inPlace reports whether picked is correct at position i.
+func (a *ArrangeView) inPlace(i, picked int) bool {
+	if a.matching() {
+		return picked >= 0 && a.options[picked] == a.question.Matching[i].Right
+	}
+	return picked == i
+}
+
+func (a *ArrangeView) showResult() {
+	if a.question == nil {
+		return
+	}
+	a.draw()
+	help := "[::d]Enter: start arranging, Up/Down select, Space picks an item up and puts it down, Enter submits, Esc cancels[::-]"
+	if a.matching() {
+		help = "[::d]Enter: start matching, Up/Down select, Left/Right or Space pick the match, Enter submits, Esc cancels[::-]"
+	}
+	switch {
+	case !a.question.HasAnswer():
+		a.result.SetText(help)
+	case a.hideCorrectness:
+		a.result.SetText("[yellow]Answer saved[-]\n" + help)
+	case a.question.AnsweredState == types.AnsweredTrue:
+		a.result.SetText("[green]Correct![-]")
+	default:
+		arrangement := a.question.GetArrangement()
+		inPlace := 0
+		for i, picked := range arrangement {
+			if a.inPlace(i, picked) {
+				inPlace++
+			}
+		}
+		a.result.SetText(fmt.Sprintf("[red]Wrong![-] %d of %d in place, %.0f%% credit (s shows the solution)",
+			inPlace, len(arrangement), a.question.Credit()*100))
+	}
+}
+
+// ShowSolution lists the items in order or the correct pairs.
+func (a *ArrangeView) ShowSolution() {
+	if a.question == nil || !a.question.IsArranged() {
+		return
+	}
+	var b strings.Builder
+	if a.matching() {
+		b.WriteString("Solution:\n")
+		for _, pair := range a.question.Matching {
+			fmt.Fprintf(&b, "  %s  →  [green]%s[-]\n", tview.Escape(pair.Left), tview.Escape(pair.Right))
+		}
+	} else {
+		fmt.Fprintf(&b, "Solution: [green]%s[-]\n", tview.Escape(strings.Join(a.question.Ordering, "  →  ")))
+	}
+	a.result.SetText(b.String())
+}
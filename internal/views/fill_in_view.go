@@ -0,0 +1,132 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// FillInView lets the answer of a fill-in-the-blank question be typed. It
+// takes the place of the QuestionsView checkboxes, see AnswerArea. Focusing it
+// starts typing, Enter submits the answer and Esc leaves the input unchanged.
+type FillInView struct {
+	*tview.Flex
+	input            *tview.InputField
+	result           *tview.TextView
+	explainationView *tview.TextView
+	question         *types.Question
+	hideCorrectness  bool
+	onDone           func(answered bool)
+}
+
+func NewFillInView(explainationView *tview.TextView) *FillInView {
+	f := &FillInView{
+		Flex:             tview.NewFlex().SetDirection(tview.FlexRow),
+		input:            tview.NewInputField().SetLabel("Answer: "),
+		result:           tview.NewTextView().SetDynamicColors(true).SetWrap(true),
+		explainationView: explainationView,
+	}
+	f.input.SetDoneFunc(func(key tcell.Key) {
+		answered := false
+		switch key {
+		case tcell.KeyEnter:
+			f.submit()
+			answered = true
+		case tcell.KeyEscape:
+			f.input.SetText(f.question.GetTypedAnswer())
+		default:
+			return
+		}
+		if f.onDone != nil {
+			f.onDone(answered)
+		}
+	})
+	f.SetBorder(true).SetTitle("Answer")
+	f.AddItem(f.input, 1, 0, true).
+		AddItem(tview.NewBox(), 1, 0, false).
+		AddItem(f.result, 0, 1, false)
+	return f
+}
+
+// SetHideCorrectness suppresses all correct/wrong feedback, e.g. during an exam.
+func (f *FillInView) SetHideCorrectness(hide bool) *FillInView {
+	f.hideCorrectness = hide
+	f.showResult()
+	return f
+}
+
+// SetDoneFunc sets the handler called when typing ends, with answered set if
+// the answer was submitted with Enter.
+func (f *FillInView) SetDoneFunc(handler func(answered bool)) *FillInView {
+	f.onDone = handler
+	return f
+}
+
+// Typing reports whether the input has the focus, so keys are typed instead
+// of being used as shortcuts.
+func (f *FillInView) Typing() bool {
+	return f.input.HasFocus()
+}
+
+func (f *FillInView) SetQuestion(question *types.Question) {
+	f.question = question
+	f.input.SetText(question.GetTypedAnswer())
+	f.showResult()
+}
+
+func (f *FillInView) submit() {
+	state := f.question.AnswerFillIn(f.input.GetText())
+	f.showResult()
+	if f.hideCorrectness {
+		return
+	}
+	switch state {
+	case types.AnsweredTrue:
+		f.explainationView.SetText(fmt.Sprintf("[green]Correct![-]\n%s", f.question.Explanation))
+	case types.AnsweredFalse:
+		f.explainationView.SetText(fmt.Sprintf("[red]Wrong![-]\n%s", f.question.Explanation))
+	}
+}
+
+func (f *FillInView) showResult() {
+	if f.question == nil {
+		return
+	}
+	switch {
+	case strings.TrimSpace(f.question.GetTypedAnswer()) == "":
+		f.result.SetText("[::d]Enter: type the answer, Enter again submits it, Esc cancels[::-]")
+	case f.hideCorrectness:
+		f.result.SetText("[yellow]Answer saved[-]")
+	case f.question.AnsweredState == types.AnsweredTrue:
+		f.result.SetText("[green]Correct![-]")
+	default:
+		text := fmt.Sprintf("[red]Wrong![-] Accepted: %s", tview.Escape(f.question.FillIn.Solution()))
+		if feedback := f.question.FillIn.Feedback(f.question.GetTypedAnswer()); feedback != "" {
+			text += "\n[yellow]" + tview.Escape(feedback) + "[-]"
+		}
+		f.result.SetText(text)
+	}
+}
+
+// ShowSolution lists the accepted answers and patterns.
+func (f *FillInView) ShowSolution() {
+	if f.question == nil || !f.question.IsFillIn() {
+		return
+	}
+	var b strings.Builder
+	if typed := f.question.GetTypedAnswer(); typed != "" {
+		color := "red"
+		if f.question.FillIn.Accepts(typed) {
+			color = "green"
+		}
+		fmt.Fprintf(&b, "Your answer: [%s]%s[-]\n", color, tview.Escape(typed))
+	}
+	fmt.Fprintf(&b, "Accepted: [green]%s[-]\n", tview.Escape(strings.Join(f.question.FillIn.Accepted, "  |  ")))
+	if len(f.question.FillIn.Patterns) > 0 {
+		fmt.Fprintf(&b, "Patterns: %s\n", tview.Escape(strings.Join(f.question.FillIn.Patterns, "  |  ")))
+	}
+	f.result.SetText(b.String())
+}
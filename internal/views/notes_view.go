@@ -0,0 +1,77 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// NotesView shows the personal tags and note of the current question below
+// the explanation.
+type NotesView struct {
+	*tview.TextView
+}
+
+func NewNotesView() *NotesView {
+	n := &NotesView{TextView: tview.NewTextView().SetDynamicColors(true).SetWrap(true)}
+	n.SetBorder(true).SetTitle("Notes")
+	return n
+}
+
+// SetQuestion shows the tags and note of question.
+func (n *NotesView) SetQuestion(question *types.Question) {
+	text := annotationText(question)
+	if text == "" {
+		text = "[::d]No tags or note. o: edit, O: edit the note in $EDITOR[::-]"
+	}
+	n.SetText(text).ScrollToBeginning()
+}
+
+// annotationText formats the tags and note of question, or returns "" if it
+// has neither.
+func annotationText(question *types.Question) string {
+	var b strings.Builder
+	if tags := question.GetTags(); len(tags) > 0 {
+		b.WriteString("[yellow]Tags:[-] " + tview.Escape(strings.Join(tags, ", ")) + "\n")
+	}
+	if note := question.GetNote(); note != "" {
+		b.WriteString(tview.Escape(note))
+	}
+	return b.String()
+}
+
+// AnnotationForm edits the tags and note of a question. Esc cancels.
+type AnnotationForm struct {
+	*tview.Form
+	tags *tview.InputField
+	note *tview.TextArea
+}
+
+// NewAnnotationForm returns a form filled with the tags and note of question.
+// onSave is called with the entered tags and note, onCancel when the form is
+// left without saving.
+func NewAnnotationForm(question *types.Question, onSave func(tags []string, note string), onCancel func()) *AnnotationForm {
+	f := &AnnotationForm{
+		Form: tview.NewForm(),
+		tags: tview.NewInputField().SetLabel("Tags").SetText(strings.Join(question.GetTags(), ", ")).
+			SetPlaceholder("comma separated, e.g. regex, exam-trap"),
+		note: tview.NewTextArea().SetLabel("Note").SetText(question.GetNote(), false).SetSize(10, 0),
+	}
+	f.AddFormItem(f.tags).
+		AddFormItem(f.note).
+		AddButton("Save", func() { onSave(types.ParseTags(f.tags.GetText()), f.note.GetText()) }).
+		AddButton("Cancel", onCancel).
+		SetCancelFunc(onCancel)
+	f.SetBorder(true).SetTitle(" Tags and note (Ctrl-S saves, Esc cancels) ")
+	f.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Ctrl-S saves from every field, Enter adds lines to the note
+		if event.Key() == tcell.KeyCtrlS {
+			onSave(types.ParseTags(f.tags.GetText()), f.note.GetText())
+			return nil
+		}
+		return event
+	})
+	return f
+}
@@ -17,6 +17,13 @@ type QuestionsView struct {
 	questionTextView *tview.TextView
 	markerPosition   int
 	currentQuestion  *types.Question
+	hideCorrectness  bool
+}
+
+// SetHideCorrectness suppresses all correct/wrong feedback, e.g. during an exam.
+func (r *QuestionsView) SetHideCorrectness(hide bool) *QuestionsView {
+	r.hideCorrectness = hide
+	return r
 }
 
 func (r *QuestionsView) IncreaseMarkerPosition() {
@@ -193,7 +200,9 @@ func (r *QuestionsView) Draw(screen tcell.Screen) {
 			markerChar = "»" // selection marker occupies same slot as blank when not selected
 		}
 		if r.isOptionMarked(index) {
-			if option.IsCorrect {
+			if r.hideCorrectness {
+				answerStyle = "[yellow]"
+			} else if option.IsCorrect {
 				answerStyle = correctAnswerStyle
 			} else {
 				answerStyle = incorrectAnswerStyle
@@ -278,6 +287,16 @@ func (r *QuestionsView) ToggleMarkedOption(index int) types.AnsweredState {
 		}
 	}
 
+	if r.hideCorrectness {
+		if r.checkAllCorrectMarked() {
+			return types.AnsweredTrue
+		}
+		if len(r.GetCurrentOptions()) > 0 {
+			return types.AnsweredFalse
+		}
+		return types.AnsweredUnknown
+	}
+
 	if r.checkAllCorrectMarked() {
 		r.explainationView.SetText(fmt.Sprintf("[green]Correct![-]\n%s", r.currentQuestion.Explanation))
 		return types.AnsweredTrue
@@ -291,15 +310,10 @@ func (r *QuestionsView) ToggleMarkedOption(index int) types.AnsweredState {
 	return types.AnsweredUnknown
 }
 
+// checkAllCorrectMarked grades the current question the same way as
+// statistics and exams do, see types.Question.IsAnsweredCorrectly.
 func (r *QuestionsView) checkAllCorrectMarked() bool {
-	for _, answer := range r.currentQuestion.Answers {
-		if answer.IsCorrect && !answer.GetIsMarked() {
-			return false
-		} else if !answer.IsCorrect && answer.GetIsMarked() {
-			return false
-		}
-	}
-	return true
+	return r.currentQuestion.IsAnsweredCorrectly()
 }
 
 // MouseHandler returns the mouse handler for this primitive.
@@ -195,15 +195,10 @@ func (r *QuestionsViewModern) ToggleMarkedOption(index int) types.AnsweredState
 	return types.AnsweredUnknown
 }
 
+// checkAllCorrectMarked grades the current question the same way as
+// statistics and exams do, see types.Question.IsAnsweredCorrectly.
 func (r *QuestionsViewModern) checkAllCorrectMarked() bool {
-	for _, answer := range r.currentQuestion.Answers {
-		if answer.IsCorrect && !answer.GetIsMarked() {
-			return false
-		} else if !answer.IsCorrect && answer.GetIsMarked() {
-			return false
-		}
-	}
-	return true
+	return r.currentQuestion.IsAnsweredCorrectly()
 }
 
 // MouseHandler returns the mouse handler for this primitive.
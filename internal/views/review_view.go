@@ -0,0 +1,223 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ReviewView lists every question of a session that was answered wrong or
+// left unanswered. The selected question is shown with the picked and the
+// correct options side by side plus its explanation.
+// n/right and p/left move like in the main view, Enter jumps to the question
+// and q/Esc closes the review.
+type ReviewView struct {
+	*tview.Flex
+	list      *tview.List
+	details   *tview.TextView
+	questions []*types.Question
+	entries   []int // indexes into questions
+	onJump    func(index int)
+	onClose   func()
+}
+
+func NewReviewView(questions []*types.Question) *ReviewView {
+	r := &ReviewView{
+		Flex:      tview.NewFlex(),
+		list:      tview.NewList().ShowSecondaryText(false).SetHighlightFullLine(true),
+		details:   tview.NewTextView().SetDynamicColors(true).SetWrap(true),
+		questions: questions,
+	}
+	r.list.SetBorder(true)
+	r.details.SetBorder(true).SetTitle("Details")
+
+	r.list.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		r.showDetails(index)
+	})
+	r.list.SetSelectedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		if r.onJump != nil && index < len(r.entries) {
+			r.onJump(r.entries[index])
+		}
+	})
+	r.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			r.close()
+			return nil
+		case tcell.KeyRight:
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case tcell.KeyLeft:
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'q':
+				r.close()
+				return nil
+			case 'n':
+				return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+			case 'p':
+				return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+			}
+		}
+		return event
+	})
+
+	r.AddItem(r.list, 0, 1, true).
+		AddItem(r.details, 0, 2, false)
+	r.Refresh()
+	return r
+}
+
+// SetJumpFunc sets the handler called with the session index of the question
+// selected with Enter.
+func (r *ReviewView) SetJumpFunc(handler func(index int)) *ReviewView {
+	r.onJump = handler
+	return r
+}
+
+// SetCloseFunc sets the handler called when the review is left with q/Esc.
+func (r *ReviewView) SetCloseFunc(handler func()) *ReviewView {
+	r.onClose = handler
+	return r
+}
+
+// Len returns the number of questions under review.
+func (r *ReviewView) Len() int {
+	return len(r.entries)
+}
+
+// Refresh rebuilds the list from the current answered state of the questions.
+func (r *ReviewView) Refresh() {
+	r.list.Clear()
+	r.entries = reviewEntries(r.questions)
+	for _, index := range r.entries {
+		question := r.questions[index]
+		r.list.AddItem(fmt.Sprintf("%3d %s %s", index+1, reviewStateLabel(question), tview.Escape(question.Text)), "", 0, nil)
+	}
+	r.list.SetTitle(fmt.Sprintf("Review (%d)", len(r.entries)))
+	if len(r.entries) == 0 {
+		r.details.SetText("[green]Nothing to review, every question was answered correctly.[-]")
+		return
+	}
+	r.showDetails(0)
+}
+
+// reviewEntries returns the indexes of the questions to review: those answered
+// wrong and those left unanswered, in session order.
+func reviewEntries(questions []*types.Question) []int {
+	entries := []int{}
+	for index, question := range questions {
+		if question.AnsweredState != types.AnsweredTrue {
+			entries = append(entries, index)
+		}
+	}
+	return entries
+}
+
+func (r *ReviewView) close() {
+	if r.onClose != nil {
+		r.onClose()
+	}
+}
+
+func (r *ReviewView) showDetails(index int) {
+	if index < 0 || index >= len(r.entries) {
+		return
+	}
+	question := r.questions[r.entries[index]]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow::b]Question %d[-:-:-] (ID %d)  %s\n\n", r.entries[index]+1, question.ID, reviewStateLabel(question))
+	fmt.Fprintf(&b, "%s\n\n", tview.Escape(question.Text))
+	if !question.IsFillIn() && !question.IsArranged() {
+		b.WriteString("[::d]picked  correct[::-]\n")
+	}
+	if question.IsArranged() {
+		writeArrangement(&b, question)
+	}
+	for _, answer := range question.Answers {
+		picked := "  "
+		if answer.GetIsMarked() {
+			picked = "[yellow]●[-] "
+			if !answer.IsCorrect {
+				picked = "[red]●[-] "
+			}
+		}
+		correct := "  "
+		if answer.IsCorrect {
+			correct = "[green]✔[-] "
+		}
+		fmt.Fprintf(&b, "  %s      %s     %s\n", picked, correct, tview.Escape(answer.Text))
+	}
+	if question.IsFillIn() {
+		typed := "[::d]nothing typed[::-]"
+		if question.HasAnswer() {
+			color := "red"
+			if question.IsAnsweredCorrectly() {
+				color = "green"
+			}
+			typed = fmt.Sprintf("[%s]%s[-]", color, tview.Escape(question.GetTypedAnswer()))
+		}
+		fmt.Fprintf(&b, "  typed:    %s\n", typed)
+		if feedback := question.FillIn.Feedback(question.GetTypedAnswer()); feedback != "" {
+			fmt.Fprintf(&b, "            [yellow]%s[-]\n", tview.Escape(feedback))
+		}
+		fmt.Fprintf(&b, "  accepted: [green]%s[-]\n", tview.Escape(strings.Join(question.FillIn.Accepted, "  |  ")))
+	}
+	b.WriteString("\n[::b]Explanation[::-]\n")
+	if question.Explanation != "" {
+		b.WriteString(tview.Escape(question.Explanation))
+	} else {
+		b.WriteString("[red]No explanation available[-]")
+	}
+	if notes := annotationText(question); notes != "" {
+		b.WriteString("\n\n[::b]Your notes[::-]\n" + notes)
+	}
+	r.details.SetText(b.String()).ScrollToBeginning()
+}
+
+// writeArrangement lists the arranged items of an ordering or matching
+// question next to the correct ones.
+func writeArrangement(b *strings.Builder, question *types.Question) {
+	arrangement := question.GetArrangement()
+	if arrangement == nil {
+		b.WriteString("  [::d]not arranged[::-]\n")
+	}
+	options := question.MatchOptions()
+	solution := question.SolutionTexts()
+	for i, want := range solution {
+		got := "[::d]?[::-]"
+		if arrangement != nil {
+			color := "red"
+			text := "?"
+			switch {
+			case question.Kind == types.QuestionKindOrdering:
+				text = question.Ordering[arrangement[i]]
+			case arrangement[i] >= 0:
+				text = options[arrangement[i]]
+			}
+			if question.Kind == types.QuestionKindOrdering && arrangement[i] == i ||
+				question.Kind == types.QuestionKindMatching && text == question.Matching[i].Right {
+				color = "green"
+			}
+			if question.Kind == types.QuestionKindMatching {
+				text = question.Matching[i].Left + " → " + text
+			}
+			got = fmt.Sprintf("[%s]%s[-]", color, tview.Escape(text))
+		}
+		fmt.Fprintf(b, "  %d. %s\n     [::d]correct:[::-] [green]%s[-]\n", i+1, got, tview.Escape(want))
+	}
+	if arrangement != nil {
+		fmt.Fprintf(b, "  [::d]credit %.0f%%[::-]\n", question.Credit()*100)
+	}
+}
+
+func reviewStateLabel(question *types.Question) string {
+	if question.AnsweredState == types.AnsweredFalse {
+		return "[red]wrong[-]  "
+	}
+	return "[white]skipped[-]"
+}
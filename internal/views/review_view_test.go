@@ -0,0 +1,116 @@
+package views
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SqiSch/lpic-cli/internal/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// reviewSession returns five questions: 0 and 3 answered correctly, 1 and 4
+// wrong and 2 skipped.
+func reviewSession() []*types.Question {
+	states := []types.AnsweredState{types.AnsweredTrue, types.AnsweredFalse, types.AnsweredUnknown, types.AnsweredTrue, types.AnsweredFalse}
+	questions := make([]*types.Question, len(states))
+	for i, state := range states {
+		questions[i] = &types.Question{ID: 100 + i, Text: "question", Answers: []*types.Answer{{AnswerID: "a", IsCorrect: true}}}
+		questions[i].SetAnsweredState(state)
+	}
+	return questions
+}
+
+func TestReviewEntries(t *testing.T) {
+	if got, want := reviewEntries(reviewSession()), []int{1, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reviewEntries() = %v, want %v", got, want)
+	}
+	if got := reviewEntries(reviewSession()[:1]); len(got) != 0 {
+		t.Errorf("reviewEntries() = %v for a correct session, want none", got)
+	}
+}
+
+// press sends key presses to the list of r, the way the application does.
+func press(r *ReviewView, events ...*tcell.EventKey) {
+	for _, event := range events {
+		r.list.InputHandler()(event, func(tview.Primitive) {})
+	}
+}
+
+func key(k tcell.Key) *tcell.EventKey {
+	return tcell.NewEventKey(k, 0, tcell.ModNone)
+}
+
+func char(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestReviewViewNavigation(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []*tcell.EventKey
+		want int // session index jumped to with Enter
+	}{
+		{"first entry", nil, 1},
+		{"n", []*tcell.EventKey{char('n')}, 2},
+		{"right", []*tcell.EventKey{key(tcell.KeyRight), key(tcell.KeyRight)}, 4},
+		{"p", []*tcell.EventKey{char('n'), char('n'), char('p')}, 2},
+		{"left", []*tcell.EventKey{key(tcell.KeyRight), key(tcell.KeyLeft)}, 1},
+		{"wraps around", []*tcell.EventKey{char('n'), char('n'), char('n')}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jumped := -1
+			r := NewReviewView(reviewSession()).SetJumpFunc(func(index int) {
+				jumped = index
+			})
+			press(r, tt.keys...)
+			press(r, key(tcell.KeyEnter))
+			if jumped != tt.want {
+				t.Errorf("jumped to %d, want %d", jumped, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewViewClose(t *testing.T) {
+	for _, event := range []*tcell.EventKey{char('q'), key(tcell.KeyEscape)} {
+		closed := false
+		r := NewReviewView(reviewSession()).SetCloseFunc(func() {
+			closed = true
+		})
+		press(r, event)
+		if !closed {
+			t.Errorf("%s did not close the review", event.Name())
+		}
+	}
+}
+
+func TestReviewViewRefresh(t *testing.T) {
+	questions := reviewSession()
+	r := NewReviewView(questions)
+	if r.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", r.Len())
+	}
+
+	// answering a reviewed question correctly removes it from the review
+	questions[2].SetAnsweredState(types.AnsweredTrue)
+	r.Refresh()
+	jumped := -1
+	r.SetJumpFunc(func(index int) {
+		jumped = index
+	})
+	press(r, char('n'), key(tcell.KeyEnter))
+	if r.Len() != 2 || jumped != 4 {
+		t.Errorf("after answering question 3: Len() = %d and jumped to %d, want 2 and 4", r.Len(), jumped)
+	}
+
+	for _, question := range questions {
+		question.SetAnsweredState(types.AnsweredTrue)
+	}
+	r.Refresh()
+	press(r, key(tcell.KeyEnter))
+	if r.Len() != 0 || jumped != 4 {
+		t.Errorf("nothing to review: Len() = %d and jumped to %d, want 0 and no jump", r.Len(), jumped)
+	}
+}
@@ -0,0 +1,147 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SqiSch/lpic-cli/internal/search"
+	"github.com/SqiSch/lpic-cli/internal/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// searchResultLimit is the number of results listed, the total is shown in
+// the title.
+const searchResultLimit = 100
+
+// SearchView searches the questions of all testsets of a certification while
+// the query is typed. The results are listed best first with the matches
+// highlighted, the selected one is shown in full next to the list.
+// Up/down select a result, Enter jumps to it and Esc closes the search.
+type SearchView struct {
+	*tview.Flex
+	input   *tview.InputField
+	list    *tview.List
+	details *tview.TextView
+	index   *search.Index
+	terms   []string
+	results []search.Result
+	onJump  func(question *types.Question)
+	onClose func()
+}
+
+func NewSearchView(index *search.Index) *SearchView {
+	s := &SearchView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		input:   tview.NewInputField().SetLabel("/").SetPlaceholder("words in the question, answers or explanation"),
+		list:    tview.NewList().ShowSecondaryText(false).SetHighlightFullLine(true),
+		details: tview.NewTextView().SetDynamicColors(true).SetWrap(true),
+		index:   index,
+	}
+	s.input.SetBorder(true).SetTitle("Search")
+	s.list.SetBorder(true).SetTitle("Results")
+	s.details.SetBorder(true).SetTitle("Details")
+
+	s.input.SetChangedFunc(s.update)
+	s.list.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		s.showDetails(index)
+	})
+	// the query keeps the focus, the keys moving through the results are
+	// passed on to the list
+	s.input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			if s.onClose != nil {
+				s.onClose()
+			}
+			return nil
+		case tcell.KeyEnter:
+			if s.onJump != nil && len(s.results) > 0 {
+				s.onJump(s.results[s.list.GetCurrentItem()].Question)
+			}
+			return nil
+		case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn:
+			s.list.InputHandler()(event, nil)
+			return nil
+		}
+		return event
+	})
+
+	s.AddItem(s.input, 3, 0, true).
+		AddItem(tview.NewFlex().
+			AddItem(s.list, 0, 1, false).
+			AddItem(s.details, 0, 2, false), 0, 1, false)
+	s.update("")
+	return s
+}
+
+// SetJumpFunc sets the handler called with the question selected with Enter.
+func (s *SearchView) SetJumpFunc(handler func(question *types.Question)) *SearchView {
+	s.onJump = handler
+	return s
+}
+
+// SetCloseFunc sets the handler called when the search is left with Esc.
+func (s *SearchView) SetCloseFunc(handler func()) *SearchView {
+	s.onClose = handler
+	return s
+}
+
+// update searches for query and lists the results.
+func (s *SearchView) update(query string) {
+	s.terms = search.Terms(query)
+	s.results = s.index.Search(query)
+	total := len(s.results)
+	if len(s.results) > searchResultLimit {
+		s.results = s.results[:searchResultLimit]
+	}
+
+	s.list.Clear()
+	for _, result := range s.results {
+		excerpt := search.Excerpt(result.Question.Text, s.terms, 80)
+		s.list.AddItem(fmt.Sprintf("%5d %s", result.Question.ID, s.highlight(excerpt)), "", 0, nil)
+	}
+	s.list.SetTitle(fmt.Sprintf("Results (%d)", total))
+	switch {
+	case len(s.terms) == 0:
+		s.details.SetText("[::d]Type to search the questions, answers and explanations of all test sets.[::-]")
+	case total == 0:
+		s.details.SetText("[red]No question contains all of the words.[-]")
+	default:
+		s.showDetails(0)
+	}
+}
+
+func (s *SearchView) highlight(text string) string {
+	return search.Highlight(text, s.terms, func(match string) string {
+		return "[black:yellow]" + tview.Escape(match) + "[-:-]"
+	}, tview.Escape)
+}
+
+func (s *SearchView) showDetails(index int) {
+	if index < 0 || index >= len(s.results) {
+		return
+	}
+	result := s.results[index]
+	question := result.Question
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow::b]Question %d[-:-:-]  [::d]%s[::-]\n\n", question.ID, tview.Escape(strings.Join(result.Testsets, ", ")))
+	fmt.Fprintf(&b, "%s\n\n", s.highlight(question.Text))
+	for _, answer := range question.Answers {
+		correct := "  "
+		if answer.IsCorrect {
+			correct = "[green]✔[-] "
+		}
+		fmt.Fprintf(&b, "  %s %s\n", correct, s.highlight(answer.Text))
+	}
+	if question.IsFillIn() || question.IsArranged() {
+		for _, solution := range question.SolutionTexts() {
+			fmt.Fprintf(&b, "  [green]✔[-] %s\n", s.highlight(solution))
+		}
+	}
+	if question.Explanation != "" {
+		b.WriteString("\n[::b]Explanation[::-]\n" + s.highlight(question.Explanation))
+	}
+	s.details.SetText(b.String()).ScrollToBeginning()
+}
@@ -16,27 +16,21 @@ func QuestionStateOverview(certSet []*types.Question, tv *tview.TextView, curren
 		return
 	}
 
-	correct := 0
-	incorrect := 0
-	unknown := 0
-	for _, q := range certSet {
-		switch q.AnsweredState {
-		case types.AnsweredTrue:
-			correct++
-		case types.AnsweredFalse:
-			incorrect++
-		default:
-			unknown++
-		}
-	}
+	// near-duplicates marked by -groupDuplicates count as one question
+	correct, incorrect, unknown := types.CountItemStates(certSet)
+	items := correct + incorrect + unknown
 
 	answered := correct + incorrect
-	pct := func(v int) float64 { return (float64(v) / float64(total)) * 100 }
+	pct := func(v int) float64 { return (float64(v) / float64(items)) * 100 }
 
 	// currentIndex is zero-based; shown as one-based
 	header := fmt.Sprintf("[yellow::b]%d / %d[-]", currentIndex+1, total)
 	stats := fmt.Sprintf("Answered: %d (%.0f%%)\n[green]Correct[-]: %d ( %.0f%% )\n[red]Incorrect[-]: %d ( %.0f%% )\n[white]Unknown[-]: %d ( %.0f%% )",
 		answered, pct(answered), correct, pct(correct), incorrect, pct(incorrect), unknown, pct(unknown))
 
+	if items < total {
+		stats += fmt.Sprintf("\n[::d]%d duplicates counted once[::-]", total-items)
+	}
+
 	tv.SetText(header + "\n\n" + stats).SetDynamicColors(true)
 }